@@ -1,6 +1,11 @@
 package gengo
 
-import "github.com/trinchan/gengo/lang"
+import (
+	"encoding/json"
+
+	"github.com/trinchan/gengo/currency"
+	"github.com/trinchan/gengo/lang"
+)
 
 const (
 	accountNamespace = "/account"
@@ -8,12 +13,25 @@ const (
 
 // AccountStatsResponse defines the response for the AccountStatus() endpoint.
 type AccountStatsResponse struct {
-	CreditsSpent Float64 `json:"credits_spent"`
-	Processing   Float64 `json:"processing"`
-	UserSince    Time    `json:"user_since"`
-	Currency     string  `json:"currency"`
-	BillingType  string  `json:"billing_type"`
-	CustomerType string  `json:"customer_type"`
+	CreditsSpent currency.Money `json:"credits_spent"`
+	Processing   currency.Money `json:"processing"`
+	UserSince    Time           `json:"user_since"`
+	Currency     currency.Code  `json:"currency"`
+	BillingType  string         `json:"billing_type"`
+	CustomerType string         `json:"customer_type"`
+}
+
+// UnmarshalJSON implements the Unmarshaler interface so that CreditsSpent and Processing carry the response's currency.
+func (a *AccountStatsResponse) UnmarshalJSON(d []byte) error {
+	type asr AccountStatsResponse
+	x := new(asr)
+	if err := json.Unmarshal(d, x); err != nil {
+		return err
+	}
+	*a = AccountStatsResponse(*x)
+	a.CreditsSpent = a.CreditsSpent.In(a.Currency)
+	a.Processing = a.Processing.In(a.Currency)
+	return nil
 }
 
 // AccountStats retrieves account stats, such as orders made.
@@ -40,8 +58,20 @@ func (c *Client) Me() (*MeResponse, error) {
 
 // BalanceResponse defines the response for the Balance() endpoint.
 type BalanceResponse struct {
-	Credits  Float64 `json:"credits"`
-	Currency string  `json:"currency"`
+	Credits  currency.Money `json:"credits"`
+	Currency currency.Code  `json:"currency"`
+}
+
+// UnmarshalJSON implements the Unmarshaler interface so that Credits carries the response's currency.
+func (b *BalanceResponse) UnmarshalJSON(d []byte) error {
+	type br BalanceResponse
+	x := new(br)
+	if err := json.Unmarshal(d, x); err != nil {
+		return err
+	}
+	*b = BalanceResponse(*x)
+	b.Credits = b.Credits.In(b.Currency)
+	return nil
 }
 
 // Balance retrieves account balance in credits.
@@ -66,6 +96,6 @@ type PreferredTranslatorResponse struct {
 // PreferredTranslators retrieves preferred translators set by user.
 func (c *Client) PreferredTranslators() (*PreferredTranslatorsResponse, error) {
 	ptr := []PreferredTranslatorResponse{}
-	err := c.get(accountNamespace+"/preferred_translators", nil, ptr)
+	err := c.get(accountNamespace+"/preferred_translators", nil, &ptr)
 	return &PreferredTranslatorsResponse{PreferredTranslators: ptr}, err
 }
@@ -12,7 +12,7 @@ func ExampleClient_AccountStats() {
 		fmt.Printf("Error retrieving account stats: %v\n", err)
 	}
 	fmt.Printf("User since: %s\n", r.UserSince)
-	fmt.Printf("Credits spent: %0.2f %s\n", r.CreditsSpent, r.Currency)
+	fmt.Printf("Credits spent: %s\n", r.CreditsSpent)
 }
 
 func ExampleClient_Balance() {
@@ -21,7 +21,7 @@ func ExampleClient_Balance() {
 	if err != nil {
 		fmt.Printf("Error retrieving account balance: %v\n", err)
 	}
-	log.Printf("Balance: %0.2f %s", r.Credits, r.Currency)
+	log.Printf("Balance: %s", r.Credits)
 }
 
 func ExampleClient_PreferredTranslators() {
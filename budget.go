@@ -0,0 +1,295 @@
+package gengo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/trinchan/gengo/currency"
+)
+
+const (
+	// BudgetPerCall limits the cost of a single PostJobs() call.
+	BudgetPerCall = "per-call"
+	// BudgetDaily limits spending over the last 24 hours.
+	BudgetDaily = "daily"
+	// BudgetMonthly limits spending over the last 30 days.
+	BudgetMonthly = "monthly"
+	// BudgetBalance stops orders the account balance cannot pay for.
+	BudgetBalance = "balance"
+)
+
+// BudgetExceededError is returned by PostJobs() when a BudgetGuard refuses to submit jobs.
+type BudgetExceededError struct {
+	// Limit is one of the Budget constants.
+	Limit     string
+	Allowed   currency.Money
+	Spent     currency.Money
+	Requested currency.Money
+}
+
+func (e *BudgetExceededError) Error() string {
+	if e.Limit == BudgetPerCall || e.Limit == BudgetBalance {
+		return fmt.Sprintf("gengo: %s budget exceeded: jobs cost %s, limit is %s", e.Limit, e.Requested, e.Allowed)
+	}
+	return fmt.Sprintf("gengo: %s budget exceeded: jobs cost %s, %s of %s already spent", e.Limit, e.Requested, e.Spent, e.Allowed)
+}
+
+// Spend records credits spent on an order.
+type Spend struct {
+	Time     time.Time      `json:"time"`
+	OrderID  int            `json:"order_id"`
+	Credits  currency.Money `json:"credits"`
+	Currency currency.Code  `json:"currency"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *Spend) UnmarshalJSON(d []byte) error {
+	type spend Spend
+	if err := json.Unmarshal(d, (*spend)(s)); err != nil {
+		return err
+	}
+	s.Credits = s.Credits.In(s.Currency)
+	return nil
+}
+
+// Ledger stores the spending a BudgetGuard checks rolling limits against.
+type Ledger interface {
+	Record(s Spend) error
+	Spent(since time.Time) (currency.Money, error)
+}
+
+// MemoryLedger is a Ledger which only lasts as long as the process.
+type MemoryLedger struct {
+	mu     sync.Mutex
+	spends []Spend
+}
+
+// Record implements Ledger.
+func (l *MemoryLedger) Record(s Spend) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.spends = append(l.spends, s)
+	return nil
+}
+
+// Spent implements Ledger.
+func (l *MemoryLedger) Spent(since time.Time) (currency.Money, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return sumSpends(l.spends, since)
+}
+
+// FileLedger is a Ledger persisted as one JSON object per line in a file, so that limits hold across runs.
+type FileLedger struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileLedger creates a new FileLedger stored at path. The file is created on the first Record.
+func NewFileLedger(path string) *FileLedger {
+	return &FileLedger{Path: path}
+}
+
+// Record implements Ledger.
+func (l *FileLedger) Record(s Spend) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+// Spent implements Ledger.
+func (l *FileLedger) Spent(since time.Time) (currency.Money, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.Open(l.Path)
+	if os.IsNotExist(err) {
+		return currency.Money{}, nil
+	}
+	if err != nil {
+		return currency.Money{}, err
+	}
+	defer f.Close()
+	var spends []Spend
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s Spend
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return currency.Money{}, fmt.Errorf("reading ledger %s line %d: %v", l.Path, line, err)
+		}
+		spends = append(spends, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return currency.Money{}, err
+	}
+	return sumSpends(spends, since)
+}
+
+func sumSpends(spends []Spend, since time.Time) (currency.Money, error) {
+	var credits []currency.Money
+	for _, s := range spends {
+		if !s.Time.Before(since) {
+			credits = append(credits, s.Credits)
+		}
+	}
+	return currency.Sum(credits...)
+}
+
+// BudgetGuard quotes jobs before PostJobs() submits them and refuses orders which would exceed its limits.
+// A zero limit is not enforced.
+type BudgetGuard struct {
+	PerCall      currency.Money
+	Daily        currency.Money
+	Monthly      currency.Money
+	CheckBalance bool
+	Ledger       Ledger
+
+	now func() time.Time
+	mu  sync.Mutex
+}
+
+// BudgetOption configures a BudgetGuard.
+type BudgetOption func(*BudgetGuard)
+
+// WithPerCallLimit limits the cost of each PostJobs() call.
+func WithPerCallLimit(m currency.Money) BudgetOption {
+	return func(g *BudgetGuard) {
+		g.PerCall = m
+	}
+}
+
+// WithDailyLimit limits spending over the last 24 hours.
+func WithDailyLimit(m currency.Money) BudgetOption {
+	return func(g *BudgetGuard) {
+		g.Daily = m
+	}
+}
+
+// WithMonthlyLimit limits spending over the last 30 days.
+func WithMonthlyLimit(m currency.Money) BudgetOption {
+	return func(g *BudgetGuard) {
+		g.Monthly = m
+	}
+}
+
+// WithBalanceCheck refuses orders costing more than the account balance.
+func WithBalanceCheck(b bool) BudgetOption {
+	return func(g *BudgetGuard) {
+		g.CheckBalance = b
+	}
+}
+
+// NewBudgetGuard creates a new BudgetGuard recording spending in the ledger.
+func NewBudgetGuard(ledger Ledger, options ...BudgetOption) *BudgetGuard {
+	g := &BudgetGuard{
+		Ledger: ledger,
+		now:    time.Now,
+	}
+	for _, option := range options {
+		option(g)
+	}
+	return g
+}
+
+// SetBudgetGuard makes PostJobs() check every order against the guard's limits. A nil guard removes the check.
+func (c *Client) SetBudgetGuard(g *BudgetGuard) {
+	c.budget = g
+}
+
+// post checks the order against every limit, submits it and records what it cost.
+// Submissions are serialized so that concurrent orders cannot overrun a limit together.
+func (g *BudgetGuard) post(c *Client, req *PostJobsRequest, submit func() (*PostJobsResponse, error)) (*PostJobsResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	quote, err := c.QuoteText(NewQuoteTextRequest(req.Jobs...))
+	if err != nil {
+		return nil, fmt.Errorf("quoting jobs for budget check: %w", err)
+	}
+	cost, err := quote.Total()
+	if err != nil {
+		return nil, fmt.Errorf("quoting jobs for budget check: %w", err)
+	}
+	if err := g.check(c, cost); err != nil {
+		return nil, err
+	}
+	pjr, err := submit()
+	if err != nil {
+		return pjr, err
+	}
+	spend := Spend{Time: g.now(), OrderID: pjr.OrderID, Credits: pjr.CreditsUsed, Currency: pjr.Currency}
+	if spend.Credits.IsZero() {
+		spend.Credits, spend.Currency = cost, cost.Currency
+	}
+	if err := g.Ledger.Record(spend); err != nil {
+		return pjr, fmt.Errorf("jobs were submitted in order %d but could not be recorded in the ledger: %w", pjr.OrderID, err)
+	}
+	return pjr, nil
+}
+
+func (g *BudgetGuard) check(c *Client, cost currency.Money) error {
+	if exceeds(cost, g.PerCall) {
+		return &BudgetExceededError{Limit: BudgetPerCall, Allowed: g.PerCall, Requested: cost}
+	}
+	windows := []struct {
+		limit  string
+		amount currency.Money
+		since  time.Duration
+	}{
+		{BudgetDaily, g.Daily, 24 * time.Hour},
+		{BudgetMonthly, g.Monthly, 30 * 24 * time.Hour},
+	}
+	for _, w := range windows {
+		if w.amount.Sign() <= 0 {
+			continue
+		}
+		spent, err := g.Ledger.Spent(g.now().Add(-w.since))
+		if err != nil {
+			return err
+		}
+		total, err := spent.Add(cost)
+		if err != nil {
+			return fmt.Errorf("checking %s budget: %w", w.limit, err)
+		}
+		if exceeds(total, w.amount) {
+			return &BudgetExceededError{Limit: w.limit, Allowed: w.amount, Spent: spent, Requested: cost}
+		}
+	}
+	if g.CheckBalance {
+		br, err := c.Balance()
+		if err != nil {
+			return fmt.Errorf("retrieving balance for budget check: %w", err)
+		}
+		if n, err := cost.Cmp(br.Credits); err != nil || n > 0 {
+			return &BudgetExceededError{Limit: BudgetBalance, Allowed: br.Credits, Requested: cost}
+		}
+	}
+	return nil
+}
+
+// exceeds reports whether m is over a positive limit. Amounts in another currency than the limit always exceed it,
+// since they cannot be compared without a conversion rate.
+func exceeds(m, limit currency.Money) bool {
+	if limit.Sign() <= 0 {
+		return false
+	}
+	n, err := m.Cmp(limit)
+	return err != nil || n > 0
+}
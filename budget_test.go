@@ -0,0 +1,104 @@
+package gengo
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo/currency"
+	"github.com/trinchan/gengo/internal/gengotest"
+	"github.com/trinchan/gengo/lang"
+)
+
+func usd(s string) currency.Money {
+	return currency.MustParse(s, currency.USD)
+}
+
+func newBudgetClient(g *BudgetGuard) (*Client, *gengotest.API) {
+	c, api := newFakeClient()
+	api.Handle("POST /translate/service/quote", `{"jobs":[{"credits":"3.00","currency":"USD"},{"credits":"2.50","currency":"USD"}]}`)
+	api.Handle("POST /translate/jobs", `{"order_id":42,"job_count":2,"credits_used":"5.50","currency":"USD"}`)
+	api.Handle("GET /account/balance", `{"credits":"100.00","currency":"USD"}`)
+	c.SetBudgetGuard(g)
+	return c, api
+}
+
+func budgetJobs() *PostJobsRequest {
+	pair := lang.NewPair(lang.English, lang.Japanese)
+	return NewPostJobsRequest([]*JobRequest{
+		NewJobRequest("one", pair, TierStandard),
+		NewJobRequest("two", pair, TierStandard),
+	})
+}
+
+func TestBudgetGuardRecordsSpend(t *testing.T) {
+	now := time.Date(2020, 1, 15, 12, 0, 0, 0, time.UTC)
+	ledger := NewFileLedger(filepath.Join(t.TempDir(), "ledger.jsonl"))
+	g := NewBudgetGuard(ledger, WithPerCallLimit(usd("10")), WithDailyLimit(usd("20")), WithBalanceCheck(true))
+	g.now = func() time.Time { return now }
+	c, _ := newBudgetClient(g)
+	r, err := c.PostJobs(budgetJobs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OrderID != 42 {
+		t.Errorf("unexpected response %+v", r)
+	}
+	spent, err := ledger.Spent(now.Add(-time.Hour))
+	if err != nil || spent != usd("5.50") {
+		t.Errorf("Spent() = %v, %v, want 5.50", spent, err)
+	}
+}
+
+func TestBudgetGuardLimits(t *testing.T) {
+	now := time.Date(2020, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		options []BudgetOption
+		history []Spend
+		limit   string
+	}{
+		{"per call", []BudgetOption{WithPerCallLimit(usd("5"))}, nil, BudgetPerCall},
+		{"daily", []BudgetOption{WithDailyLimit(usd("10"))}, []Spend{{Time: now.Add(-time.Hour), Credits: usd("5")}}, BudgetDaily},
+		{"daily window", []BudgetOption{WithDailyLimit(usd("10"))}, []Spend{{Time: now.Add(-25 * time.Hour), Credits: usd("5")}}, ""},
+		{"monthly", []BudgetOption{WithDailyLimit(usd("10")), WithMonthlyLimit(usd("50"))}, []Spend{{Time: now.Add(-10 * 24 * time.Hour), Credits: usd("45")}}, BudgetMonthly},
+		{"balance", []BudgetOption{WithBalanceCheck(true)}, []Spend{}, ""},
+	}
+	for _, tt := range tests {
+		ledger := new(MemoryLedger)
+		for _, s := range tt.history {
+			ledger.Record(s)
+		}
+		g := NewBudgetGuard(ledger, tt.options...)
+		g.now = func() time.Time { return now }
+		c, api := newBudgetClient(g)
+		_, err := c.PostJobs(budgetJobs())
+		var be *BudgetExceededError
+		if tt.limit == "" {
+			if err != nil {
+				t.Errorf("%s: PostJobs() = %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.As(err, &be) || be.Limit != tt.limit || be.Requested != usd("5.50") {
+			t.Errorf("%s: PostJobs() = %v, want %s budget error", tt.name, err, tt.limit)
+		}
+		for _, req := range api.Requests() {
+			if req.Method == "POST" && req.URL.Path == "/v2/translate/jobs" {
+				t.Errorf("%s: jobs were submitted despite the budget", tt.name)
+			}
+		}
+	}
+}
+
+func TestBudgetGuardBalance(t *testing.T) {
+	g := NewBudgetGuard(new(MemoryLedger), WithBalanceCheck(true))
+	c, api := newBudgetClient(g)
+	api.Handle("GET /account/balance", `{"credits":"5.00","currency":"USD"}`)
+	_, err := c.PostJobs(budgetJobs())
+	var be *BudgetExceededError
+	if !errors.As(err, &be) || be.Limit != BudgetBalance || be.Allowed != usd("5") {
+		t.Errorf("PostJobs() = %v, want balance budget error", err)
+	}
+}
@@ -0,0 +1,276 @@
+package gengo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/trinchan/gengo/currency"
+	"github.com/trinchan/gengo/lang"
+)
+
+// DefaultCatalogTTL is how long a Catalog uses language pairs before retrieving them again.
+const DefaultCatalogTTL = 24 * time.Hour
+
+// ErrPairUnavailable is returned when Gengo does not translate a language pair at a tier.
+var ErrPairUnavailable = errors.New("gengo: language pair is not available at this tier")
+
+type pairTier struct {
+	lang.Pair
+	Tier
+}
+
+// catalogSnapshot is the data a Catalog retrieves, as cached on disk.
+type catalogSnapshot struct {
+	FetchedAt     time.Time               `json:"fetched_at"`
+	LanguagePairs []LanguagePairWithPrice `json:"language_pairs"`
+	Languages     []Language              `json:"languages"`
+}
+
+// Catalog caches the language pairs and languages supported by Gengo so that they can be looked up without an API call.
+// It is safe for concurrent use.
+type Catalog struct {
+	client        *Client
+	ttl           time.Duration
+	cacheFile     string
+	staleFallback bool
+	now           func() time.Time
+
+	mu        sync.Mutex
+	fetchedAt time.Time
+	pairs     map[pairTier]LanguagePairWithPrice
+	tiers     map[lang.Pair][]Tier
+	targets   map[lang.Code][]lang.Code
+	languages map[lang.Code]Language
+}
+
+// CatalogOption configures a Catalog.
+type CatalogOption func(*Catalog)
+
+// WithCatalogTTL sets how long retrieved language pairs are used before being retrieved again.
+func WithCatalogTTL(d time.Duration) CatalogOption {
+	return func(c *Catalog) {
+		c.ttl = d
+	}
+}
+
+// WithCatalogCacheFile caches retrieved language pairs in a file, so that they are shared between processes.
+func WithCatalogCacheFile(path string) CatalogOption {
+	return func(c *Catalog) {
+		c.cacheFile = path
+	}
+}
+
+// WithStaleFallback sets whether expired language pairs are used when they cannot be retrieved again.
+// It is enabled by default.
+func WithStaleFallback(b bool) CatalogOption {
+	return func(c *Catalog) {
+		c.staleFallback = b
+	}
+}
+
+// NewCatalog creates a new Catalog retrieving language pairs with the given client.
+// Nothing is retrieved until the first lookup.
+func NewCatalog(client *Client, options ...CatalogOption) *Catalog {
+	c := &Catalog{
+		client:        client,
+		ttl:           DefaultCatalogTTL,
+		staleFallback: true,
+		now:           time.Now,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Refresh retrieves the language pairs and languages from Gengo, even if the cached ones have not expired.
+func (c *Catalog) Refresh() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refresh()
+}
+
+// FetchedAt returns when the language pairs in use were retrieved.
+func (c *Catalog) FetchedAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fetchedAt
+}
+
+// Available reports whether Gengo translates the pair at the tier.
+func (c *Catalog) Available(p lang.Pair, t Tier) (bool, error) {
+	_, err := c.LanguagePair(p, t)
+	if err == ErrPairUnavailable {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// LanguagePair returns the language pair and its unit price at the tier, or ErrPairUnavailable.
+func (c *Catalog) LanguagePair(p lang.Pair, t Tier) (LanguagePairWithPrice, error) {
+	if err := c.load(); err != nil {
+		return LanguagePairWithPrice{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lp, ok := c.pairs[pairTier{p, t}]
+	if !ok {
+		return lp, ErrPairUnavailable
+	}
+	return lp, nil
+}
+
+// UnitPrice returns the price per unit of translating the pair at the tier.
+func (c *Catalog) UnitPrice(p lang.Pair, t Tier) (currency.Money, error) {
+	lp, err := c.LanguagePair(p, t)
+	return currency.FromFloat(float64(lp.UnitPrice), lp.Currency), err
+}
+
+// Tiers returns the tiers the pair is available at, from cheapest to most expensive.
+func (c *Catalog) Tiers(p lang.Pair) ([]Tier, error) {
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Tier(nil), c.tiers[p]...), nil
+}
+
+// Targets returns every language the source language can be translated into, sorted by code.
+func (c *Catalog) Targets(source lang.Code) ([]lang.Code, error) {
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]lang.Code(nil), c.targets[source]...), nil
+}
+
+// Language returns the language Gengo describes with the code.
+func (c *Catalog) Language(code lang.Code) (Language, error) {
+	if err := c.load(); err != nil {
+		return Language{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.languages[code]
+	if !ok {
+		return l, fmt.Errorf("gengo: unknown language %q", code)
+	}
+	return l, nil
+}
+
+// load makes sure unexpired language pairs are in use, reading the cache file or retrieving them if needed.
+func (c *Catalog) load() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pairs != nil && c.fresh(c.fetchedAt) {
+		return nil
+	}
+	if c.pairs == nil && c.cacheFile != "" {
+		if s, err := c.readCache(); err == nil {
+			c.use(s)
+			if c.fresh(s.FetchedAt) {
+				return nil
+			}
+		}
+	}
+	err := c.refresh()
+	if err != nil && c.staleFallback && c.pairs != nil {
+		if logger != nil {
+			logger.Printf("using language pairs from %s: %v", c.fetchedAt, err)
+		}
+		return nil
+	}
+	return err
+}
+
+func (c *Catalog) fresh(t time.Time) bool {
+	return c.now().Sub(t) < c.ttl
+}
+
+func (c *Catalog) refresh() error {
+	lps, err := c.client.LanguagePairs(NewLanguagePairsRequest())
+	if err != nil {
+		return fmt.Errorf("retrieving language pairs: %w", err)
+	}
+	ls, err := c.client.Languages()
+	if err != nil {
+		return fmt.Errorf("retrieving languages: %w", err)
+	}
+	s := &catalogSnapshot{FetchedAt: c.now(), LanguagePairs: lps.LanguagePairs, Languages: ls.Languages}
+	c.use(s)
+	if c.cacheFile != "" {
+		if err := c.writeCache(s); err != nil && logger != nil {
+			logger.Printf("writing catalog cache %s: %v", c.cacheFile, err)
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) use(s *catalogSnapshot) {
+	c.fetchedAt = s.FetchedAt
+	c.pairs = make(map[pairTier]LanguagePairWithPrice, len(s.LanguagePairs))
+	c.tiers = map[lang.Pair][]Tier{}
+	c.targets = map[lang.Code][]lang.Code{}
+	for _, lp := range s.LanguagePairs {
+		c.pairs[pairTier{lp.Pair, lp.Tier}] = lp
+		c.tiers[lp.Pair] = append(c.tiers[lp.Pair], lp.Tier)
+	}
+	for p, tiers := range c.tiers {
+		c.targets[p.Source] = append(c.targets[p.Source], p.Target)
+		sort.Slice(tiers, func(i, j int) bool {
+			return c.pairs[pairTier{p, tiers[i]}].UnitPrice < c.pairs[pairTier{p, tiers[j]}].UnitPrice
+		})
+	}
+	for _, targets := range c.targets {
+		sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+	}
+	c.languages = make(map[lang.Code]Language, len(s.Languages))
+	for _, l := range s.Languages {
+		c.languages[l.Code] = l
+	}
+}
+
+func (c *Catalog) readCache() (*catalogSnapshot, error) {
+	f, err := os.Open(c.cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s := new(catalogSnapshot)
+	if err := json.NewDecoder(f).Decode(s); err != nil {
+		return nil, fmt.Errorf("reading catalog cache %s: %v", c.cacheFile, err)
+	}
+	return s, nil
+}
+
+// writeCache replaces the cache file atomically so that concurrent readers never see a partial file.
+func (c *Catalog) writeCache(s *catalogSnapshot) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.cacheFile), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.cacheFile), filepath.Base(c.cacheFile)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.cacheFile)
+}
@@ -0,0 +1,112 @@
+package gengo
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo/currency"
+	"github.com/trinchan/gengo/internal/gengotest"
+	"github.com/trinchan/gengo/lang"
+)
+
+const (
+	testLanguagePairs = `[
+		{"lc_src":"en","lc_tgt":"ja","tier":"pro","unit_price":"0.1200","currency":"USD"},
+		{"lc_src":"en","lc_tgt":"ja","tier":"standard","unit_price":"0.0600","currency":"USD"},
+		{"lc_src":"en","lc_tgt":"fr","tier":"standard","unit_price":"0.0600","currency":"USD"},
+		{"lc_src":"ja","lc_tgt":"en","tier":"standard","unit_price":"0.0500","currency":"USD"}]`
+	testLanguages = `[
+		{"lc":"en","language":"English","localized_name":"English","unit_type":"word"},
+		{"lc":"ja","language":"Japanese","localized_name":"日本語","unit_type":"character"}]`
+)
+
+func newTestCatalog(options ...CatalogOption) (*Catalog, *gengotest.API, *time.Time) {
+	c, api := newFakeClient()
+	api.Handle("GET /translate/service/language_pairs", testLanguagePairs)
+	api.Handle("GET /translate/service/languages", testLanguages)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cat := NewCatalog(c, options...)
+	cat.now = func() time.Time { return now }
+	return cat, api, &now
+}
+
+func TestCatalogLookups(t *testing.T) {
+	cat, _, _ := newTestCatalog()
+	enJa := lang.NewPair(lang.English, lang.Japanese)
+	if ok, err := cat.Available(enJa, TierPro); !ok || err != nil {
+		t.Errorf("Available(en-ja, pro) = %v, %v", ok, err)
+	}
+	if ok, err := cat.Available(lang.NewPair(lang.Japanese, lang.English), TierPro); ok || err != nil {
+		t.Errorf("Available(ja-en, pro) = %v, %v", ok, err)
+	}
+	price, err := cat.UnitPrice(enJa, TierStandard)
+	if err != nil || price != currency.MustParse("0.06", currency.USD) {
+		t.Errorf("UnitPrice(en-ja, standard) = %v, %v", price, err)
+	}
+	if _, err := cat.UnitPrice(enJa, TierUltra); err != ErrPairUnavailable {
+		t.Errorf("UnitPrice(en-ja, ultra) error = %v", err)
+	}
+	if tiers, _ := cat.Tiers(enJa); !reflect.DeepEqual(tiers, []Tier{TierStandard, TierPro}) {
+		t.Errorf("Tiers(en-ja) = %v", tiers)
+	}
+	if targets, _ := cat.Targets(lang.English); !reflect.DeepEqual(targets, []lang.Code{lang.French, lang.Japanese}) {
+		t.Errorf("Targets(en) = %v", targets)
+	}
+	if l, err := cat.Language(lang.Japanese); err != nil || l.UnitType != lang.UnitCharacter {
+		t.Errorf("Language(ja) = %+v, %v", l, err)
+	}
+}
+
+func TestCatalogTTL(t *testing.T) {
+	cat, api, now := newTestCatalog(WithCatalogTTL(time.Hour))
+	for i := 0; i < 3; i++ {
+		cat.Targets(lang.English)
+	}
+	if api.Len() != 2 {
+		t.Fatalf("expected one retrieval of pairs and languages, got %d requests", api.Len())
+	}
+	*now = now.Add(2 * time.Hour)
+	cat.Targets(lang.English)
+	if api.Len() != 4 {
+		t.Errorf("expected the catalog to refresh after the ttl, got %d requests", api.Len())
+	}
+}
+
+func TestCatalogStaleFallback(t *testing.T) {
+	cat, api, now := newTestCatalog(WithCatalogTTL(time.Hour))
+	cat.Targets(lang.English)
+	delete(api.Routes, "GET /translate/service/language_pairs")
+	*now = now.Add(2 * time.Hour)
+	if targets, err := cat.Targets(lang.English); err != nil || len(targets) != 2 {
+		t.Errorf("expected stale targets, got %v, %v", targets, err)
+	}
+
+	strict, api, now := newTestCatalog(WithCatalogTTL(time.Hour), WithStaleFallback(false))
+	strict.Targets(lang.English)
+	delete(api.Routes, "GET /translate/service/language_pairs")
+	*now = now.Add(2 * time.Hour)
+	if _, err := strict.Targets(lang.English); err == nil {
+		t.Error("expected an error without stale fallback")
+	}
+}
+
+func TestCatalogCacheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "catalog.json")
+	cat, _, _ := newTestCatalog(WithCatalogCacheFile(path))
+	if err := cat.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	cached, api, _ := newTestCatalog(WithCatalogCacheFile(path))
+	price, err := cached.UnitPrice(lang.NewPair(lang.English, lang.Japanese), TierPro)
+	if err != nil || price.Amount() != "0.12" {
+		t.Errorf("UnitPrice() from cache = %v, %v", price, err)
+	}
+	if api.Len() != 0 {
+		t.Errorf("expected the cache file to be used, got %d requests", api.Len())
+	}
+	if !cached.FetchedAt().Equal(cat.FetchedAt()) {
+		t.Errorf("FetchedAt() = %v, want %v", cached.FetchedAt(), cat.FetchedAt())
+	}
+}
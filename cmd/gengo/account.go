@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+var accountCommand = &command{
+	name:    "account",
+	summary: "show account information",
+	subcommands: []*command{
+		{name: "stats", summary: "show credits spent and account details", setup: noFlags(accountStats)},
+		{name: "balance", summary: "show the credit balance", setup: noFlags(accountBalance)},
+		{name: "me", summary: "show the account owner", setup: noFlags(accountMe)},
+		{name: "preferred", summary: "list preferred translators", setup: noFlags(accountPreferred)},
+	},
+}
+
+// noFlags is the setup of commands without flags or arguments.
+func noFlags(run runFunc) func(*flag.FlagSet) runFunc {
+	return func(*flag.FlagSet) runFunc {
+		return func(e *env, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+			}
+			return run(e, args)
+		}
+	}
+}
+
+func accountStats(e *env, _ []string) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	r, err := c.AccountStats()
+	if err != nil {
+		return err
+	}
+	t := newTable("credits_spent", "processing", "currency", "user_since", "billing_type", "customer_type")
+	t.add(r.CreditsSpent.Amount(), r.Processing.Amount(), r.Currency, r.UserSince, r.BillingType, r.CustomerType)
+	return e.print(r, t)
+}
+
+func accountBalance(e *env, _ []string) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	r, err := c.Balance()
+	if err != nil {
+		return err
+	}
+	t := newTable("credits", "currency")
+	t.add(r.Credits.Amount(), r.Currency)
+	return e.print(r, t)
+}
+
+func accountMe(e *env, _ []string) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	r, err := c.Me()
+	if err != nil {
+		return err
+	}
+	t := newTable("email", "name", "display_name", "language")
+	t.add(r.Email, r.Name, r.DisplayName, r.LanguageCode)
+	return e.print(r, t)
+}
+
+func accountPreferred(e *env, _ []string) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	r, err := c.PreferredTranslators()
+	if err != nil {
+		return err
+	}
+	t := newTable("source", "target", "tier", "translator_id", "last_login")
+	for _, p := range r.PreferredTranslators {
+		for _, tr := range p.Translators {
+			t.add(p.Source, p.Target, p.Tier, tr.ID, tr.LastLogin)
+		}
+	}
+	return e.print(r.PreferredTranslators, t)
+}
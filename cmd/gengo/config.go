@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/trinchan/gengo"
+)
+
+// config is the config file, which holds named credential profiles, such as
+//
+//	{
+//	  "default_profile": "sandbox",
+//	  "profiles": {
+//	    "sandbox": {"public_key": "...", "private_key": "..."},
+//	    "jp": {"public_key": "...", "private_key": "...", "production": true}
+//	  }
+//	}
+type config struct {
+	DefaultProfile string              `json:"default_profile"`
+	Profiles       map[string]*profile `json:"profiles"`
+}
+
+// profile is a set of credentials and the API it is used against.
+type profile struct {
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key"`
+	Production bool   `json:"production,omitempty"`
+	// BaseURL overrides the sandbox or production URL.
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+func (p *profile) baseURL() string {
+	switch {
+	case p.BaseURL != "":
+		return p.BaseURL
+	case p.Production:
+		return gengo.ProductionBaseURL
+	}
+	return gengo.SandboxBaseURL
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "gengo.json"
+	}
+	return filepath.Join(dir, "gengo", "config.json")
+}
+
+// loadProfile returns the named profile from the config file. Without a name, credentials come from the
+// GENGO_PUBLIC_KEY, GENGO_PRIVATE_KEY and GENGO_PRODUCTION environment variables if they are set,
+// and from the config file's default profile otherwise.
+func loadProfile(path, name string, getenv func(string) string) (*profile, error) {
+	if name == "" && getenv("GENGO_PUBLIC_KEY") != "" {
+		p := &profile{
+			PublicKey:  getenv("GENGO_PUBLIC_KEY"),
+			PrivateKey: getenv("GENGO_PRIVATE_KEY"),
+			Production: getenv("GENGO_PRODUCTION") != "",
+		}
+		if p.PrivateKey == "" {
+			return nil, errors.New("GENGO_PRIVATE_KEY is not set")
+		}
+		return p, nil
+	}
+	if path == "" {
+		path = defaultConfigPath()
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && name == "" {
+		return nil, fmt.Errorf("no credentials: set GENGO_PUBLIC_KEY and GENGO_PRIVATE_KEY or create %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("reading config %s: %v", path, err)
+	}
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	if p.PublicKey == "" || p.PrivateKey == "" {
+		return nil, fmt.Errorf("profile %q in %s has no keys", name, path)
+	}
+	return p, nil
+}
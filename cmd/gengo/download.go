@@ -0,0 +1,450 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+)
+
+const defaultLayout = "{target}/{slug}{ext}"
+
+// downloadBatchSize is the number of jobs fetched at a time, so that saving starts before every job is fetched.
+const downloadBatchSize = 50
+
+// Keys of the merged file, chosen with -key.
+const (
+	keySlug       = "slug"
+	keyCustomData = "custom_data"
+	keyID         = "id"
+)
+
+var downloadCommand = &command{
+	name:    "download",
+	summary: "save approved translations to files, resuming where an interrupted download stopped",
+	setup: func(fs *flag.FlagSet) runFunc {
+		orderID := fs.Int("order", 0, "download the approved jobs of the order `id`")
+		since := fs.String("since", "", "only download jobs created after the `time`, as a date, RFC 3339 time or UNIX time")
+		out := fs.String("out", ".", "`directory` to write the translations to")
+		layout := fs.String("layout", defaultLayout,
+			"`path` of each translation in -out, from {id}, {order}, {source}, {target} or {lang}, {tier}, {slug}, {custom_data} and {ext}")
+		merge := fs.String("merge", "", "write every translation to one JSON or CSV `file` instead, keyed by -key")
+		key := fs.String("key", keySlug, "`key` of the merged translations: slug, custom_data or id")
+		progressPath := fs.String("progress", "", "`file` recording the jobs already downloaded (default .gengo-download.json in -out, or -merge with .progress.json appended)")
+		restart := fs.Bool("restart", false, "download every job again, ignoring the progress file")
+		return func(e *env, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+			}
+			d := &downloader{out: *out, layout: *layout, merge: *merge, key: *key}
+			if err := d.validate(); err != nil {
+				return fmt.Errorf("%w: %v", errUsage, err)
+			}
+			var after time.Time
+			if *since != "" {
+				t, err := parseTime(*since)
+				if err != nil {
+					return fmt.Errorf("%w: -since: %v", errUsage, err)
+				}
+				after = t
+			}
+			d.progressPath = *progressPath
+			if d.progressPath == "" {
+				d.progressPath = d.defaultProgressPath()
+			}
+			d.progress = newDownloadProgress()
+			if !*restart {
+				p, err := readDownloadProgress(d.progressPath)
+				if err != nil {
+					return err
+				}
+				d.progress = p
+			}
+			c, err := e.client()
+			if err != nil {
+				return err
+			}
+			ids, err := approvedJobIDs(c, *orderID, after)
+			incomplete, _ := err.(*gengo.IncompleteListError)
+			if err != nil && incomplete == nil {
+				return err
+			}
+			err = d.download(e, c, ids, after)
+			if d.merge != "" && len(d.progress.Jobs) > 0 {
+				if merr := d.writeMerged(); merr != nil && err == nil {
+					err = merr
+				}
+			}
+			fmt.Fprintf(e.stderr, "Downloaded %d jobs, skipped %d downloaded before.\n", len(d.downloaded), d.skipped)
+			if err != nil {
+				return err
+			}
+			if err := e.print(d.downloaded, d.table()); err != nil {
+				return err
+			}
+			if incomplete != nil {
+				// The run is not complete, although the jobs listed were downloaded.
+				return fmt.Errorf("%v; download them with -order", incomplete)
+			}
+			return nil
+		}
+	},
+}
+
+// approvedJobIDs lists the approved jobs of an order, or all approved jobs created after a time. If not every
+// approved job could be listed, it returns those which were with a *gengo.IncompleteListError.
+func approvedJobIDs(c *gengo.Client, orderID int, after time.Time) ([]int, error) {
+	if orderID != 0 {
+		r, err := c.GetOrder(gengo.NewOrderGetRequest(orderID))
+		if err != nil {
+			return nil, err
+		}
+		var ids []int
+		for _, id := range r.Order.JobsApproved {
+			ids = append(ids, int(id))
+		}
+		return ids, nil
+	}
+	options := []gengo.GetJobsRequestOption{gengo.WithStatus(gengo.JobStatusApproved)}
+	if !after.IsZero() {
+		options = append(options, gengo.WithTimestampAfter(gengo.Time(after)))
+	}
+	return listJobIDs(c, options...)
+}
+
+// downloadedJob records where a job was saved. Text is only kept for merged downloads, which are rewritten as a whole.
+// Skipped marks the file jobs of merged downloads, which cannot be merged, so that resumed runs skip them too.
+type downloadedJob struct {
+	JobID   int       `json:"job_id"`
+	Target  lang.Code `json:"target"`
+	Path    string    `json:"path,omitempty"`
+	Key     string    `json:"key,omitempty"`
+	Text    string    `json:"text,omitempty"`
+	Skipped bool      `json:"skipped,omitempty"`
+}
+
+// downloadProgress is the progress file of a download, so that it can resume after an interruption.
+type downloadProgress struct {
+	Jobs map[int]downloadedJob `json:"jobs"`
+}
+
+func newDownloadProgress() *downloadProgress {
+	return &downloadProgress{Jobs: map[int]downloadedJob{}}
+}
+
+func readDownloadProgress(path string) (*downloadProgress, error) {
+	p := newDownloadProgress()
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("reading progress file %s: %v", path, err)
+	}
+	if p.Jobs == nil {
+		p.Jobs = map[int]downloadedJob{}
+	}
+	return p, nil
+}
+
+func (p *downloadProgress) write(path string) error {
+	return writeFileAtomic(path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	})
+}
+
+// downloader saves approved jobs as files laid out in a directory, or merged into one file.
+type downloader struct {
+	out, layout string
+	merge, key  string
+
+	progressPath string
+	progress     *downloadProgress
+	// paths maps the file of each job to its ID, to tell jobs laid out to the same path apart.
+	paths      map[string]int
+	downloaded []downloadedJob
+	skipped    int
+}
+
+var placeholderPattern = regexp.MustCompile(`\{[a-z_]*\}`)
+
+var layoutFields = map[string]bool{
+	"{id}": true, "{order}": true, "{source}": true, "{target}": true, "{lang}": true,
+	"{tier}": true, "{slug}": true, "{custom_data}": true, "{ext}": true,
+}
+
+func (d *downloader) validate() error {
+	if d.merge != "" {
+		switch strings.ToLower(filepath.Ext(d.merge)) {
+		case ".json", ".csv":
+		default:
+			return fmt.Errorf("-merge must be a .json or .csv file")
+		}
+		switch d.key {
+		case keySlug, keyCustomData, keyID:
+		default:
+			return fmt.Errorf("unknown -key %q", d.key)
+		}
+		return nil
+	}
+	if d.layout == "" || filepath.IsAbs(d.layout) {
+		return fmt.Errorf("-layout must be a relative path")
+	}
+	for _, p := range placeholderPattern.FindAllString(d.layout, -1) {
+		if !layoutFields[p] {
+			return fmt.Errorf("unknown placeholder %s in -layout", p)
+		}
+	}
+	return nil
+}
+
+func (d *downloader) defaultProgressPath() string {
+	if d.merge != "" {
+		return d.merge + ".progress.json"
+	}
+	return filepath.Join(d.out, ".gengo-download.json")
+}
+
+// download saves the jobs not downloaded before, recording each in the progress file as soon as it is saved.
+func (d *downloader) download(e *env, c *gengo.Client, ids []int, after time.Time) error {
+	d.paths = map[string]int{}
+	for id, j := range d.progress.Jobs {
+		if j.Path != "" {
+			d.paths[j.Path] = id
+		}
+	}
+	var pending []int
+	for _, id := range ids {
+		if _, ok := d.progress.Jobs[id]; ok {
+			d.skipped++
+			continue
+		}
+		pending = append(pending, id)
+	}
+	for start := 0; start < len(pending); start += downloadBatchSize {
+		end := start + downloadBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		jobs, err := getJobs(c, pending[start:end])
+		if err != nil {
+			return err
+		}
+		for i := range jobs {
+			job := &jobs[i]
+			if !after.IsZero() && time.Time(job.Ctime).Before(after) {
+				continue
+			}
+			saved, err := d.save(c, job)
+			if err != nil {
+				return fmt.Errorf("job %d: %v", job.ID, err)
+			}
+			if saved.Skipped {
+				fmt.Fprintf(e.stderr, "Skipping file job %d, which cannot be merged.\n", job.ID)
+			}
+			d.progress.Jobs[saved.JobID] = *saved
+			if err := d.progress.write(d.progressPath); err != nil {
+				return err
+			}
+			if !saved.Skipped {
+				d.downloaded = append(d.downloaded, *saved)
+			}
+		}
+	}
+	return nil
+}
+
+// save writes a job to its file, or only records its text for merging. File jobs cannot be merged and are
+// recorded as skipped.
+func (d *downloader) save(c *gengo.Client, job *gengo.GetJobResponse) (*downloadedJob, error) {
+	saved := &downloadedJob{JobID: int(job.ID), Target: job.Target}
+	if d.merge != "" {
+		if job.FileTargetURL != "" {
+			saved.Skipped = true
+			return saved, nil
+		}
+		saved.Key, saved.Text = d.mergeKey(job), job.BodyTgt
+		return saved, nil
+	}
+	rel := d.path(job)
+	if id, ok := d.paths[rel]; ok && id != int(job.ID) {
+		ext := path.Ext(rel)
+		rel = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(rel, ext), job.ID, ext)
+	}
+	d.paths[rel] = int(job.ID)
+	saved.Path = rel
+	err := writeFileAtomic(filepath.Join(d.out, filepath.FromSlash(rel)), func(w io.Writer) error {
+		if job.FileTargetURL == "" {
+			_, err := io.WriteString(w, job.BodyTgt)
+			return err
+		}
+		body, err := c.TargetFile(job)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		_, err = io.Copy(w, body)
+		return err
+	})
+	return saved, err
+}
+
+// path expands the layout for a job. Values are made safe to use as a single path element.
+func (d *downloader) path(job *gengo.GetJobResponse) string {
+	ext := ".txt"
+	if job.FileTargetURL != "" {
+		if u, err := url.Parse(job.FileTargetURL); err == nil && path.Ext(u.Path) != "" {
+			ext = path.Ext(u.Path)
+		} else {
+			ext = ""
+		}
+	}
+	slug := job.Slug
+	if slug == "" {
+		slug = strconv.Itoa(int(job.ID))
+	}
+	values := map[string]string{
+		"{id}":          strconv.Itoa(int(job.ID)),
+		"{order}":       strconv.Itoa(int(job.OrderID)),
+		"{source}":      string(job.Source),
+		"{target}":      string(job.Target),
+		"{lang}":        string(job.Target),
+		"{tier}":        string(job.Tier),
+		"{slug}":        slug,
+		"{custom_data}": job.CustomData,
+		"{ext}":         ext,
+	}
+	p := placeholderPattern.ReplaceAllStringFunc(d.layout, func(p string) string {
+		return pathElement(values[p])
+	})
+	return path.Clean(filepath.ToSlash(p))
+}
+
+// pathElement replaces the characters of v which would leave or add directories.
+func pathElement(v string) string {
+	v = strings.NewReplacer("/", "_", "\\", "_").Replace(v)
+	if v == ".." || v == "." {
+		return "_"
+	}
+	return v
+}
+
+func (d *downloader) mergeKey(job *gengo.GetJobResponse) string {
+	key := ""
+	switch d.key {
+	case keySlug:
+		key = job.Slug
+	case keyCustomData:
+		key = job.CustomData
+	}
+	if key == "" {
+		key = strconv.Itoa(int(job.ID))
+	}
+	return key
+}
+
+// writeMerged writes every job downloaded so far, including in earlier runs, to the merged file.
+// JSON maps each key to its translations by language; CSV has a row per key and a column per language.
+func (d *downloader) writeMerged() error {
+	merged := map[string]map[string]string{}
+	languages := map[string]bool{}
+	for _, j := range d.progress.Jobs {
+		if j.Skipped {
+			continue
+		}
+		if merged[j.Key] == nil {
+			merged[j.Key] = map[string]string{}
+		}
+		merged[j.Key][string(j.Target)] = j.Text
+		languages[string(j.Target)] = true
+	}
+	if strings.ToLower(filepath.Ext(d.merge)) == ".json" {
+		return writeFileAtomic(d.merge, func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			enc.SetEscapeHTML(false)
+			return enc.Encode(merged)
+		})
+	}
+	header := []string{"key"}
+	for l := range languages {
+		header = append(header, l)
+	}
+	sort.Strings(header[1:])
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return writeFileAtomic(d.merge, func(w io.Writer) error {
+		cw := csv.NewWriter(w)
+		cw.Write(header)
+		for _, k := range keys {
+			row := []string{k}
+			for _, l := range header[1:] {
+				row = append(row, merged[k][l])
+			}
+			cw.Write(row)
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+}
+
+func (d *downloader) table() *table {
+	if d.merge != "" {
+		t := newTable("job_id", "target", "key")
+		for _, j := range d.downloaded {
+			t.add(j.JobID, j.Target, j.Key)
+		}
+		return t
+	}
+	t := newTable("job_id", "target", "path")
+	for _, j := range d.downloaded {
+		t.add(j.JobID, j.Target, filepath.Join(d.out, filepath.FromSlash(j.Path)))
+	}
+	return t
+}
+
+// writeFileAtomic writes a file through a temporary file renamed into place, so that it is never left half written.
+func writeFileAtomic(name string, write func(io.Writer) error) error {
+	dir := filepath.Dir(name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/internal/gengotest"
+)
+
+func downloadRoutes() map[string]string {
+	return map[string]string{
+		"GET /translate/order/42": `{"order":{"order_id":"42","jobs_approved":["101","102","103"],"total_jobs":"3"}}`,
+		"GET /translate/jobs/101,102,103": `{"jobs":[` +
+			`{"job_id":"101","order_id":"42","lc_src":"en","lc_tgt":"ja","slug":"greeting","custom_data":"home.hello","body_tgt":"こんにちは"},` +
+			`{"job_id":"102","order_id":"42","lc_src":"en","lc_tgt":"fr","slug":"greeting","custom_data":"home.hello","body_tgt":"Bonjour"},` +
+			`{"job_id":"103","order_id":"42","lc_src":"en","lc_tgt":"ja","slug":"../brochure","file_url_tgt":"http://files.example/t/103.docx"}]}`,
+		"GET /translate/jobs/103": `{"jobs":[` +
+			`{"job_id":"103","order_id":"42","lc_src":"en","lc_tgt":"ja","slug":"../brochure","file_url_tgt":"http://files.example/t/103.docx"}]}`,
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestDownload(t *testing.T) {
+	dir := t.TempDir()
+	e := newTestEnv(downloadRoutes())
+	// The file job fails first, so the download stops after the text jobs and resumes with it.
+	if code := run(e.env, []string{"download", "-order", "42", "-out", dir}); code != 1 {
+		t.Fatalf("exit %d, want 1: %s", code, e.stderr)
+	}
+	if got := readFile(t, filepath.Join(dir, "ja", "greeting.txt")); got != "こんにちは" {
+		t.Errorf("ja/greeting.txt = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "fr", "greeting.txt")); got != "Bonjour" {
+		t.Errorf("fr/greeting.txt = %q", got)
+	}
+
+	e = newTestEnv(downloadRoutes())
+	e.api.HandleRaw("GET /t/103.docx", "docx")
+	if code := run(e.env, []string{"-o", "csv", "download", "-order", "42", "-out", dir}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if got := readFile(t, filepath.Join(dir, "ja", ".._brochure.docx")); got != "docx" {
+		t.Errorf("file job = %q", got)
+	}
+	want := "job_id,target,path\n103,ja," + filepath.Join(dir, "ja", ".._brochure.docx") + "\n"
+	if e.stdout.String() != want {
+		t.Errorf("output = %q, want %q", e.stdout, want)
+	}
+	if !strings.Contains(e.stderr.String(), "Downloaded 1 jobs, skipped 2 downloaded before.") {
+		t.Errorf("unexpected summary %q", e.stderr)
+	}
+}
+
+func TestDownloadLayout(t *testing.T) {
+	dir := t.TempDir()
+	routes := downloadRoutes()
+	routes["GET /translate/order/42"] = `{"order":{"order_id":"42","jobs_approved":["101","102"],"total_jobs":"2"}}`
+	routes["GET /translate/jobs/101,102"] = `{"jobs":[` +
+		`{"job_id":"101","order_id":"42","lc_src":"en","lc_tgt":"ja","slug":"greeting","body_tgt":"こんにちは"},` +
+		`{"job_id":"102","order_id":"42","lc_src":"en","lc_tgt":"ja","slug":"greeting","body_tgt":"やあ"}]}`
+	e := newTestEnv(routes)
+	if code := run(e.env, []string{"download", "-order", "42", "-out", dir, "-layout", "{order}/{lang}/{slug}.txt"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if got := readFile(t, filepath.Join(dir, "42", "ja", "greeting-102.txt")); got != "やあ" {
+		t.Errorf("the second job with the same path = %q", got)
+	}
+	if code := run(newTestEnv(routes).env, []string{"download", "-order", "42", "-layout", "{name}.txt"}); code != 2 {
+		t.Errorf("unknown placeholder = %d, want 2", code)
+	}
+}
+
+func TestDownloadMerged(t *testing.T) {
+	dir := t.TempDir()
+	merged := filepath.Join(dir, "strings.csv")
+	e := newTestEnv(downloadRoutes())
+	if code := run(e.env, []string{"download", "-order", "42", "-merge", merged, "-key", "custom_data"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if got, want := readFile(t, merged), "key,fr,ja\nhome.hello,Bonjour,こんにちは\n"; got != want {
+		t.Errorf("merged CSV = %q, want %q", got, want)
+	}
+	if !strings.Contains(e.stderr.String(), "Skipping file job 103") {
+		t.Errorf("expected the file job to be skipped, got %q", e.stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "strings.csv.progress.json")); err != nil {
+		t.Errorf("expected a progress file: %v", err)
+	}
+
+	// A resumed run skips the file job along with the merged ones.
+	e = newTestEnv(downloadRoutes())
+	if code := run(e.env, []string{"download", "-order", "42", "-merge", merged, "-key", "custom_data"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if strings.Contains(e.stderr.String(), "Skipping file job") || !strings.Contains(e.stderr.String(), "Downloaded 0 jobs, skipped 3 downloaded before.") {
+		t.Errorf("unexpected resumed run %q", e.stderr)
+	}
+	if got := readFile(t, merged); got != "key,fr,ja\nhome.hello,Bonjour,こんにちは\n" {
+		t.Errorf("merged CSV after resuming = %q", got)
+	}
+
+	mergedJSON := filepath.Join(dir, "strings.json")
+	if code := run(newTestEnv(downloadRoutes()).env, []string{"download", "-order", "42", "-merge", mergedJSON}); code != 0 {
+		t.Fatal("merging to JSON failed")
+	}
+	if got, want := readFile(t, mergedJSON), "{\n  \"greeting\": {\n    \"fr\": \"Bonjour\",\n    \"ja\": \"こんにちは\"\n  }\n}\n"; got != want {
+		t.Errorf("merged JSON = %q, want %q", got, want)
+	}
+}
+
+// approvedHistory serves n approved text jobs created a minute apart, listed newest first as Gengo does, or oldest
+// first after timestamp_after with oldestFirst. Only the jobs from first can be fetched by ID.
+func approvedHistory(n, first int, oldestFirst bool) *testEnv {
+	e := newTestEnv(nil)
+	var history []gengotest.Job
+	for id := 1; id <= n; id++ {
+		history = append(history, gengotest.Job{ID: id, Ctime: 1706745600 + int64(id)*60, Status: gengo.JobStatusApproved})
+	}
+	e.api.HandleFunc("GET /translate/jobs", gengotest.Jobs(history, oldestFirst))
+	for start := first; start <= n; start += downloadBatchSize {
+		var ids, jobs []string
+		for id := start; id < start+downloadBatchSize && id <= n; id++ {
+			ids = append(ids, strconv.Itoa(id))
+			jobs = append(jobs, fmt.Sprintf(`{"job_id":"%d","lc_src":"en","lc_tgt":"ja","slug":"s%d","body_tgt":"t%d"}`, id, id, id))
+		}
+		e.api.Handle("GET /translate/jobs/"+strings.Join(ids, ","), `{"jobs":[`+strings.Join(jobs, ",")+`]}`)
+	}
+	return e
+}
+
+func TestDownloadPages(t *testing.T) {
+	dir := t.TempDir()
+	e := approvedHistory(250, 1, true)
+	if code := run(e.env, []string{"download", "-out", dir}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stderr.String(), "Downloaded 250 jobs") {
+		t.Errorf("unexpected summary %q", e.stderr)
+	}
+	if got := readFile(t, filepath.Join(dir, "ja", "s250.txt")); got != "t250" {
+		t.Errorf("ja/s250.txt = %q", got)
+	}
+
+	// Listed newest first, only the newest page is downloaded and the run fails.
+	dir = t.TempDir()
+	e = approvedHistory(210, 11, false)
+	if code := run(e.env, []string{"download", "-out", dir}); code != 1 {
+		t.Fatalf("exit %d, want 1: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stderr.String(), "Downloaded 200 jobs") || !strings.Contains(e.stderr.String(), "-order") {
+		t.Errorf("unexpected summary %q", e.stderr)
+	}
+}
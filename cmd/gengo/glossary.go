@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/trinchan/gengo"
+)
+
+var glossaryCommand = &command{
+	name:    "glossary",
+	summary: "show glossaries",
+	subcommands: []*command{
+		{name: "list", summary: "list glossaries", setup: noFlags(glossaryList)},
+		{name: "get", args: "id", summary: "show a glossary, or its terms", setup: glossaryGet},
+	},
+}
+
+func glossariesTable(gs ...gengo.Glossary) *table {
+	t := newTable("id", "title", "source", "targets", "units", "public", "ctime")
+	for _, g := range gs {
+		targets := make([]string, len(g.Targets))
+		for i, l := range g.Targets {
+			targets[i] = string(l.Code)
+		}
+		t.add(g.ID, g.Title, g.SourceCode, strings.Join(targets, ","), g.UnitCount, g.Public, g.Ctime)
+	}
+	return t
+}
+
+func glossaryList(e *env, _ []string) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	r, err := c.ListGlossaries()
+	if err != nil {
+		return err
+	}
+	return e.print(r.Glossaries, glossariesTable(r.Glossaries...))
+}
+
+func glossaryGet(fs *flag.FlagSet) runFunc {
+	terms := fs.Bool("terms", false, "list the glossary's terms")
+	return func(e *env, args []string) error {
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		if *terms {
+			r, err := c.GlossaryTerms(gengo.NewGlossaryTermsRequest(id))
+			if err != nil {
+				return err
+			}
+			t := newTable("source", "target", "term", "comment")
+			for _, term := range r.Terms {
+				for _, tr := range term.Translations {
+					t.add(term.Source, tr.Code, tr.Term, term.Comment)
+				}
+			}
+			return e.print(r.Terms, t)
+		}
+		r, err := c.GetGlossaryByID(gengo.NewGetGlossaryRequest(id))
+		if err != nil {
+			return err
+		}
+		return e.print(r.Glossary, glossariesTable(r.Glossary))
+	}
+}
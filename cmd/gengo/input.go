@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	inputJSONL = "jsonl"
+	inputCSV   = "csv"
+	inputYAML  = "yaml"
+)
+
+// jobRecord defines a text job read from an input file.
+type jobRecord struct {
+	// Row is the line of the record in a JSON Lines or CSV file, or its index from 1 in a YAML file.
+	Row         int    `json:"-" yaml:"-"`
+	Text        string `json:"text" yaml:"text"`
+	Source      string `json:"source" yaml:"source"`
+	Target      string `json:"target" yaml:"target"`
+	Tier        string `json:"tier" yaml:"tier"`
+	Slug        string `json:"slug" yaml:"slug"`
+	Comment     string `json:"comment" yaml:"comment"`
+	CustomData  string `json:"custom_data" yaml:"custom_data"`
+	GlossaryID  int    `json:"glossary_id" yaml:"glossary_id"`
+	CallbackURL string `json:"callback_url" yaml:"callback_url"`
+}
+
+// defaults fills the fields r leaves empty from d.
+func (r *jobRecord) defaults(d *jobRecord) {
+	fill := func(s *string, def string) {
+		if *s == "" {
+			*s = def
+		}
+	}
+	fill(&r.Source, d.Source)
+	fill(&r.Target, d.Target)
+	fill(&r.Tier, d.Tier)
+	fill(&r.Slug, d.Slug)
+	fill(&r.Comment, d.Comment)
+	fill(&r.CustomData, d.CustomData)
+	fill(&r.CallbackURL, d.CallbackURL)
+	if r.GlossaryID == 0 {
+		r.GlossaryID = d.GlossaryID
+	}
+}
+
+// job validates the record and creates its JobRequest.
+func (r *jobRecord) job(options ...gengo.JobOption) (*gengo.JobRequest, error) {
+	if strings.TrimSpace(r.Text) == "" {
+		return nil, errors.New("no text to translate")
+	}
+	tier := gengo.Tier(r.Tier)
+	if tier != gengo.TierStandard && tier != gengo.TierPro && tier != gengo.TierUltra {
+		return nil, fmt.Errorf("unknown tier %q", r.Tier)
+	}
+	source, err := lang.Parse(r.Source)
+	if err != nil {
+		return nil, fmt.Errorf("source: %v", err)
+	}
+	target, err := lang.Parse(r.Target)
+	if err != nil {
+		return nil, fmt.Errorf("target: %v", err)
+	}
+	if r.Slug != "" {
+		options = append(options, gengo.WithSlug(r.Slug))
+	}
+	if r.Comment != "" {
+		options = append(options, gengo.WithComment(r.Comment))
+	}
+	if r.CustomData != "" {
+		options = append(options, gengo.WithCustomData(r.CustomData))
+	}
+	if r.GlossaryID != 0 {
+		options = append(options, gengo.WithGlossaryID(r.GlossaryID))
+	}
+	if r.CallbackURL != "" {
+		options = append(options, gengo.WithCallbackURL(r.CallbackURL))
+	}
+	return gengo.NewJobRequest(r.Text, lang.NewPair(source, target), tier, options...), nil
+}
+
+// inputFormat guesses the format of an input file from its extension.
+func inputFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson", ".json":
+		return inputJSONL, nil
+	case ".csv":
+		return inputCSV, nil
+	case ".yaml", ".yml":
+		return inputYAML, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %s, use -format", path)
+}
+
+// readRecords reads job records in the format. JSON input may also be a single array of records.
+func readRecords(r io.Reader, format string) ([]jobRecord, error) {
+	switch format {
+	case inputJSONL:
+		return readJSONL(r)
+	case inputCSV:
+		return readCSV(r)
+	case inputYAML:
+		return readYAML(r)
+	}
+	return nil, fmt.Errorf("unknown input format %q", format)
+}
+
+func readJSONL(r io.Reader) ([]jobRecord, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var records []jobRecord
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, err
+		}
+		for i := range records {
+			records[i].Row = i + 1
+		}
+		return records, nil
+	}
+	for i, line := range bytes.Split(b, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		rec := jobRecord{Row: i + 1}
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func readCSV(r io.Reader) ([]jobRecord, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %v", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	var records []jobRecord
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		rec := jobRecord{Row: line}
+		for i, v := range row {
+			if i >= len(header) {
+				return nil, fmt.Errorf("line %d: more fields than columns", line)
+			}
+			if err := rec.set(header[i], v); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		records = append(records, rec)
+	}
+}
+
+// set sets the field of the CSV column.
+func (r *jobRecord) set(column, v string) error {
+	switch strings.ToLower(strings.TrimSpace(column)) {
+	case "text":
+		r.Text = v
+	case "source":
+		r.Source = v
+	case "target":
+		r.Target = v
+	case "tier":
+		r.Tier = v
+	case "slug":
+		r.Slug = v
+	case "comment":
+		r.Comment = v
+	case "custom_data":
+		r.CustomData = v
+	case "glossary_id":
+		if v == "" {
+			return nil
+		}
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid glossary_id %q", v)
+		}
+		r.GlossaryID = id
+	case "callback_url":
+		r.CallbackURL = v
+	default:
+		return fmt.Errorf("unknown column %q", column)
+	}
+	return nil
+}
+
+func readYAML(r io.Reader) ([]jobRecord, error) {
+	var records []jobRecord
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&records); err != nil && err != io.EOF {
+		return nil, err
+	}
+	for i := range records {
+		records[i].Row = i + 1
+	}
+	return records, nil
+}
+
+// jobFlags are the flags describing jobs, shared by quote and submit.
+// Jobs are read from -file, or made from the text given as arguments or on standard input.
+// The other flags set the fields records leave empty.
+type jobFlags struct {
+	file, format string
+	defaults     jobRecord
+}
+
+func newJobFlags(fs *flag.FlagSet) *jobFlags {
+	f := new(jobFlags)
+	fs.StringVar(&f.file, "file", "", "read jobs from a JSON Lines, CSV or YAML `file`")
+	fs.StringVar(&f.format, "format", "", "`format` of -file: jsonl, csv or yaml (default from the file extension)")
+	fs.StringVar(&f.defaults.Source, "from", "", "source language `code`")
+	fs.StringVar(&f.defaults.Target, "to", "", "comma separated target language `codes`")
+	fs.StringVar(&f.defaults.Tier, "tier", string(gengo.TierStandard), "`tier`: standard, pro or ultra")
+	fs.StringVar(&f.defaults.Slug, "slug", "", "`slug` naming the jobs")
+	fs.StringVar(&f.defaults.Comment, "comment", "", "`comment` for the translator")
+	fs.StringVar(&f.defaults.CustomData, "custom-data", "", "`data` stored with the jobs")
+	fs.IntVar(&f.defaults.GlossaryID, "glossary", 0, "glossary `id` to use")
+	fs.StringVar(&f.defaults.CallbackURL, "callback", "", "callback `url`")
+	return f
+}
+
+// records reads the job records from -file, or makes one per target language for the text in args or on stdin.
+// stdinUsed reports whether standard input was read.
+func (f *jobFlags) records(e *env, args []string) (records []jobRecord, stdinUsed bool, err error) {
+	if f.file != "" {
+		if len(args) != 0 {
+			return nil, false, fmt.Errorf("%w: text arguments cannot be used with -file", errUsage)
+		}
+		if strings.Contains(f.defaults.Target, ",") {
+			return nil, false, fmt.Errorf("%w: -to takes a single target language with -file; give each row its target instead", errUsage)
+		}
+		format := f.format
+		if format == "" {
+			if format, err = inputFormat(f.file); err != nil {
+				return nil, false, fmt.Errorf("%w: %v", errUsage, err)
+			}
+		}
+		in, err := os.Open(f.file)
+		if err != nil {
+			return nil, false, err
+		}
+		defer in.Close()
+		if records, err = readRecords(in, format); err != nil {
+			return nil, false, fmt.Errorf("reading %s: %v", f.file, err)
+		}
+		if len(records) == 0 {
+			return nil, false, fmt.Errorf("no jobs in %s", f.file)
+		}
+		for i := range records {
+			records[i].defaults(&f.defaults)
+		}
+		return records, false, nil
+	}
+	text := strings.Join(args, " ")
+	if len(args) == 0 || text == "-" {
+		b, err := io.ReadAll(e.stdin)
+		if err != nil {
+			return nil, true, err
+		}
+		text, stdinUsed = string(b), true
+	}
+	if f.defaults.Target == "" {
+		return nil, stdinUsed, fmt.Errorf("%w: -to is required", errUsage)
+	}
+	for i, target := range strings.Split(f.defaults.Target, ",") {
+		rec := f.defaults
+		rec.Row, rec.Text, rec.Target = i+1, text, strings.TrimSpace(target)
+		records = append(records, rec)
+	}
+	return records, stdinUsed, nil
+}
+
+// jobs creates the JobRequest of every record, reporting errors by row.
+func jobs(records []jobRecord, options ...gengo.JobOption) ([]*gengo.JobRequest, error) {
+	jobs := make([]*gengo.JobRequest, len(records))
+	for i := range records {
+		job, err := records[i].job(options...)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %v", records[i].Row, err)
+		}
+		jobs[i] = job
+	}
+	return jobs, nil
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/trinchan/gengo"
+)
+
+var jobsCommand = &command{
+	name:    "jobs",
+	summary: "list and act on translation jobs",
+	subcommands: []*command{
+		{name: "list", summary: "list recent jobs", setup: jobsList},
+		{name: "get", args: "id...", summary: "show jobs", setup: noFlagsIDs(jobsGet)},
+		{name: "approve", args: "id...", summary: "approve reviewable jobs", setup: jobsApprove},
+		{name: "revise", args: "id...", summary: "send jobs back to the translator for revision", setup: jobsRevise},
+		{name: "reject", args: "id...", summary: "reject jobs, solving their captchas interactively", setup: jobsReject},
+		{name: "archive", args: "id...", summary: "archive approved jobs", setup: noFlagsIDs(jobsArchive)},
+		{name: "cancel", args: "id...", summary: "cancel jobs which have not been started", setup: noFlagsIDs(jobsCancel)},
+		{name: "comments", args: "id", summary: "show or add to a job's comment thread", setup: jobsComments},
+		{name: "revisions", args: "id", summary: "list a job's revisions", setup: jobsRevisions},
+		{name: "feedback", args: "id...", summary: "show the feedback left on an approved job", setup: noFlagsIDs(jobsFeedback)},
+	},
+}
+
+// noFlagsIDs is the setup of commands taking IDs as arguments and no flags.
+func noFlagsIDs(run func(e *env, ids []int) error) func(*flag.FlagSet) runFunc {
+	return func(*flag.FlagSet) runFunc {
+		return func(e *env, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			return run(e, ids)
+		}
+	}
+}
+
+// getJobs fetches jobs by ID.
+func getJobs(c *gengo.Client, ids []int) ([]gengo.GetJobResponse, error) {
+	r, err := c.GetJobsByID(gengo.NewGetJobsByIDRequest(ids...))
+	if err != nil {
+		return nil, err
+	}
+	return r.Jobs, nil
+}
+
+// listJobIDs lists the IDs of every job GetJobs() lists with the options, oldest first, a full page at a time. If
+// not every job could be listed, it returns those which were with a *gengo.IncompleteListError.
+func listJobIDs(c *gengo.Client, options ...gengo.GetJobsRequestOption) ([]int, error) {
+	options = append([]gengo.GetJobsRequestOption{gengo.WithCount(gengo.MaxJobsPerPage)}, options...)
+	r, err := c.ListJobs(gengo.NewGetJobsRequest(options...))
+	if r == nil {
+		return nil, err
+	}
+	ids := make([]int, len(r.Jobs))
+	for i, j := range r.Jobs {
+		ids[i] = int(j.ID)
+	}
+	return ids, err
+}
+
+// warnIncomplete reports a listing which could not reach every job on stderr instead of failing, since the jobs
+// listed are still of use. Other errors are returned.
+func warnIncomplete(e *env, err error) error {
+	if _, ok := err.(*gengo.IncompleteListError); ok {
+		fmt.Fprintf(e.stderr, "Warning: %v\n", err)
+		return nil
+	}
+	return err
+}
+
+func jobsList(fs *flag.FlagSet) runFunc {
+	status := fs.String("status", "", "only list jobs with the `status`")
+	after := fs.String("after", "", "only list jobs created after the `time`, as a date, RFC 3339 time or UNIX time")
+	count := fs.Int("count", 0, "list at most `n` jobs")
+	idsOnly := fs.Bool("ids", false, "only list job IDs and creation times, without fetching each job")
+	return func(e *env, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+		}
+		var options []gengo.GetJobsRequestOption
+		if *status != "" {
+			options = append(options, gengo.WithStatus(*status))
+		}
+		if *after != "" {
+			t, err := parseTime(*after)
+			if err != nil {
+				return fmt.Errorf("%w: -after: %v", errUsage, err)
+			}
+			options = append(options, gengo.WithTimestampAfter(gengo.Time(t)))
+		}
+		if *count > 0 {
+			options = append(options, gengo.WithCount(*count))
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		r, err := c.GetJobs(gengo.NewGetJobsRequest(options...))
+		if err != nil {
+			return err
+		}
+		if *idsOnly {
+			t := newTable("job_id", "ctime")
+			for _, j := range r.Jobs {
+				t.add(j.ID, j.Ctime)
+			}
+			return e.print(r.Jobs, t)
+		}
+		ids := make([]int, len(r.Jobs))
+		for i, j := range r.Jobs {
+			ids[i] = int(j.ID)
+		}
+		jobs, err := getJobs(c, ids)
+		if err != nil {
+			return err
+		}
+		return e.print(jobs, jobsTable(jobs))
+	}
+}
+
+// parseTime parses a date, an RFC 3339 time or UNIX seconds.
+func parseTime(s string) (time.Time, error) {
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func jobsGet(e *env, ids []int) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	jobs, err := getJobs(c, ids)
+	if err != nil {
+		return err
+	}
+	return e.print(jobs, jobsTable(jobs))
+}
+
+// printAction reports the jobs or orders an action was taken on.
+func (e *env) printAction(action string, ids ...int) error {
+	return e.print(actionResult{Action: action, IDs: ids}, actionTable(action, ids...))
+}
+
+func jobsApprove(fs *flag.FlagSet) runFunc {
+	rating := fs.Int("rating", 0, "`rating` of the translation, from 1 to 5")
+	forTranslator := fs.String("for-translator", "", "`comment` for the translator")
+	forGengo := fs.String("for-gengo", "", "`comment` for Gengo")
+	public := fs.Bool("public", false, "allow Gengo to publish the feedback")
+	return func(e *env, args []string) error {
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		if *rating < 0 || *rating > 5 {
+			return fmt.Errorf("%w: -rating must be from 1 to 5", errUsage)
+		}
+		var options []gengo.ApproveJobOption
+		if *rating > 0 {
+			options = append(options, gengo.WithRating(*rating))
+		}
+		if *forTranslator != "" {
+			options = append(options, gengo.WithTranslatorComment(*forTranslator))
+		}
+		if *forGengo != "" {
+			options = append(options, gengo.WithGengoComment(*forGengo))
+		}
+		if *public {
+			options = append(options, gengo.WithPublicComment(true))
+		}
+		reqs := make([]*gengo.ApproveJobRequest, len(ids))
+		for i, id := range ids {
+			reqs[i] = gengo.NewApproveJobRequest(id, options...)
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		if err := c.ApproveJobs(gengo.NewApproveJobsRequest(reqs...)); err != nil {
+			return err
+		}
+		return e.printAction("approved", ids...)
+	}
+}
+
+func jobsRevise(fs *flag.FlagSet) runFunc {
+	comment := fs.String("comment", "", "`comment` explaining what to revise (required)")
+	return func(e *env, args []string) error {
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		if *comment == "" {
+			return fmt.Errorf("%w: -comment is required", errUsage)
+		}
+		reqs := make([]*gengo.ReviseJobRequest, len(ids))
+		for i, id := range ids {
+			reqs[i] = gengo.NewReviseJobRequest(id, gengo.WithRevisionComment(*comment))
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		if err := c.ReviseJobs(gengo.NewReviseJobsRequest(reqs...)); err != nil {
+			return err
+		}
+		return e.printAction("revising", ids...)
+	}
+}
+
+func jobsReject(fs *flag.FlagSet) runFunc {
+	reason := fs.String("reason", gengo.RejectionReasonQuality, "`reason`: quality, incomplete or other")
+	comment := fs.String("comment", "", "`comment` for the translator (required)")
+	followUp := fs.String("follow-up", gengo.FollowUpRequeue, "what happens next: `requeue` or cancel")
+	captcha := fs.String("captcha", "", "captcha `text`, when rejecting a single job whose captcha was already read")
+	return func(e *env, args []string) error {
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		if *captcha != "" && len(ids) > 1 {
+			return fmt.Errorf("%w: -captcha can only be used with a single job", errUsage)
+		}
+		reqs := make([]*gengo.RejectJobRequest, len(ids))
+		for i, id := range ids {
+			reqs[i] = gengo.NewRejectJobRequest(id, *reason, *comment, *captcha, gengo.WithFollowUp(*followUp))
+			if err := reqs[i].Validate(); err != nil {
+				return fmt.Errorf("%w: %v", errUsage, err)
+			}
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		if *captcha != "" {
+			_, err = c.RejectJobs(gengo.NewRejectJobsRequest(reqs...))
+		} else {
+			solver := &gengo.PromptCaptchaSolver{In: e.stdin, Out: e.stderr}
+			_, err = c.RejectJobsWithCaptcha(solver, reqs...)
+		}
+		if err != nil {
+			return err
+		}
+		return e.printAction("rejected", ids...)
+	}
+}
+
+func jobsArchive(e *env, ids []int) error {
+	reqs := make([]*gengo.ArchiveJobRequest, len(ids))
+	for i, id := range ids {
+		reqs[i] = gengo.NewArchiveJobRequest(id)
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	if err := c.ArchiveJobs(gengo.NewArchiveJobsRequest(reqs...)); err != nil {
+		return err
+	}
+	return e.printAction("archived", ids...)
+}
+
+func jobsCancel(e *env, ids []int) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if err := c.CancelJob(gengo.NewCancelJobRequest(id)); err != nil {
+			if i > 0 {
+				e.printAction("canceled", ids[:i]...)
+			}
+			return fmt.Errorf("canceling job %d: %v", id, err)
+		}
+	}
+	return e.printAction("canceled", ids...)
+}
+
+func jobsComments(fs *flag.FlagSet) runFunc {
+	add := fs.String("add", "", "add a comment with the `text` before showing the thread")
+	return func(e *env, args []string) error {
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		if *add != "" {
+			if err := c.AddJobComment(gengo.NewAddJobCommentRequest(id, *add)); err != nil {
+				return err
+			}
+		}
+		r, err := c.JobComments(gengo.NewJobCommentsRequest(id))
+		if err != nil {
+			return err
+		}
+		return e.print(r.Thread, commentsTable(r.Thread))
+	}
+}
+
+func jobsRevisions(fs *flag.FlagSet) runFunc {
+	diff := fs.Bool("diff", false, "print the changes between revisions instead of listing them")
+	return func(e *env, args []string) error {
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		if *diff {
+			h, err := c.JobRevisionHistory(gengo.NewJobRevisionHistoryRequest(id))
+			if err != nil {
+				return err
+			}
+			if e.format == formatJSON {
+				return e.print(h, nil)
+			}
+			_, err = fmt.Fprint(e.stdout, h.Unified())
+			return err
+		}
+		r, err := c.JobRevisions(gengo.NewJobRevisionsRequest(id))
+		if err != nil {
+			return err
+		}
+		t := newTable("rev_id", "ctime")
+		for _, rev := range r.Revisions {
+			t.add(rev.ID, rev.Ctime)
+		}
+		return e.print(r.Revisions, t)
+	}
+}
+
+func jobsFeedback(e *env, ids []int) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	t := newTable("job_id", "rating", "for_translator")
+	feedback := make(map[int]gengo.Feedback, len(ids))
+	for _, id := range ids {
+		r, err := c.JobFeedback(gengo.NewJobFeedbackRequest(id))
+		if err != nil {
+			return fmt.Errorf("retrieving feedback for job %d: %v", id, err)
+		}
+		feedback[id] = r.Feedback
+		t.add(id, r.Feedback.Rating, r.Feedback.Comment)
+	}
+	return e.print(feedback, t)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/l10n"
+)
+
+// defaultProject is the project file l10n commands read unless -project is set.
+const defaultProject = "gengo-l10n.json"
+
+var l10nCommand = &command{
+	name:    "l10n",
+	summary: "keep the resource files of a localization project translated",
+	subcommands: []*command{
+		{name: "status", summary: "count the translated, new, changed and pending strings of every file", setup: l10nStatus},
+		{name: "push", summary: "submit the new and changed strings of the source locale", setup: l10nPush},
+		{name: "pull", summary: "write approved translations into the target files", setup: l10nPull},
+	},
+}
+
+// syncer loads the project of -project and its state. The client is only created when needed.
+func (e *env) syncer(project string, needClient bool) (*l10n.Syncer, error) {
+	p, err := l10n.LoadProject(project)
+	if err != nil {
+		return nil, err
+	}
+	var c *gengo.Client
+	if needClient {
+		if c, err = e.client(); err != nil {
+			return nil, err
+		}
+	}
+	return l10n.NewSyncer(c, p)
+}
+
+func l10nStatus(fs *flag.FlagSet) runFunc {
+	project := fs.String("project", defaultProject, "the project `file`")
+	return func(e *env, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+		}
+		s, err := e.syncer(*project, false)
+		if err != nil {
+			return err
+		}
+		statuses, err := s.Status()
+		if err != nil {
+			return err
+		}
+		t := newTable("file", "locale", "total", "translated", "new", "changed", "pending")
+		for _, st := range statuses {
+			t.add(st.File, st.Locale, st.Total, st.Translated, st.New, st.Changed, st.Pending)
+		}
+		return e.print(statuses, t)
+	}
+}
+
+func l10nPush(fs *flag.FlagSet) runFunc {
+	project := fs.String("project", defaultProject, "the project `file`")
+	dryRun := fs.Bool("dry-run", false, "list the strings to submit without submitting them")
+	yes := fs.Bool("yes", false, "submit without asking for confirmation")
+	return func(e *env, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+		}
+		s, err := e.syncer(*project, !*dryRun)
+		if err != nil {
+			return err
+		}
+		changes, err := s.Plan()
+		if err != nil {
+			return err
+		}
+		t := newTable("file", "locale", "key", "reason", "text")
+		for _, c := range changes {
+			t.add(c.File, c.Locale, c.Unit.Key, c.Reason, c.Unit.Text)
+		}
+		if len(changes) == 0 || *dryRun {
+			if len(changes) == 0 {
+				fmt.Fprintln(e.stderr, "Nothing to submit.")
+			}
+			return e.print(changes, t)
+		}
+		if !*yes {
+			if err := writeTable(e.stderr, t); err != nil {
+				return err
+			}
+			jobs, err := s.Jobs(changes)
+			if err != nil {
+				return err
+			}
+			quote, err := s.Client.QuoteText(gengo.NewQuoteTextRequest(jobs...))
+			if err != nil {
+				return fmt.Errorf("quoting jobs: %v", err)
+			}
+			total, err := quote.Total()
+			if err != nil {
+				return err
+			}
+			ok, err := e.confirm(fmt.Sprintf("Submit %d jobs for %s?", len(jobs), total))
+			if err != nil || !ok {
+				return err
+			}
+		}
+		r, err := s.Submit(changes)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stderr, "Submitted order %d with %d jobs\n", r.OrderID, len(changes))
+		return e.print(changes, t)
+	}
+}
+
+func l10nPull(fs *flag.FlagSet) runFunc {
+	project := fs.String("project", defaultProject, "the project `file`")
+	return func(e *env, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+		}
+		s, err := e.syncer(*project, true)
+		if err != nil {
+			return err
+		}
+		r, err := s.Pull()
+		if err != nil {
+			return err
+		}
+		t := newTable("file", "locale", "key", "job_id", "result")
+		for _, tr := range r.Written {
+			t.add(tr.File, tr.Locale, tr.Key, tr.JobID, "written")
+		}
+		for _, tr := range r.Canceled {
+			t.add(tr.File, tr.Locale, tr.Key, tr.JobID, "canceled")
+		}
+		fmt.Fprintf(e.stderr, "%d translations written, %d canceled, %d pending\n", len(r.Written), len(r.Canceled), r.Pending)
+		return e.print(r, t)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestL10n(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "gengo-l10n.json")
+	files := map[string]string{
+		project:                       `{"source_locale":"en","locales":["ja"],"files":[{"source":"{locale}.json"}]}`,
+		filepath.Join(dir, "en.json"): `{"hello": "Hello", "bye": "Bye"}`,
+		filepath.Join(dir, "ja.json"): `{"hello": "こんにちは"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	routes := map[string]string{
+		"POST /translate/service/quote": `{"jobs":[{"credits":"0.06","unit_count":1,"currency":"USD","eta":3600}]}`,
+		"POST /translate/jobs":          `{"order_id":7,"job_count":1,"credits_used":"0.06","currency":"USD"}`,
+		"GET /translate/order/7":        `{"order":{"order_id":"7","jobs_approved":["11"]}}`,
+	}
+
+	e := newTestEnv(routes)
+	if code := run(e.env, []string{"l10n", "status", "-project", project}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	want := "FILE           LOCALE  TOTAL  TRANSLATED  NEW  CHANGED  PENDING\n" +
+		"{locale}.json  ja      2      1           1    0        0\n"
+	if e.stdout.String() != want {
+		t.Errorf("status output =\n%s\nwant\n%s", e.stdout, want)
+	}
+
+	e = newTestEnv(routes)
+	e.stdin = strings.NewReader("n\n")
+	if code := run(e.env, []string{"l10n", "push", "-project", project}); code != 0 || e.api.Len() != 1 {
+		t.Fatalf("exit %d with %d requests: %s", code, e.api.Len(), e.stderr)
+	}
+	if !strings.Contains(e.stderr.String(), "Submit 1 jobs for 0.06 USD?") || !strings.Contains(e.stderr.String(), "Not submitted.") {
+		t.Errorf("unexpected confirmation:\n%s", e.stderr)
+	}
+
+	e = newTestEnv(routes)
+	if code := run(e.env, []string{"l10n", "push", "-project", project, "-yes"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stderr.String(), "Submitted order 7 with 1 jobs") || !strings.Contains(e.stdout.String(), "bye") {
+		t.Errorf("unexpected push output:\n%s%s", e.stderr, e.stdout)
+	}
+	state, err := os.ReadFile(filepath.Join(dir, ".gengo-l10n-state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st struct {
+		Files map[string]map[string]map[string]struct{ Pending struct{ Hash string } }
+	}
+	if err := json.Unmarshal(state, &st); err != nil {
+		t.Fatal(err)
+	}
+	hash := st.Files["{locale}.json"]["ja"]["bye"].Pending.Hash
+
+	routes["GET /translate/jobs/11"] = `{"jobs":[{"job_id":"11","slug":"bye","status":"approved","lc_src":"en","lc_tgt":"ja","body_tgt":"さようなら",` +
+		`"custom_data":"{\"file\":\"{locale}.json\",\"hash\":\"` + hash + `\"}"}]}`
+	e = newTestEnv(routes)
+	if code := run(e.env, []string{"l10n", "pull", "-project", project}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stderr.String(), "1 translations written, 0 canceled, 0 pending") {
+		t.Errorf("unexpected pull output:\n%s", e.stderr)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "ja.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"hello\": \"こんにちは\",\n  \"bye\": \"さようなら\"\n}"; string(b) != want {
+		t.Errorf("ja.json = %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,213 @@
+// Command gengo is a command line client for the Gengo API.
+//
+// Usage:
+//
+//	gengo [-profile name] [-config file] [-o table|json|csv] command [subcommand] [flags] [args]
+//
+// Run "gengo help" for the list of commands. Credentials are read from the GENGO_PUBLIC_KEY and
+// GENGO_PRIVATE_KEY environment variables, or from a profile in the config file.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trinchan/gengo"
+)
+
+// command is a node of the command tree. Groups have subcommands; leaves have setup.
+type command struct {
+	name    string
+	args    string
+	summary string
+	// setup registers the command's flags and returns the function running it.
+	setup       func(fs *flag.FlagSet) runFunc
+	subcommands []*command
+}
+
+type runFunc func(e *env, args []string) error
+
+// errUsage is returned by commands called with invalid arguments, so that their usage is printed.
+var errUsage = errors.New("invalid usage")
+
+// env is the state shared by every command of a run.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+	sleep  func(time.Duration)
+	now    func() time.Time
+
+	configPath string
+	profile    string
+	format     string
+
+	// transport replaces the HTTP transport of the client, for tests.
+	transport http.RoundTripper
+	c         *gengo.Client
+}
+
+// client returns the Gengo client for the selected profile, creating it on first use.
+func (e *env) client() (*gengo.Client, error) {
+	if e.c != nil {
+		return e.c, nil
+	}
+	p, err := loadProfile(e.configPath, e.profile, e.getenv)
+	if err != nil {
+		return nil, err
+	}
+	e.c = gengo.New(p.PublicKey, p.PrivateKey, p.baseURL())
+	if e.transport != nil {
+		e.c.SetRoundTripper(e.transport)
+	}
+	return e.c, nil
+}
+
+var commands = []*command{
+	accountCommand,
+	languagesCommand,
+	pairsCommand,
+	quoteCommand,
+	submitCommand,
+	jobsCommand,
+	reviewCommand,
+	downloadCommand,
+	reportCommand,
+	orderCommand,
+	glossaryCommand,
+	watchCommand,
+	l10nCommand,
+}
+
+func main() {
+	e := &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv, sleep: time.Sleep, now: time.Now}
+	os.Exit(run(e, os.Args[1:]))
+}
+
+// run runs the command named by args and returns the process exit code.
+func run(e *env, args []string) int {
+	fs := flag.NewFlagSet("gengo", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.profile, "profile", e.getenv("GENGO_PROFILE"), "config `profile` to use")
+	fs.StringVar(&e.configPath, "config", e.getenv("GENGO_CONFIG"), "config `file` (default "+defaultConfigPath()+")")
+	fs.StringVar(&e.format, "o", formatTable, "output `format`: table, json or csv")
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "usage: gengo [flags] command [subcommand] [flags] [args]")
+		fs.PrintDefaults()
+		printCommands(e.stderr, "gengo", commands)
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !validFormat(e.format) {
+		fmt.Fprintf(e.stderr, "gengo: unknown output format %q\n", e.format)
+		return 2
+	}
+	args = fs.Args()
+	if len(args) == 0 || args[0] == "help" {
+		fs.Usage()
+		return 2
+	}
+	return dispatch(e, "gengo", commands, args)
+}
+
+func dispatch(e *env, path string, cmds []*command, args []string) int {
+	cmd := find(cmds, args[0])
+	if cmd == nil {
+		fmt.Fprintf(e.stderr, "%s: unknown command %q\n", path, args[0])
+		printCommands(e.stderr, path, cmds)
+		return 2
+	}
+	path += " " + cmd.name
+	if cmd.setup == nil {
+		if len(args) < 2 || args[1] == "help" {
+			printCommands(e.stderr, path, cmd.subcommands)
+			return 2
+		}
+		return dispatch(e, path, cmd.subcommands, args[1:])
+	}
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: %s [flags] %s\n%s\n", path, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	runCmd := cmd.setup(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if err := runCmd(e, fs.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(e.stderr, "%s: %v\n", path, err)
+			fs.Usage()
+			return 2
+		}
+		fmt.Fprintf(e.stderr, "%s: %v\n", path, err)
+		var exit *exitError
+		if errors.As(err, &exit) {
+			return exit.code
+		}
+		return 1
+	}
+	return 0
+}
+
+func find(cmds []*command, name string) *command {
+	for _, c := range cmds {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func printCommands(w io.Writer, path string, cmds []*command) {
+	fmt.Fprintf(w, "\ncommands of %s:\n", path)
+	for _, c := range cmds {
+		name := c.name
+		if c.setup == nil {
+			names := make([]string, len(c.subcommands))
+			for i, s := range c.subcommands {
+				names[i] = s.name
+			}
+			name += " " + strings.Join(names, "|")
+		}
+		fmt.Fprintf(w, "  %-50s %s\n", name, c.summary)
+	}
+}
+
+// parseIDs parses job or order IDs from arguments, requiring at least one.
+func parseIDs(args []string) ([]int, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: missing id", errUsage)
+	}
+	ids := make([]int, len(args))
+	for i, a := range args {
+		id, err := strconv.Atoi(a)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid id %q", errUsage, a)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// parseID parses exactly one ID from arguments.
+func parseID(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%w: expected one id", errUsage)
+	}
+	ids, err := parseIDs(args)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo/internal/gengotest"
+)
+
+type testEnv struct {
+	*env
+	api    *gengotest.API
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+}
+
+func newTestEnv(routes map[string]string) *testEnv {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	vars := map[string]string{"GENGO_PUBLIC_KEY": "public", "GENGO_PRIVATE_KEY": "private"}
+	api := gengotest.New(routes)
+	clock := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &testEnv{
+		env: &env{
+			stdin:     strings.NewReader(""),
+			stdout:    stdout,
+			stderr:    stderr,
+			getenv:    func(k string) string { return vars[k] },
+			sleep:     func(d time.Duration) { clock = clock.Add(d) },
+			now:       func() time.Time { return clock },
+			transport: api,
+		},
+		api:    api,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+func TestAccountBalance(t *testing.T) {
+	routes := map[string]string{"GET /account/balance": `{"credits":"25.32","currency":"USD"}`}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table", "CREDITS  CURRENCY\n25.32    USD\n"},
+		{"csv", "credits,currency\n25.32,USD\n"},
+		{"json", "{\n  \"credits\": \"25.32\",\n  \"currency\": \"USD\"\n}\n"},
+	}
+	for _, tt := range tests {
+		e := newTestEnv(routes)
+		if code := run(e.env, []string{"-o", tt.format, "account", "balance"}); code != 0 {
+			t.Fatalf("%s: exit %d: %s", tt.format, code, e.stderr)
+		}
+		if e.stdout.String() != tt.want {
+			t.Errorf("%s output = %q, want %q", tt.format, e.stdout, tt.want)
+		}
+	}
+}
+
+func TestJobsCommands(t *testing.T) {
+	e := newTestEnv(map[string]string{
+		"GET /translate/jobs":           `[{"job_id":"1","ctime":1580474096},{"job_id":"2","ctime":1580474097}]`,
+		"GET /translate/jobs/1,2":       `{"jobs":[{"job_id":"1","order_id":"9","lc_src":"en","lc_tgt":"ja","tier":"standard","status":"reviewable","unit_count":"3","credits":"0.15"},{"job_id":"2","order_id":"9","lc_src":"en","lc_tgt":"fr","tier":"pro","status":"approved"}]}`,
+		"PUT /translate/jobs":           `null`,
+		"GET /translate/job/1/comments": `{"thread":[{"body":"Looks good","author":"customer","ctime":1580474096}]}`,
+	})
+	if code := run(e.env, []string{"-o", "csv", "jobs", "list", "-status", "reviewable"}); code != 0 {
+		t.Fatalf("jobs list: exit %d: %s", code, e.stderr)
+	}
+	want := "job_id,order_id,source,target,tier,status,units,credits,ctime\n" +
+		"1,9,en,ja,standard,reviewable,3,0.15,\n" +
+		"2,9,en,fr,pro,approved,0,0,\n"
+	if e.stdout.String() != want {
+		t.Errorf("jobs list = %q, want %q", e.stdout, want)
+	}
+	if q := e.api.Requests()[0].URL.Query().Get("status"); q != "reviewable" {
+		t.Errorf("status filter = %q", q)
+	}
+
+	e.stdout.Reset()
+	if code := run(e.env, []string{"jobs", "approve", "-rating", "5", "1", "2"}); code != 0 {
+		t.Fatalf("jobs approve: exit %d: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stdout.String(), "1   approved") {
+		t.Errorf("jobs approve output = %q", e.stdout)
+	}
+	last := e.api.Body(e.api.Len() - 1)
+	if !strings.Contains(last, "approve") || !strings.Contains(last, "rating") {
+		t.Errorf("jobs approve sent %s", last)
+	}
+
+	e.stdout.Reset()
+	if code := run(e.env, []string{"jobs", "comments", "1"}); code != 0 {
+		t.Fatalf("jobs comments: exit %d: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stdout.String(), "2020-01-31T12:34:56Z  customer  Looks good") {
+		t.Errorf("jobs comments output = %q", e.stdout)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"nope"},
+		{"jobs"},
+		{"jobs", "approve"},
+		{"jobs", "approve", "x"},
+		{"jobs", "revise", "1"},
+		{"quote", "-from", "en", "hello"},
+		{"-o", "xml", "languages"},
+	}
+	for _, args := range tests {
+		e := newTestEnv(nil)
+		if code := run(e.env, args); code != 2 {
+			t.Errorf("run(%q) = %d, want 2", args, code)
+		}
+		if e.api.Len() != 0 {
+			t.Errorf("run(%q) called the API", args)
+		}
+	}
+	e := newTestEnv(nil)
+	if code := run(e.env, []string{"account", "me"}); code != 1 || !strings.Contains(e.stderr.String(), "no fake response") {
+		t.Errorf("API errors should exit 1, got %d: %s", code, e.stderr)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := `{"default_profile":"sandbox","profiles":{
+		"sandbox":{"public_key":"sp","private_key":"ss"},
+		"jp":{"public_key":"jp","private_key":"js","production":true}}}`
+	if err := os.WriteFile(path, []byte(cfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+	noEnv := func(string) string { return "" }
+	p, err := loadProfile(path, "", noEnv)
+	if err != nil || p.PublicKey != "sp" || p.baseURL() != "http://api.sandbox.gengo.com/v2" {
+		t.Errorf("default profile = %+v, %v", p, err)
+	}
+	p, err = loadProfile(path, "jp", noEnv)
+	if err != nil || p.PublicKey != "jp" || p.baseURL() != "http://api.gengo.com/v2" {
+		t.Errorf("jp profile = %+v, %v", p, err)
+	}
+	if _, err := loadProfile(path, "missing", noEnv); err == nil {
+		t.Error("expected an error loading a missing profile")
+	}
+	vars := map[string]string{"GENGO_PUBLIC_KEY": "ep", "GENGO_PRIVATE_KEY": "es"}
+	getenv := func(k string) string { return vars[k] }
+	if p, err := loadProfile(path, "", getenv); err != nil || p.PublicKey != "ep" {
+		t.Errorf("environment credentials = %+v, %v", p, err)
+	}
+	if p, err := loadProfile(path, "jp", getenv); err != nil || p.PublicKey != "jp" {
+		t.Errorf("a named profile should win over the environment, got %+v, %v", p, err)
+	}
+	if _, err := loadProfile(filepath.Join(t.TempDir(), "none.json"), "", noEnv); err == nil {
+		t.Error("expected an error without any credentials")
+	}
+}
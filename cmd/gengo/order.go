@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/trinchan/gengo"
+)
+
+var orderCommand = &command{
+	name:    "order",
+	summary: "show and act on orders",
+	subcommands: []*command{
+		{name: "get", args: "id", summary: "show an order and the state of its jobs", setup: orderGet},
+		{name: "cancel", args: "id...", summary: "cancel orders whose jobs have not been started", setup: noFlagsIDs(orderCancel)},
+		{name: "comments", args: "id", summary: "show or add to an order's comment thread", setup: orderComments},
+	},
+}
+
+func orderGet(fs *flag.FlagSet) runFunc {
+	jobs := fs.Bool("jobs", false, "list the order's jobs")
+	return func(e *env, args []string) error {
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		r, err := c.GetOrder(gengo.NewOrderGetRequest(id))
+		if err != nil {
+			return err
+		}
+		if *jobs {
+			jr, err := c.OrderJobs(gengo.NewOrderJobsRequest(id))
+			if err != nil {
+				return err
+			}
+			return e.print(jr.Jobs, jobsTable(jr.Jobs))
+		}
+		o := r.Order
+		t := newTable("order_id", "status", "progress", "jobs", "units", "credits", "currency",
+			"queued", "available", "pending", "reviewable", "revising", "approved", "held", "cancelled")
+		t.add(o.OrderID, o.Status(), fmt.Sprintf("%.0f%%", o.Progress()), o.Count, o.Units, o.Credits.Amount(), o.Currency,
+			o.JobsQueued, o.JobsAvailable, o.JobsPending, o.JobsReviewable, o.JobsRevising, o.JobsApproved,
+			o.JobsHeld, o.JobsCancelled)
+		return e.print(o, t)
+	}
+}
+
+func orderCancel(e *env, ids []int) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if err := c.CancelOrder(gengo.NewOrderCancelRequest(id)); err != nil {
+			if i > 0 {
+				e.printAction("canceled", ids[:i]...)
+			}
+			return err
+		}
+	}
+	return e.printAction("canceled", ids...)
+}
+
+func orderComments(fs *flag.FlagSet) runFunc {
+	add := fs.String("add", "", "add a comment with the `text` before showing the thread")
+	return func(e *env, args []string) error {
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		if *add != "" {
+			if err := c.AddOrderComment(gengo.NewAddOrderCommentRequest(id, *add)); err != nil {
+				return err
+			}
+		}
+		r, err := c.OrderComments(gengo.NewOrderCommentsRequest(id))
+		if err != nil {
+			return err
+		}
+		return e.print(r.Thread, commentsTable(r.Thread))
+	}
+}
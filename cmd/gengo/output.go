@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/trinchan/gengo"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+func validFormat(f string) bool {
+	return f == formatTable || f == formatJSON || f == formatCSV
+}
+
+// table is the tabular form of a command's output, used by the table and CSV formats.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func newTable(header ...string) *table {
+	return &table{header: header}
+}
+
+// add appends a row, formatting each value with cell.
+func (t *table) add(values ...interface{}) {
+	row := make([]string, len(values))
+	for i, v := range values {
+		row[i] = cell(v)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// cell formats a value for a table. Times are RFC 3339 in UTC and empty times are blank.
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case gengo.Time:
+		return cell(time.Time(v))
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case gengo.Float64:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64)
+	case gengo.Bool:
+		return strconv.FormatBool(bool(v))
+	case []gengo.Int:
+		s := make([]string, len(v))
+		for i, id := range v {
+			s[i] = strconv.Itoa(int(id))
+		}
+		return strings.Join(s, ",")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// print writes v as JSON, or t as a table or CSV, depending on the output format.
+func (e *env) print(v interface{}, t *table) error {
+	switch e.format {
+	case formatJSON:
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatCSV:
+		w := csv.NewWriter(e.stdout)
+		if err := w.Write(t.header); err != nil {
+			return err
+		}
+		if err := w.WriteAll(t.rows); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+	return writeTable(e.stdout, t)
+}
+
+// writeTable writes t aligned in columns, with each cell on a single line.
+func writeTable(out io.Writer, t *table) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(t.header, "\t")))
+	for _, row := range t.rows {
+		for i, c := range row {
+			row[i] = strings.Join(strings.Fields(c), " ")
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// jobsTable lists jobs one per row.
+func jobsTable(jobs []gengo.GetJobResponse) *table {
+	t := newTable("job_id", "order_id", "source", "target", "tier", "status", "units", "credits", "ctime")
+	for _, j := range jobs {
+		t.add(j.ID, j.OrderID, j.Source, j.Target, j.Tier, j.Status, j.UnitCount, j.Credits, j.Ctime)
+	}
+	return t
+}
+
+// commentsTable lists a comment thread.
+func commentsTable(comments []gengo.Comment) *table {
+	t := newTable("ctime", "author", "body")
+	for _, c := range comments {
+		t.add(c.Ctime, c.Author, c.Body)
+	}
+	return t
+}
+
+// actionTable lists the jobs or orders an action was taken on.
+func actionTable(action string, ids ...int) *table {
+	t := newTable("id", "action")
+	for _, id := range ids {
+		t.add(id, action)
+	}
+	return t
+}
+
+// actionResult is the JSON form of actionTable.
+type actionResult struct {
+	Action string `json:"action"`
+	IDs    []int  `json:"ids"`
+}
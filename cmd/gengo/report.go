@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/trinchan/gengo/report"
+)
+
+var reportCommand = &command{
+	name:    "report",
+	summary: "report units, credits, times to last delivery and redelivery and rejection rates of the whole job history",
+	setup: func(fs *flag.FlagSet) runFunc {
+		by := fs.String("by", "month", "comma separated `dimensions` to group jobs by: month, pair, tier or tag")
+		since := fs.String("since", "", "only report jobs created after the `time`, as a date, RFC 3339 time or UNIX time")
+		revisions := fs.Bool("revisions", false, "fetch the revisions of every job to report delivery times and redelivery rates (one request per job)")
+		markdown := fs.Bool("markdown", false, "write a Markdown table instead of the -o format")
+		out := fs.String("out", "", "write the report to `file` instead of standard output")
+		return func(e *env, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+			}
+			var dims []report.Dimension
+			for _, s := range strings.Split(*by, ",") {
+				d, err := report.ParseDimension(s)
+				if err != nil {
+					return fmt.Errorf("%w: -by: %v", errUsage, err)
+				}
+				dims = append(dims, d)
+			}
+			var options []report.FetcherOption
+			if *since != "" {
+				t, err := parseTime(*since)
+				if err != nil {
+					return fmt.Errorf("%w: -since: %v", errUsage, err)
+				}
+				options = append(options, report.WithSince(t))
+			}
+			if *revisions {
+				options = append(options, report.WithRevisions())
+			}
+			c, err := e.client()
+			if err != nil {
+				return err
+			}
+			balance, err := c.Balance()
+			if err != nil {
+				return err
+			}
+			jobs, err := report.NewFetcher(c, options...).Jobs()
+			if err := warnIncomplete(e, err); err != nil {
+				return err
+			}
+			r := report.New(jobs, balance.Currency, dims...)
+			if *out != "" {
+				f, err := os.Create(*out)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				e.stdout = f
+			}
+			switch {
+			case *markdown:
+				return r.WriteMarkdown(e.stdout)
+			case e.format == formatJSON:
+				return r.WriteJSON(e.stdout)
+			case e.format == formatCSV:
+				return r.WriteCSV(e.stdout)
+			}
+			return writeTable(e.stdout, reportTable(r))
+		}
+	},
+}
+
+// reportTable lists the rows of a report and its total, with rates as percentages.
+func reportTable(r *report.Report) *table {
+	var header []string
+	for _, d := range r.By {
+		header = append(header, string(d))
+	}
+	t := newTable(append(header, "jobs", "approved", "units", "credits", "rejected", "redelivered", "to last delivery")...)
+	add := func(keys []string, row *report.Row) {
+		values := make([]interface{}, 0, len(keys)+7)
+		for _, k := range keys {
+			values = append(values, k)
+		}
+		// Redeliveries and delivery times are only known with -revisions.
+		redelivered, delivery := "", ""
+		if row.Reviewed > 0 {
+			redelivered = fmt.Sprintf("%d (%.1f%%)", row.Redelivered, row.RedeliveryRate*100)
+		}
+		if row.AverageTimeToLastDelivery > 0 {
+			delivery = row.AverageTimeToLastDelivery.Round(time.Minute).String()
+		}
+		values = append(values, row.Jobs, row.Approved, row.Units, row.Credits,
+			fmt.Sprintf("%d (%.1f%%)", row.Rejected, row.RejectionRate*100), redelivered, delivery)
+		t.add(values...)
+	}
+	for _, row := range r.Rows {
+		add(row.Keys, row)
+	}
+	total := make([]string, len(r.By))
+	if len(total) > 0 {
+		total[0] = "total"
+	}
+	add(total, r.Total)
+	return t
+}
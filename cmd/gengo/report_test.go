@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	routes := map[string]string{
+		"GET /account/balance": `{"credits":"25.32","currency":"USD"}`,
+		"GET /translate/jobs":  `[{"job_id":"2","ctime":1706745600},{"job_id":"1","ctime":1706659200}]`,
+		"GET /translate/jobs/1,2": `{"jobs":[` +
+			`{"job_id":"2","lc_src":"en","lc_tgt":"ja","tier":"pro","status":"rejected","unit_count":"5","credits":"0.75","ctime":1706745600},` +
+			`{"job_id":"1","lc_src":"en","lc_tgt":"ja","tier":"standard","status":"approved","unit_count":"10","credits":"0.50","ctime":1706659200}]}`,
+	}
+	e := newTestEnv(routes)
+	if code := run(e.env, []string{"report", "-by", "pair"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	want := "PAIR   JOBS  APPROVED  UNITS  CREDITS   REJECTED   REDELIVERED  TO LAST DELIVERY\n" +
+		"en→ja  2     1         15     1.25 USD  1 (50.0%)               \n" +
+		"total  2     1         15     1.25 USD  1 (50.0%)               \n"
+	if e.stdout.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", e.stdout, want)
+	}
+
+	e = newTestEnv(routes)
+	if code := run(e.env, []string{"report", "-by", "month,tier", "-markdown"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stdout.String(), "| 2024-02 | pro | 1 | 0 | 5 | 0.75 | 1 | 100.0% |") {
+		t.Errorf("unexpected Markdown:\n%s", e.stdout)
+	}
+	if code := run(newTestEnv(routes).env, []string{"report", "-by", "week"}); code != 2 {
+		t.Errorf("unknown dimension = %d, want 2", code)
+	}
+}
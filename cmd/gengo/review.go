@@ -0,0 +1,518 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/trinchan/gengo"
+	"golang.org/x/term"
+	"golang.org/x/text/width"
+)
+
+// defaultReviewWidth is the screen width used when the width of the terminal is unknown.
+const defaultReviewWidth = 100
+
+const (
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyBackspace = 0x7f
+	keyEscape    = 0x1b
+)
+
+const reviewHelp = "1-5 approve with rating · a approve · r revise · c comment · n/→ next · p/← previous · q quit"
+
+var reviewCommand = &command{
+	name:    "review",
+	summary: "review translations interactively, approving, revising or commenting on each reviewable job",
+	setup: func(fs *flag.FlagSet) runFunc {
+		orderID := fs.Int("order", 0, "only review the jobs of the order `id`")
+		count := fs.Int("count", 0, "review at most `n` jobs")
+		return func(e *env, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+			}
+			c, err := e.client()
+			if err != nil {
+				return err
+			}
+			jobs, err := reviewableJobs(e, c, *orderID, *count)
+			if err != nil {
+				return err
+			}
+			if len(jobs) == 0 {
+				fmt.Fprintln(e.stderr, "No jobs to review.")
+				return nil
+			}
+			r := newReviewer(e, c, jobs)
+			restore, err := r.rawMode()
+			if err != nil {
+				return err
+			}
+			err = r.run()
+			restore()
+			if err != nil {
+				return err
+			}
+			return e.print(r.results, r.table())
+		}
+	},
+}
+
+// reviewableJobs lists the reviewable jobs, optionally of one order, oldest first. The jobs which could not be
+// listed are reported on stderr, to be reviewed once those listed are.
+func reviewableJobs(e *env, c *gengo.Client, orderID, count int) ([]gengo.GetJobResponse, error) {
+	var ids []int
+	if orderID != 0 {
+		r, err := c.GetOrder(gengo.NewOrderGetRequest(orderID))
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range r.Order.JobsReviewable {
+			ids = append(ids, int(id))
+		}
+	} else {
+		var err error
+		ids, err = listJobIDs(c, gengo.WithStatus(gengo.JobStatusReviewable))
+		if err := warnIncomplete(e, err); err != nil {
+			return nil, err
+		}
+	}
+	if count > 0 && count < len(ids) {
+		ids = ids[:count]
+	}
+	return getJobs(c, ids)
+}
+
+// reviewResult records what was done with a reviewed job.
+type reviewResult struct {
+	JobID   int    `json:"job_id"`
+	Action  string `json:"action"`
+	Rating  int    `json:"rating,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// reviewer is the state of an interactive review: the jobs, the one shown, and what was done with each.
+type reviewer struct {
+	e     *env
+	c     *gengo.Client
+	in    *bufio.Reader
+	out   io.Writer
+	width int
+	// live is set when reviewing on a terminal, which is then cleared before drawing each job.
+	live bool
+
+	jobs []gengo.GetJobResponse
+	pos  int
+	// actions is the action taken on each job by ID.
+	actions       map[int]string
+	comments      map[int][]gengo.Comment
+	orderComments map[int][]gengo.Comment
+	results       []reviewResult
+	// message is a note about the last action, shown under the job.
+	message string
+}
+
+func newReviewer(e *env, c *gengo.Client, jobs []gengo.GetJobResponse) *reviewer {
+	return &reviewer{
+		e:             e,
+		c:             c,
+		in:            bufio.NewReader(e.stdin),
+		out:           e.stderr,
+		width:         defaultReviewWidth,
+		jobs:          jobs,
+		actions:       map[int]string{},
+		comments:      map[int][]gengo.Comment{},
+		orderComments: map[int][]gengo.Comment{},
+	}
+}
+
+// rawMode switches a terminal on standard input to raw mode, so that keys are read as they are pressed.
+// The returned function restores the terminal.
+func (r *reviewer) rawMode() (func(), error) {
+	f, ok := r.e.stdin.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return func() {}, nil
+	}
+	state, err := term.MakeRaw(int(f.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	if out, ok := r.e.stderr.(*os.File); ok {
+		if w, _, err := term.GetSize(int(out.Fd())); err == nil && w > 0 {
+			r.width = w
+		}
+	}
+	r.live = true
+	// Raw mode also stops translating newlines, so lines must end with a carriage return.
+	r.out = &crlfWriter{w: r.e.stderr}
+	return func() { term.Restore(int(f.Fd()), state) }, nil
+}
+
+// crlfWriter writes "\r\n" for every "\n".
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c *crlfWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(c.w, strings.ReplaceAll(string(p), "\n", "\r\n")); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// run shows each job and handles keys until every job has been acted on or the review is quit.
+func (r *reviewer) run() error {
+	for {
+		if err := r.draw(); err != nil {
+			return err
+		}
+		key, err := r.key()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r.message = ""
+		job := &r.jobs[r.pos]
+		switch {
+		case key == 'q' || key == keyCtrlC || key == keyCtrlD:
+			return nil
+		case key == 'n' || key == 'j' || key == ' ' || key == keyRight:
+			r.move(1)
+		case key == 'p' || key == 'k' || key == keyLeft:
+			r.move(-1)
+		case key == 'a' || key >= '1' && key <= '5':
+			if r.acted(job) {
+				continue
+			}
+			rating := 0
+			if key != 'a' {
+				rating = int(key - '0')
+			}
+			r.approve(job, rating)
+		case key == 'r':
+			if r.acted(job) {
+				continue
+			}
+			comment, err := r.prompt("Revision comment: ")
+			if err != nil || comment == "" {
+				r.message = "Revision canceled."
+				continue
+			}
+			r.revise(job, comment)
+		case key == 'c':
+			comment, err := r.prompt("Comment: ")
+			if err != nil || comment == "" {
+				r.message = "Comment canceled."
+				continue
+			}
+			r.comment(job, comment)
+		default:
+			r.message = reviewHelp
+		}
+		if r.finished() {
+			r.draw()
+			return nil
+		}
+	}
+}
+
+// acted reports whether the job was already approved or sent for revision, which cannot be done twice.
+func (r *reviewer) acted(job *gengo.GetJobResponse) bool {
+	action, ok := r.actions[int(job.ID)]
+	if ok {
+		r.message = fmt.Sprintf("Job %d was already %s.", job.ID, action)
+	}
+	return ok
+}
+
+func (r *reviewer) approve(job *gengo.GetJobResponse, rating int) {
+	var options []gengo.ApproveJobOption
+	if rating > 0 {
+		options = append(options, gengo.WithRating(rating))
+	}
+	err := r.c.ApproveJobs(gengo.NewApproveJobsRequest(gengo.NewApproveJobRequest(int(job.ID), options...)))
+	if err != nil {
+		r.message = fmt.Sprintf("Approving job %d failed: %v", job.ID, err)
+		return
+	}
+	r.done(job, reviewResult{JobID: int(job.ID), Action: "approved", Rating: rating})
+}
+
+func (r *reviewer) revise(job *gengo.GetJobResponse, comment string) {
+	err := r.c.ReviseJobs(gengo.NewReviseJobsRequest(gengo.NewReviseJobRequest(int(job.ID), gengo.WithRevisionComment(comment))))
+	if err != nil {
+		r.message = fmt.Sprintf("Requesting a revision of job %d failed: %v", job.ID, err)
+		return
+	}
+	r.done(job, reviewResult{JobID: int(job.ID), Action: "revising", Comment: comment})
+}
+
+func (r *reviewer) comment(job *gengo.GetJobResponse, comment string) {
+	if err := r.c.AddJobComment(gengo.NewAddJobCommentRequest(int(job.ID), comment)); err != nil {
+		r.message = fmt.Sprintf("Commenting on job %d failed: %v", job.ID, err)
+		return
+	}
+	// Refetch the thread with the new comment when the job is drawn again.
+	delete(r.comments, int(job.ID))
+	r.results = append(r.results, reviewResult{JobID: int(job.ID), Action: "commented", Comment: comment})
+	r.message = fmt.Sprintf("Commented on job %d.", job.ID)
+}
+
+// done records the action on the job and moves to the next job not acted on.
+func (r *reviewer) done(job *gengo.GetJobResponse, result reviewResult) {
+	r.actions[result.JobID] = result.Action
+	r.results = append(r.results, result)
+	r.message = fmt.Sprintf("Job %d %s.", job.ID, result.Action)
+	for i := 1; i < len(r.jobs); i++ {
+		next := (r.pos + i) % len(r.jobs)
+		if _, ok := r.actions[int(r.jobs[next].ID)]; !ok {
+			r.pos = next
+			return
+		}
+	}
+}
+
+func (r *reviewer) move(delta int) {
+	r.pos = (r.pos + delta + len(r.jobs)) % len(r.jobs)
+}
+
+// finished reports whether every job was approved or sent for revision.
+func (r *reviewer) finished() bool {
+	return len(r.actions) == len(r.jobs)
+}
+
+// Arrow keys are read as escape sequences and returned as these runes from the Unicode private use area.
+const (
+	keyRight rune = 0xe000 + iota
+	keyLeft
+)
+
+// key reads a key press.
+func (r *reviewer) key() (rune, error) {
+	k, _, err := r.in.ReadRune()
+	if err != nil || k != keyEscape {
+		return k, err
+	}
+	// A lone escape is not followed by more input, so only escape sequences already read are looked for, rather
+	// than waiting for the next key.
+	if r.in.Buffered() < 2 {
+		return keyEscape, nil
+	}
+	if b, err := r.in.Peek(2); err == nil && b[0] == '[' {
+		r.in.Discard(2)
+		switch b[1] {
+		case 'C':
+			return keyRight, nil
+		case 'D':
+			return keyLeft, nil
+		}
+	}
+	return keyEscape, nil
+}
+
+// prompt reads a line of text, echoing it as it is typed. Ctrl-C or escape cancel it.
+func (r *reviewer) prompt(label string) (string, error) {
+	fmt.Fprint(r.out, "\n"+label)
+	var line []rune
+	for {
+		k, _, err := r.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch k {
+		case '\r', '\n':
+			fmt.Fprintln(r.out)
+			return strings.TrimSpace(string(line)), nil
+		case keyCtrlC, keyEscape:
+			return "", nil
+		case keyBackspace, '\b':
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				fmt.Fprint(r.out, "\b \b")
+			}
+		default:
+			if unicode.IsPrint(k) {
+				line = append(line, k)
+				if r.live {
+					fmt.Fprint(r.out, string(k))
+				}
+			}
+		}
+	}
+}
+
+// draw shows the current job: its source and target side by side, then the job and order comment threads.
+func (r *reviewer) draw() error {
+	job := &r.jobs[r.pos]
+	jobComments, err := r.jobComments(int(job.ID))
+	if err != nil {
+		return err
+	}
+	orderComments, err := r.orderThread(int(job.OrderID))
+	if err != nil {
+		return err
+	}
+	if r.live {
+		fmt.Fprint(r.out, ansiClear)
+	}
+	state := "reviewable"
+	if action, ok := r.actions[int(job.ID)]; ok {
+		state = action
+	}
+	fmt.Fprintf(r.out, "Job %d (%d/%d)  %s→%s  %s  order %d  %s\n\n",
+		job.ID, r.pos+1, len(r.jobs), job.Source, job.Target, job.Tier, job.OrderID, state)
+	column := (r.width - 3) / 2
+	source := wrap(job.BodySrc, column)
+	target := wrap(job.BodyTgt, column)
+	fmt.Fprintf(r.out, "%s │ %s\n", pad(fmt.Sprintf("Source (%s)", job.Source), column), fmt.Sprintf("Target (%s)", job.Target))
+	fmt.Fprintf(r.out, "%s─┼─%s\n", strings.Repeat("─", column), strings.Repeat("─", column))
+	for i := 0; i < len(source) || i < len(target); i++ {
+		var s, t string
+		if i < len(source) {
+			s = source[i]
+		}
+		if i < len(target) {
+			t = target[i]
+		}
+		fmt.Fprintf(r.out, "%s │ %s\n", pad(s, column), t)
+	}
+	r.drawComments("Job comments", jobComments)
+	r.drawComments("Order comments", orderComments)
+	fmt.Fprintf(r.out, "\n%s\n", reviewHelp)
+	if r.message != "" {
+		fmt.Fprintln(r.out, r.message)
+	}
+	return nil
+}
+
+func (r *reviewer) drawComments(title string, thread []gengo.Comment) {
+	if len(thread) == 0 {
+		return
+	}
+	fmt.Fprintf(r.out, "\n%s:\n", title)
+	for _, c := range thread {
+		when := ""
+		if t := time.Time(c.Ctime); !t.IsZero() {
+			when = " " + t.UTC().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(r.out, "  [%s%s] %s\n", c.Author, when, c.Body)
+	}
+}
+
+// jobComments returns the comment thread of a job, fetching it once.
+func (r *reviewer) jobComments(id int) ([]gengo.Comment, error) {
+	if thread, ok := r.comments[id]; ok {
+		return thread, nil
+	}
+	resp, err := r.c.JobComments(gengo.NewJobCommentsRequest(id))
+	if err != nil {
+		return nil, fmt.Errorf("retrieving comments of job %d: %v", id, err)
+	}
+	r.comments[id] = resp.Thread
+	return resp.Thread, nil
+}
+
+// orderThread returns the comment thread of an order, fetching it once.
+func (r *reviewer) orderThread(id int) ([]gengo.Comment, error) {
+	if id == 0 {
+		return nil, nil
+	}
+	if thread, ok := r.orderComments[id]; ok {
+		return thread, nil
+	}
+	resp, err := r.c.OrderComments(gengo.NewOrderCommentsRequest(id))
+	if err != nil {
+		return nil, fmt.Errorf("retrieving comments of order %d: %v", id, err)
+	}
+	r.orderComments[id] = resp.Thread
+	return resp.Thread, nil
+}
+
+func (r *reviewer) table() *table {
+	t := newTable("job_id", "action", "rating", "comment")
+	for _, res := range r.results {
+		rating := ""
+		if res.Rating > 0 {
+			rating = fmt.Sprint(res.Rating)
+		}
+		t.add(res.JobID, res.Action, rating, res.Comment)
+	}
+	return t
+}
+
+// runeWidth is the number of terminal cells a rune takes, two for wide East Asian characters.
+func runeWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+func textWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// pad fills s with spaces to n cells.
+func pad(s string, n int) string {
+	if w := textWidth(s); w < n {
+		return s + strings.Repeat(" ", n-w)
+	}
+	return s
+}
+
+// wrap splits text into lines of at most n cells, breaking at spaces where possible.
+// Text without spaces, such as Japanese, is broken between any characters.
+func wrap(text string, n int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		var line []rune
+		lineWidth, lastSpace := 0, -1
+		for _, r := range paragraph {
+			if r == '\t' {
+				r = ' '
+			}
+			w := runeWidth(r)
+			if lineWidth+w > n && len(line) > 0 {
+				if r == ' ' {
+					lines = append(lines, string(line))
+					line, lineWidth, lastSpace = nil, 0, -1
+					continue
+				}
+				if lastSpace >= 0 {
+					lines = append(lines, string(line[:lastSpace]))
+					line = append([]rune(nil), line[lastSpace+1:]...)
+				} else {
+					lines = append(lines, string(line))
+					line = nil
+				}
+				lineWidth, lastSpace = 0, -1
+				for i, lr := range line {
+					lineWidth += runeWidth(lr)
+					if lr == ' ' {
+						lastSpace = i
+					}
+				}
+			}
+			if r == ' ' {
+				lastSpace = len(line)
+			}
+			line = append(line, r)
+			lineWidth += w
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func reviewRoutes() map[string]string {
+	return map[string]string{
+		"GET /translate/jobs": `[{"job_id":"102","ctime":1580474097},{"job_id":"101","ctime":1580474096}]`,
+		"GET /translate/jobs/101,102": `{"jobs":[` +
+			`{"job_id":"101","order_id":"42","lc_src":"en","lc_tgt":"ja","tier":"standard","status":"reviewable","body_src":"Hello","body_tgt":"こんにちは"},` +
+			`{"job_id":"102","order_id":"42","lc_src":"en","lc_tgt":"fr","tier":"pro","status":"reviewable","body_src":"Goodbye","body_tgt":"Au revoir"}]}`,
+		"GET /translate/job/101/comments":  `{"thread":[{"body":"Keep it polite","author":"customer"}]}`,
+		"GET /translate/job/102/comments":  `{"thread":[]}`,
+		"GET /translate/order/42/comments": `{"thread":[{"body":"Marketing copy","author":"customer"}]}`,
+		"PUT /translate/jobs":              `null`,
+		"POST /translate/job/102/comment":  `null`,
+	}
+}
+
+func TestReview(t *testing.T) {
+	e := newTestEnv(reviewRoutes())
+	// Approve job 101 with a rating of 4, comment on job 102, then ask for its revision.
+	e.stdin = strings.NewReader("4cPlease check the tone\nrToo formal\n")
+	if code := run(e.env, []string{"-o", "csv", "review"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	want := "job_id,action,rating,comment\n" +
+		"101,approved,4,\n" +
+		"102,commented,,Please check the tone\n" +
+		"102,revising,,Too formal\n"
+	if e.stdout.String() != want {
+		t.Errorf("output = %q, want %q", e.stdout, want)
+	}
+	var actions []string
+	for i, r := range e.api.Requests() {
+		if r.Method != "GET" {
+			actions = append(actions, r.Method+" "+e.api.Form(i).Get("data"))
+		}
+	}
+	wantActions := []string{
+		`PUT {"action":"approve","job_ids":[{"job_id":101,"rating":4}]}`,
+		`POST {"id":102,"body":"Please check the tone"}`,
+		`PUT {"action":"revise","job_ids":[{"job_id":102,"comment":"Too formal"}]}`,
+	}
+	if strings.Join(actions, "\n") != strings.Join(wantActions, "\n") {
+		t.Errorf("actions =\n%s\nwant\n%s", strings.Join(actions, "\n"), strings.Join(wantActions, "\n"))
+	}
+	for _, s := range []string{"Hello", "こんにちは", "Keep it polite", "Marketing copy", "Job 102 (2/2)"} {
+		if !strings.Contains(e.stderr.String(), s) {
+			t.Errorf("review screen is missing %q", s)
+		}
+	}
+}
+
+func TestReviewNavigation(t *testing.T) {
+	e := newTestEnv(reviewRoutes())
+	// Move to job 102 and back, try an unknown key, cancel a revision and quit.
+	e.stdin = strings.NewReader("n\x1b[Dxr\x1bq")
+	if code := run(e.env, []string{"review", "-count", "2"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	screens := strings.Split(e.stderr.String(), "Job 10")
+	var shown []string
+	for _, s := range screens[1:] {
+		shown = append(shown, "10"+s[:1])
+	}
+	if got := strings.Join(shown, ","); got != "101,102,101,101,101" {
+		t.Errorf("jobs shown = %s", got)
+	}
+	if !strings.Contains(e.stderr.String(), "Revision canceled.") {
+		t.Error("expected the revision to be canceled")
+	}
+	for _, r := range e.api.Requests() {
+		if r.Method != "GET" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}
+}
+
+// keyPresses returns one key press per read, as a terminal does.
+type keyPresses struct {
+	keys  []string
+	reads int
+}
+
+func (k *keyPresses) Read(p []byte) (int, error) {
+	k.reads++
+	if len(k.keys) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, k.keys[0])
+	k.keys = k.keys[1:]
+	return n, nil
+}
+
+func TestReviewKeys(t *testing.T) {
+	in := &keyPresses{keys: []string{"\x1b[C", "\x1b", "q"}}
+	r := &reviewer{in: bufio.NewReader(in)}
+	if k, err := r.key(); k != keyRight || err != nil {
+		t.Errorf("key() of an arrow = %q, %v", k, err)
+	}
+	// A lone escape must not wait for the next key press.
+	if k, err := r.key(); k != keyEscape || err != nil || in.reads != 2 {
+		t.Errorf("key() of escape = %q, %v after %d reads", k, err, in.reads)
+	}
+	if k, err := r.key(); k != 'q' || err != nil {
+		t.Errorf("key() after escape = %q, %v", k, err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"日本語の文章", 5, []string{"日本", "語の", "文章"}},
+		{"one\ntwo", 10, []string{"one", "two"}},
+	}
+	for _, tt := range tests {
+		if got := wrap(tt.text, tt.width); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrap(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+	if w := textWidth(pad("日本", 6)); w != 6 {
+		t.Errorf("padded width = %d", w)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+)
+
+var languagesCommand = &command{
+	name:    "languages",
+	summary: "list supported languages",
+	setup:   noFlags(languages),
+}
+
+func languages(e *env, _ []string) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	r, err := c.Languages()
+	if err != nil {
+		return err
+	}
+	t := newTable("code", "name", "localized_name", "unit_type")
+	for _, l := range r.Languages {
+		t.add(l.Code, l.Name, l.LocalizedName, l.UnitType)
+	}
+	return e.print(r.Languages, t)
+}
+
+var pairsCommand = &command{
+	name:    "pairs",
+	summary: "list supported language pairs and their prices",
+	setup: func(fs *flag.FlagSet) runFunc {
+		source := fs.String("source", "", "only list pairs from the source language `code`")
+		return func(e *env, args []string) error {
+			var options []gengo.LanguagePairsRequestOption
+			if *source != "" {
+				code, err := lang.Parse(*source)
+				if err != nil {
+					return fmt.Errorf("%w: %v", errUsage, err)
+				}
+				options = append(options, gengo.WithSource(code))
+			}
+			c, err := e.client()
+			if err != nil {
+				return err
+			}
+			r, err := c.LanguagePairs(gengo.NewLanguagePairsRequest(options...))
+			if err != nil {
+				return err
+			}
+			t := newTable("source", "target", "tier", "unit_price", "currency")
+			for _, lp := range r.LanguagePairs {
+				t.add(lp.Source, lp.Target, lp.Tier, lp.UnitPrice, lp.Currency)
+			}
+			return e.print(r.LanguagePairs, t)
+		}
+	},
+}
+
+var quoteCommand = &command{
+	name:    "quote",
+	args:    "[text | -]",
+	summary: "quote the cost of translating text",
+	setup: func(fs *flag.FlagSet) runFunc {
+		jf := newJobFlags(fs)
+		return func(e *env, args []string) error {
+			records, _, err := jf.records(e, args)
+			if err != nil {
+				return err
+			}
+			jobs, err := jobs(records)
+			if err != nil {
+				return err
+			}
+			c, err := e.client()
+			if err != nil {
+				return err
+			}
+			r, err := c.QuoteText(gengo.NewQuoteTextRequest(jobs...))
+			if err != nil {
+				return err
+			}
+			return e.print(r, quoteTable(records, r))
+		}
+	},
+}
+
+// quoteTable lists the quote of each record, followed by their total.
+func quoteTable(records []jobRecord, r *gengo.QuoteTextResponse) *table {
+	t := newTable("row", "source", "target", "tier", "units", "credits", "currency", "eta")
+	for i, q := range r.Jobs {
+		if i < len(records) {
+			rec := records[i]
+			t.add(rec.Row, rec.Source, rec.Target, rec.Tier, q.UnitCount, q.Credits.Amount(), q.Currency, q.ETA)
+		}
+	}
+	if total, err := r.Total(); err == nil && len(r.Jobs) > 1 {
+		t.add("total", "", "", "", "", total.Amount(), total.Currency, "")
+	}
+	return t
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/currency"
+	"github.com/trinchan/gengo/lang"
+)
+
+// manifestPollInterval is how often submit looks up the IDs of queued jobs while waiting for them.
+const manifestPollInterval = 5 * time.Second
+
+var submitCommand = &command{
+	name:    "submit",
+	args:    "[text | -]",
+	summary: "quote and order translations of text or of the jobs in a file",
+	setup: func(fs *flag.FlagSet) runFunc {
+		jf := newJobFlags(fs)
+		autoApprove := fs.Bool("auto-approve", false, "approve the jobs automatically once translated")
+		yes := fs.Bool("yes", false, "submit without asking for confirmation")
+		manifestPath := fs.String("manifest", "", "write the manifest to `file` (default next to -file, or order-ID.manifest.json)")
+		wait := fs.Duration("wait", 0, "wait up to `duration` for Gengo to assign job IDs to the manifest")
+		return func(e *env, args []string) error {
+			records, stdinUsed, err := jf.records(e, args)
+			if err != nil {
+				return err
+			}
+			if stdinUsed && !*yes {
+				return fmt.Errorf("%w: -yes is required when the text is read from standard input", errUsage)
+			}
+			var options []gengo.JobOption
+			if *autoApprove {
+				options = append(options, gengo.WithAutoApprove(true))
+			}
+			jobs, err := jobs(records, options...)
+			if err != nil {
+				return err
+			}
+			c, err := e.client()
+			if err != nil {
+				return err
+			}
+			quote, err := c.QuoteText(gengo.NewQuoteTextRequest(jobs...))
+			if err != nil {
+				return fmt.Errorf("quoting jobs: %v", err)
+			}
+			if err := writeTable(e.stderr, quoteTable(records, quote)); err != nil {
+				return err
+			}
+			if !*yes {
+				total, err := quote.Total()
+				if err != nil {
+					return err
+				}
+				ok, err := e.confirm(fmt.Sprintf("Submit %d jobs for %s?", len(jobs), total))
+				if err != nil || !ok {
+					return err
+				}
+			}
+			r, err := c.PostJobs(gengo.NewPostJobsRequest(jobs))
+			if err != nil {
+				return err
+			}
+			m := newManifest(jf.file, records, r, e.now())
+			m.resolve(e, c, *wait)
+			path := *manifestPath
+			if path == "" {
+				path = defaultManifestPath(jf.file, r.OrderID)
+			}
+			if err := m.write(path); err != nil {
+				return fmt.Errorf("order %d was submitted but its manifest could not be written: %v", r.OrderID, err)
+			}
+			fmt.Fprintf(e.stderr, "Submitted order %d, manifest written to %s\n", r.OrderID, path)
+			return e.print(m, m.table())
+		}
+	},
+}
+
+// confirm asks a yes or no question, defaulting to no.
+func (e *env) confirm(question string) (bool, error) {
+	fmt.Fprintf(e.stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(e.stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("no answer to confirmation, use -yes to submit without one")
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	fmt.Fprintln(e.stderr, "Not submitted.")
+	return false, nil
+}
+
+// manifest records which jobs and order the rows of a submission became, for tracking them later.
+type manifest struct {
+	OrderID     int            `json:"order_id"`
+	Submitted   time.Time      `json:"submitted"`
+	Input       string         `json:"input,omitempty"`
+	CreditsUsed currency.Money `json:"credits_used"`
+	Currency    currency.Code  `json:"currency"`
+	Jobs        []manifestJob  `json:"jobs"`
+}
+
+// manifestJob maps an input row to its job. JobID is zero until Gengo has assigned one.
+type manifestJob struct {
+	Row        int        `json:"row"`
+	JobID      int        `json:"job_id,omitempty"`
+	Source     lang.Code  `json:"source"`
+	Target     lang.Code  `json:"target"`
+	Tier       gengo.Tier `json:"tier"`
+	Slug       string     `json:"slug,omitempty"`
+	CustomData string     `json:"custom_data,omitempty"`
+	text       string
+}
+
+func newManifest(input string, records []jobRecord, r *gengo.PostJobsResponse, now time.Time) *manifest {
+	m := &manifest{
+		OrderID:     r.OrderID,
+		Submitted:   now.UTC().Truncate(time.Second),
+		Input:       input,
+		CreditsUsed: r.CreditsUsed,
+		Currency:    r.Currency,
+	}
+	for _, rec := range records {
+		source, _ := lang.Parse(rec.Source)
+		target, _ := lang.Parse(rec.Target)
+		m.Jobs = append(m.Jobs, manifestJob{
+			Row:        rec.Row,
+			Source:     source,
+			Target:     target,
+			Tier:       gengo.Tier(rec.Tier),
+			Slug:       rec.Slug,
+			CustomData: rec.CustomData,
+			text:       rec.Text,
+		})
+	}
+	m.match(r.Jobs)
+	return m
+}
+
+// match assigns job IDs to the rows with the same language pair and text, in order.
+func (m *manifest) match(jobs []gengo.PostJobResponse) {
+	for _, j := range jobs {
+		for i := range m.Jobs {
+			mj := &m.Jobs[i]
+			if mj.JobID == 0 && mj.Source == j.Source && mj.Target == j.Target && mj.text == j.BodySrc {
+				mj.JobID = int(j.ID)
+				break
+			}
+		}
+	}
+}
+
+func (m *manifest) unresolved() int {
+	n := 0
+	for _, j := range m.Jobs {
+		if j.JobID == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// resolve looks up the order's jobs until every row has a job ID or wait has passed.
+// Gengo queues the jobs of new orders, so their IDs may not be known yet; failures are only reported.
+func (m *manifest) resolve(e *env, c *gengo.Client, wait time.Duration) {
+	deadline := e.now().Add(wait)
+	for m.unresolved() > 0 {
+		r, err := c.OrderJobs(gengo.NewOrderJobsRequest(m.OrderID))
+		if err != nil {
+			fmt.Fprintf(e.stderr, "Looking up the jobs of order %d: %v\n", m.OrderID, err)
+			return
+		}
+		jobs := make([]gengo.PostJobResponse, len(r.Jobs))
+		for i, j := range r.Jobs {
+			jobs[i] = gengo.PostJobResponse(j)
+		}
+		m.match(jobs)
+		if m.unresolved() == 0 || !e.now().Add(manifestPollInterval).Before(deadline) {
+			break
+		}
+		e.sleep(manifestPollInterval)
+	}
+	if n := m.unresolved(); n > 0 {
+		fmt.Fprintf(e.stderr, "%d jobs are still queued; their IDs can be found later with: gengo order get -jobs %d\n", n, m.OrderID)
+	}
+}
+
+func (m *manifest) write(path string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
+
+func (m *manifest) table() *table {
+	t := newTable("row", "order_id", "job_id", "source", "target", "tier", "slug")
+	for _, j := range m.Jobs {
+		jobID := ""
+		if j.JobID != 0 {
+			jobID = fmt.Sprint(j.JobID)
+		}
+		t.add(j.Row, m.OrderID, jobID, j.Source, j.Target, j.Tier, j.Slug)
+	}
+	return t
+}
+
+// defaultManifestPath names the manifest after the input file, such as jobs.manifest.json for jobs.csv.
+func defaultManifestPath(input string, orderID int) string {
+	if input == "" {
+		return fmt.Sprintf("order-%d.manifest.json", orderID)
+	}
+	return strings.TrimSuffix(input, filepath.Ext(input)) + ".manifest.json"
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadRecords(t *testing.T) {
+	want := []jobRecord{
+		{Text: "Hello", Source: "en", Target: "ja", Tier: "standard", Slug: "greeting", CustomData: "home.hello"},
+		{Text: "Goodbye", Source: "en", Target: "fr", Tier: "pro", Comment: "Informal", GlossaryID: 7, CallbackURL: "https://example.com/cb"},
+	}
+	rows := map[string][]int{
+		"testdata/jobs.jsonl": {1, 3},
+		"testdata/jobs.csv":   {2, 3},
+		"testdata/jobs.yaml":  {1, 2},
+	}
+	for path, wantRows := range rows {
+		format, err := inputFormat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := readRecords(f, format)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if len(records) != len(want) {
+			t.Fatalf("%s: read %d records", path, len(records))
+		}
+		for i, rec := range records {
+			w := want[i]
+			w.Row = wantRows[i]
+			if rec != w {
+				t.Errorf("%s record %d = %+v, want %+v", path, i, rec, w)
+			}
+		}
+	}
+	if _, err := readRecords(strings.NewReader("text,color\nHi,red\n"), inputCSV); err == nil {
+		t.Error("expected an error reading an unknown CSV column")
+	}
+	if _, err := readRecords(strings.NewReader(`{"txt":"Hi"}`), inputJSONL); err == nil {
+		t.Error("expected an error reading an unknown JSON field")
+	}
+}
+
+func submitRoutes() map[string]string {
+	return map[string]string{
+		"POST /translate/service/quote": `{"jobs":[{"credits":"0.06","unit_count":1,"currency":"USD","eta":3600},{"credits":"0.12","unit_count":1,"currency":"USD","eta":7200}]}`,
+		"POST /translate/jobs":          `{"order_id":42,"job_count":2,"credits_used":"0.18","currency":"USD"}`,
+		"GET /translate/order/42":       `{"order":{"order_id":"42","jobs_available":["102","101"],"total_jobs":"2","currency":"USD"}}`,
+		"GET /translate/jobs/102,101": `{"jobs":[` +
+			`{"job_id":"102","lc_src":"en","lc_tgt":"fr","body_src":"Goodbye"},` +
+			`{"job_id":"101","lc_src":"en","lc_tgt":"ja","body_src":"Hello"}]}`,
+	}
+}
+
+func TestSubmitFromFile(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "jobs.manifest.json")
+	e := newTestEnv(submitRoutes())
+	e.stdin = strings.NewReader("n\n")
+	if code := run(e.env, []string{"submit", "-file", "testdata/jobs.csv", "-manifest", manifestPath}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stderr.String(), "Submit 2 jobs for 0.18 USD? [y/N]") || !strings.Contains(e.stderr.String(), "Not submitted") {
+		t.Errorf("unexpected confirmation %q", e.stderr)
+	}
+	for _, r := range e.api.Requests() {
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/translate/jobs") {
+			t.Fatal("jobs were submitted without confirmation")
+		}
+	}
+
+	e = newTestEnv(submitRoutes())
+	e.stdin = strings.NewReader("y\n")
+	if code := run(e.env, []string{"-o", "csv", "submit", "-file", "testdata/jobs.csv", "-manifest", manifestPath}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	want := "row,order_id,job_id,source,target,tier,slug\n2,42,101,en,ja,standard,greeting\n3,42,102,en,fr,pro,\n"
+	if e.stdout.String() != want {
+		t.Errorf("output = %q, want %q", e.stdout, want)
+	}
+	b, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.OrderID != 42 || m.Input != "testdata/jobs.csv" || !m.Submitted.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) || m.CreditsUsed.Amount() != "0.18" || len(m.Jobs) != 2 || m.Jobs[1].JobID != 102 || m.Jobs[0].CustomData != "home.hello" {
+		t.Errorf("unexpected manifest %s", b)
+	}
+}
+
+func TestSubmitFileWithTargets(t *testing.T) {
+	e := newTestEnv(submitRoutes())
+	if code := run(e.env, []string{"submit", "-yes", "-file", "testdata/jobs.csv", "-to", "ja,fr"}); code != 2 {
+		t.Errorf("exit %d, want 2: %s", code, e.stderr)
+	}
+	if e.api.Len() != 0 {
+		t.Error("jobs were submitted with a list of targets for a file")
+	}
+}
+
+func TestSubmitUnresolvedJobs(t *testing.T) {
+	routes := submitRoutes()
+	routes["GET /translate/order/42"] = `{"order":{"order_id":"42","jobs_queued":"2","total_jobs":"2"}}`
+	e := newTestEnv(routes)
+	manifestPath := filepath.Join(t.TempDir(), "m.json")
+	if code := run(e.env, []string{"submit", "-yes", "-from", "en", "-to", "ja,fr", "-manifest", manifestPath, "Hello"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stderr.String(), "2 jobs are still queued") {
+		t.Errorf("expected a note about queued jobs, got %q", e.stderr)
+	}
+	e = newTestEnv(routes)
+	if code := run(e.env, []string{"submit", "-yes", "-from", "en", "-to", "ja", "-wait", "1m", "-manifest", manifestPath, "Hello"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	// The order is looked up every 5s of the fake clock until the next lookup would be past the minute.
+	if n := e.api.Len(); n != 2+12 {
+		t.Errorf("%d requests, want the jobs quoted and posted and 12 order lookups", n)
+	}
+	if code := run(newTestEnv(routes).env, []string{"submit", "-from", "en", "-to", "ja"}); code != 2 {
+		t.Errorf("submitting from standard input without -yes = %d, want 2", code)
+	}
+}
+
+func TestDefaultManifestPath(t *testing.T) {
+	if p := defaultManifestPath("batch/jobs.csv", 42); p != "batch/jobs.manifest.json" {
+		t.Errorf("defaultManifestPath() = %s", p)
+	}
+	if p := defaultManifestPath("", 42); p != "order-42.manifest.json" {
+		t.Errorf("defaultManifestPath() = %s", p)
+	}
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trinchan/gengo"
+	"golang.org/x/term"
+)
+
+// Exit codes of watch, besides 0 once every job reaches the target status.
+const (
+	exitTimeout  = 3
+	exitCanceled = 4
+)
+
+// maxWatchBackoff limits how many times the interval watch waits after failed polls.
+const maxWatchBackoff = 16
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiClear  = "\x1b[H\x1b[2J"
+)
+
+// progressBarWidth is the number of cells in each language pair's progress bar.
+const progressBarWidth = 30
+
+// statusRank orders job statuses by how far along they are, so that a target status is reached by any later one.
+var statusRank = map[string]int{
+	gengo.JobStatusQueued:     0,
+	gengo.JobStatusHeld:       0,
+	gengo.JobStatusAvailable:  1,
+	gengo.JobStatusPending:    2,
+	gengo.JobStatusRevising:   2,
+	gengo.JobStatusRejected:   2,
+	gengo.JobStatusReviewable: 3,
+	gengo.JobStatusApproved:   4,
+}
+
+var watchCommand = &command{
+	name: "watch",
+	summary: fmt.Sprintf("follow the jobs of an order until they reach a status; exits %d on timeout and %d if a job is canceled",
+		exitTimeout, exitCanceled),
+	setup: func(fs *flag.FlagSet) runFunc {
+		orderID := fs.Int("order", 0, "watch the jobs of the order `id`")
+		jobIDs := fs.String("jobs", "", "watch the comma separated job `ids`")
+		manifestPath := fs.String("manifest", "", "watch the order of a submit manifest `file`")
+		until := fs.String("until", gengo.JobStatusApproved, "exit once every job reaches the `status`: available, pending, reviewable or approved")
+		interval := fs.Duration("interval", 30*time.Second, "`duration` between polls")
+		timeout := fs.Duration("timeout", 0, "give up after `duration` (default never)")
+		return func(e *env, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+			}
+			switch *until {
+			case gengo.JobStatusAvailable, gengo.JobStatusPending, gengo.JobStatusReviewable, gengo.JobStatusApproved:
+			default:
+				return fmt.Errorf("%w: -until must be available, pending, reviewable or approved, not %q", errUsage, *until)
+			}
+			if *interval <= 0 {
+				return fmt.Errorf("%w: -interval must be positive", errUsage)
+			}
+			w := &watcher{target: *until, seen: map[int]string{}}
+			var err error
+			switch {
+			case *manifestPath != "" && *orderID == 0 && *jobIDs == "":
+				w.orderID, err = manifestOrder(*manifestPath)
+				if err != nil {
+					return err
+				}
+			case *orderID != 0 && *jobIDs == "" && *manifestPath == "":
+				w.orderID = *orderID
+			case *jobIDs != "" && *orderID == 0 && *manifestPath == "":
+				if w.jobIDs, err = parseIDs(strings.Split(*jobIDs, ",")); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("%w: one of -order, -jobs or -manifest is required", errUsage)
+			}
+			return w.run(e, *interval, *timeout)
+		}
+	},
+}
+
+// manifestOrder reads the order ID of a submit manifest.
+func manifestOrder(path string) (int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return 0, fmt.Errorf("reading manifest %s: %v", path, err)
+	}
+	if m.OrderID == 0 {
+		return 0, fmt.Errorf("manifest %s has no order", path)
+	}
+	return m.OrderID, nil
+}
+
+// watcher polls the jobs of an order, or a set of jobs, and renders their progress.
+type watcher struct {
+	orderID int
+	jobIDs  []int
+	target  string
+
+	jobs   []gengo.GetJobResponse
+	queued int
+	// seen is the status of each job at the previous poll.
+	seen map[int]string
+	// fresh marks the jobs which became reviewable at the last poll.
+	fresh  map[int]bool
+	polled time.Time
+}
+
+// run polls until every job reaches the target status. Failed polls are reported and retried at growing
+// intervals, keeping the last state, except for the first, which is usually a wrong ID or key.
+func (w *watcher) run(e *env, interval, timeout time.Duration) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	live := e.format == formatTable && isTerminal(e.stdout)
+	color := live && e.getenv("NO_COLOR") == ""
+	start := e.now()
+	errs := 0
+	for {
+		if err := w.poll(c, e.now()); err != nil {
+			if w.polled.IsZero() {
+				return err
+			}
+			errs++
+			fmt.Fprintf(e.stderr, "Polling failed, retrying: %v\n", err)
+		} else {
+			errs = 0
+			done, canceled := w.done()
+			if live {
+				fmt.Fprint(e.stdout, ansiClear)
+				w.render(e.stdout, color)
+			} else if e.format == formatTable {
+				w.renderSummary(e.stdout)
+			}
+			switch {
+			case canceled > 0:
+				return w.finish(e, &exitError{code: exitCanceled, err: fmt.Errorf("%d jobs were canceled", canceled)})
+			case done:
+				return w.finish(e, nil)
+			}
+		}
+		wait := interval * time.Duration(watchBackoff(errs))
+		if timeout > 0 && e.now().Add(wait).Sub(start) > timeout {
+			return w.finish(e, &exitError{code: exitTimeout, err: fmt.Errorf("timed out before every job was %s", w.target)})
+		}
+		e.sleep(wait)
+	}
+}
+
+// watchBackoff returns how many times the interval to wait after a number of failed polls in a row.
+func watchBackoff(errs int) int {
+	n := 1
+	for ; errs > 0 && n < maxWatchBackoff; errs-- {
+		n *= 2
+	}
+	return n
+}
+
+// finish prints the final state in the JSON and CSV formats, which are not rendered while watching.
+func (w *watcher) finish(e *env, err error) error {
+	if e.format != formatTable {
+		if perr := e.print(w.jobs, jobsTable(w.jobs)); perr != nil {
+			return perr
+		}
+	}
+	return err
+}
+
+func (w *watcher) poll(c *gengo.Client, now time.Time) error {
+	ids := w.jobIDs
+	if w.orderID != 0 {
+		r, err := c.GetOrder(gengo.NewOrderGetRequest(w.orderID))
+		if err != nil {
+			return err
+		}
+		ids = r.Order.AllJobIDs()
+		sort.Ints(ids)
+		w.queued = int(r.Order.JobsQueued)
+	}
+	jobs, err := getJobs(c, ids)
+	if err != nil {
+		return err
+	}
+	// Jobs already reviewable at the first poll are not news.
+	first := len(w.seen) == 0
+	w.fresh = map[int]bool{}
+	for _, j := range jobs {
+		id := int(j.ID)
+		if !first && j.Status == gengo.JobStatusReviewable && w.seen[id] != gengo.JobStatusReviewable {
+			w.fresh[id] = true
+		}
+		w.seen[id] = j.Status
+	}
+	w.jobs, w.polled = jobs, now
+	return nil
+}
+
+// reached reports whether the job's status is at or past the target status.
+func (w *watcher) reached(status string) bool {
+	rank, ok := statusRank[status]
+	return ok && rank >= statusRank[w.target]
+}
+
+// done reports whether every job has reached the target status, and how many jobs were canceled.
+func (w *watcher) done() (bool, int) {
+	canceled := 0
+	done := w.queued == 0 && len(w.jobs) > 0
+	for _, j := range w.jobs {
+		if j.Status == gengo.JobStatusCanceled {
+			canceled++
+		}
+		if !w.reached(j.Status) {
+			done = false
+		}
+	}
+	return done, canceled
+}
+
+// pairProgress is the progress of the jobs of one language pair.
+type pairProgress struct {
+	pair     string
+	total    int
+	reached  int
+	statuses map[string]int
+}
+
+func (w *watcher) pairs() []*pairProgress {
+	byPair := map[string]*pairProgress{}
+	var pairs []*pairProgress
+	for _, j := range w.jobs {
+		key := fmt.Sprintf("%s→%s", j.Source, j.Target)
+		p, ok := byPair[key]
+		if !ok {
+			p = &pairProgress{pair: key, statuses: map[string]int{}}
+			byPair[key] = p
+			pairs = append(pairs, p)
+		}
+		p.total++
+		p.statuses[j.Status]++
+		if w.reached(j.Status) {
+			p.reached++
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].pair < pairs[j].pair })
+	return pairs
+}
+
+func (p *pairProgress) bar() string {
+	filled := progressBarWidth * p.reached / p.total
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled) + "]"
+}
+
+func (p *pairProgress) counts() string {
+	statuses := make([]string, 0, len(p.statuses))
+	for s := range p.statuses {
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		ri, rj := statusRank[statuses[i]], statusRank[statuses[j]]
+		return ri < rj || ri == rj && statuses[i] < statuses[j]
+	})
+	parts := make([]string, len(statuses))
+	for i, s := range statuses {
+		parts[i] = fmt.Sprintf("%s %d", s, p.statuses[s])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// title describes what is being watched and when it was last polled.
+func (w *watcher) title() string {
+	what := fmt.Sprintf("%d jobs", len(w.jobIDs))
+	if w.orderID != 0 {
+		what = fmt.Sprintf("order %d", w.orderID)
+	}
+	return fmt.Sprintf("Watching %s until %s, updated %s", what, w.target, w.polled.Format("15:04:05"))
+}
+
+// render draws the full view: progress bars per language pair, then every job with reviewable jobs highlighted.
+func (w *watcher) render(out io.Writer, color bool) {
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return code + s + ansiReset
+	}
+	fmt.Fprintln(out, paint(ansiBold, w.title()))
+	fmt.Fprintln(out)
+	for _, p := range w.pairs() {
+		bar := p.bar()
+		if p.reached == p.total {
+			bar = paint(ansiGreen, bar)
+		}
+		fmt.Fprintf(out, "%-12s %s %d/%d  %s\n", p.pair, bar, p.reached, p.total, p.counts())
+	}
+	if w.queued > 0 {
+		fmt.Fprintf(out, "%-12s %d jobs queued\n", "", w.queued)
+	}
+	fmt.Fprintln(out)
+	t := newTable("job_id", "pair", "status", "units", "")
+	for _, j := range w.jobs {
+		t.add(j.ID, fmt.Sprintf("%s→%s", j.Source, j.Target), j.Status, j.UnitCount, "")
+		if w.fresh[int(j.ID)] {
+			t.rows[len(t.rows)-1][4] = "new"
+		}
+	}
+	var sb strings.Builder
+	writeTable(&sb, t)
+	lines := strings.Split(strings.TrimRight(sb.String(), "\n"), "\n")
+	for i, line := range lines {
+		if i > 0 {
+			switch w.jobs[i-1].Status {
+			case gengo.JobStatusReviewable:
+				line = paint(ansiBold+ansiYellow, line)
+			case gengo.JobStatusCanceled:
+				line = paint(ansiRed, line)
+			}
+		}
+		fmt.Fprintln(out, line)
+	}
+}
+
+// renderSummary writes one line per poll, for output which is not a terminal.
+func (w *watcher) renderSummary(out io.Writer) {
+	parts := []string{w.polled.Format(time.RFC3339)}
+	for _, p := range w.pairs() {
+		parts = append(parts, fmt.Sprintf("%s %d/%d", p.pair, p.reached, p.total))
+	}
+	if w.queued > 0 {
+		parts = append(parts, fmt.Sprintf("queued %d", w.queued))
+	}
+	for _, j := range w.jobs {
+		if w.fresh[int(j.ID)] {
+			parts = append(parts, fmt.Sprintf("job %d reviewable", j.ID))
+		}
+	}
+	fmt.Fprintln(out, strings.Join(parts, "  "))
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// exitError is an error which ends the command with a specific exit code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo/internal/gengotest"
+)
+
+// watchAPI answers with each of responses in turn for the jobs of order 42, then keeps answering with the last. An
+// empty response fails.
+type watchAPI struct {
+	*gengotest.API
+	responses []string
+}
+
+func (w *watchAPI) next() {
+	if len(w.responses) == 0 {
+		return
+	}
+	if w.responses[0] == "" {
+		delete(w.Routes, "GET /translate/jobs/101,102")
+	} else {
+		w.Handle("GET /translate/jobs/101,102", w.responses[0])
+	}
+	if len(w.responses) > 1 {
+		w.responses = w.responses[1:]
+	}
+}
+
+func watchJobs(status101, status102 string) string {
+	return `{"jobs":[` +
+		`{"job_id":"101","lc_src":"en","lc_tgt":"ja","status":"` + status101 + `"},` +
+		`{"job_id":"102","lc_src":"en","lc_tgt":"fr","status":"` + status102 + `"}]}`
+}
+
+func newWatchEnv(responses ...string) *testEnv {
+	e := newTestEnv(map[string]string{
+		"GET /translate/order/42": `{"order":{"order_id":"42","jobs_available":["101"],"jobs_pending":["102"],"total_jobs":"2"}}`,
+	})
+	w := &watchAPI{API: e.api, responses: responses}
+	w.next()
+	sleep := e.sleep
+	e.sleep = func(d time.Duration) {
+		sleep(d)
+		w.next()
+	}
+	return e
+}
+
+func TestWatchUntilReviewable(t *testing.T) {
+	e := newWatchEnv(
+		watchJobs("available", "pending"),
+		watchJobs("reviewable", "pending"),
+		watchJobs("approved", "reviewable"),
+	)
+	if code := run(e.env, []string{"watch", "-order", "42", "-until", "reviewable"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(e.stdout.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a line per poll, got %q", e.stdout)
+	}
+	if !strings.Contains(lines[0], "en→fr 0/1  en→ja 0/1") {
+		t.Errorf("unexpected first poll %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "en→ja 1/1") || !strings.Contains(lines[1], "job 101 reviewable") {
+		t.Errorf("expected job 101 to become reviewable, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "job 102 reviewable") || strings.Contains(lines[2], "job 101") {
+		t.Errorf("expected only job 102 to become reviewable, got %q", lines[2])
+	}
+}
+
+func TestWatchExitCodes(t *testing.T) {
+	e := newWatchEnv(watchJobs("available", "pending"))
+	if code := run(e.env, []string{"watch", "-jobs", "101,102", "-interval", "1m", "-timeout", "5m"}); code != exitTimeout {
+		t.Errorf("timed out watch = %d, want %d: %s", code, exitTimeout, e.stderr)
+	}
+	if n := e.api.Len(); n != 6 {
+		t.Errorf("polled %d times in 5 minutes, want 6", n)
+	}
+
+	e = newWatchEnv(watchJobs("available", "pending"), watchJobs("canceled", "pending"))
+	if code := run(e.env, []string{"-o", "csv", "watch", "-order", "42"}); code != exitCanceled {
+		t.Errorf("watch with a canceled job = %d, want %d: %s", code, exitCanceled, e.stderr)
+	}
+	if !strings.HasPrefix(e.stdout.String(), "job_id,") || !strings.Contains(e.stdout.String(), "canceled") {
+		t.Errorf("expected the final jobs as CSV, got %q", e.stdout)
+	}
+
+	if code := run(newWatchEnv().env, []string{"watch", "-order", "42", "-jobs", "1"}); code != 2 {
+		t.Errorf("watch with both -order and -jobs = %d, want 2", code)
+	}
+	for _, until := range []string{"done", "held", "rejected", "revising", "queued"} {
+		if code := run(newWatchEnv().env, []string{"watch", "-order", "42", "-until", until}); code != 2 {
+			t.Errorf("watch -until %s = %d, want 2", until, code)
+		}
+	}
+}
+
+func TestWatchRetries(t *testing.T) {
+	e := newWatchEnv(watchJobs("available", "pending"), "", watchJobs("approved", "approved"))
+	start := e.now()
+	if code := run(e.env, []string{"watch", "-order", "42", "-interval", "1m"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+	if !strings.Contains(e.stderr.String(), "Polling failed, retrying") {
+		t.Errorf("expected the failed poll to be reported, got %q", e.stderr)
+	}
+	if lines := strings.Split(strings.TrimSpace(e.stdout.String()), "\n"); len(lines) != 2 {
+		t.Errorf("expected a line per successful poll, got %q", e.stdout)
+	}
+	// The failed poll is retried after twice the interval.
+	if waited := e.now().Sub(start); waited != 3*time.Minute {
+		t.Errorf("waited %s, want 3m", waited)
+	}
+
+	e = newWatchEnv("")
+	if code := run(e.env, []string{"watch", "-order", "42"}); code != 1 {
+		t.Errorf("watch failing its first poll = %d, want 1", code)
+	}
+}
+
+func TestWatchManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.manifest.json")
+	if err := os.WriteFile(path, []byte(`{"order_id":42,"jobs":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e := newWatchEnv(watchJobs("approved", "approved"))
+	if code := run(e.env, []string{"watch", "-manifest", path}); code != 0 {
+		t.Fatalf("exit %d: %s", code, e.stderr)
+	}
+}
+
+func TestWatchRender(t *testing.T) {
+	w := &watcher{orderID: 42, target: "approved", seen: map[int]string{}}
+	e := newWatchEnv(watchJobs("approved", "reviewable"))
+	e.api.Handle("GET /translate/order/42", `{"order":{"order_id":"42","jobs_queued":"1","jobs_approved":["101"],"jobs_reviewable":["102"],"total_jobs":"3"}}`)
+	c, err := e.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.poll(c, e.now()); err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	w.render(&sb, false)
+	for _, want := range []string{
+		"Watching order 42 until approved",
+		"en→ja        [" + strings.Repeat("#", progressBarWidth) + "] 1/1  approved 1",
+		"en→fr        [" + strings.Repeat("-", progressBarWidth) + "] 0/1  reviewable 1",
+		"1 jobs queued",
+	} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("render is missing %q:\n%s", want, sb.String())
+		}
+	}
+}
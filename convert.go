@@ -0,0 +1,89 @@
+package gengo
+
+import (
+	"fmt"
+
+	"github.com/trinchan/gengo/currency"
+)
+
+// Converter normalizes credits from accounts billed in different currencies into one reporting currency.
+type Converter struct {
+	To    currency.Code
+	Rates currency.RateProvider
+}
+
+// NewConverter creates a new Converter reporting in the currency to, using exchange rates from rates.
+func NewConverter(to currency.Code, rates currency.RateProvider) *Converter {
+	return &Converter{To: to, Rates: rates}
+}
+
+// ConvertedAmount is an amount converted into the reporting currency, with the rate used.
+// Rate.Date records which day's rate the conversion is based on.
+type ConvertedAmount struct {
+	Original  currency.Money `json:"original"`
+	Converted currency.Money `json:"converted"`
+	Rate      currency.Rate  `json:"rate"`
+}
+
+// Consolidated is the total of amounts converted into the reporting currency.
+type Consolidated struct {
+	Amounts []ConvertedAmount `json:"amounts"`
+	Total   currency.Money    `json:"total"`
+}
+
+// Convert converts m into the reporting currency.
+func (cv *Converter) Convert(m currency.Money) (ConvertedAmount, error) {
+	c, r, err := currency.Convert(m, cv.To, cv.Rates)
+	return ConvertedAmount{Original: m, Converted: c, Rate: r}, err
+}
+
+// Consolidate converts every amount into the reporting currency and totals them.
+func (cv *Converter) Consolidate(ms ...currency.Money) (*Consolidated, error) {
+	con := &Consolidated{Total: currency.New(0, cv.To)}
+	for _, m := range ms {
+		ca, err := cv.Convert(m)
+		if err != nil {
+			return nil, err
+		}
+		con.Amounts = append(con.Amounts, ca)
+		if con.Total, err = con.Total.Add(ca.Converted); err != nil {
+			return nil, err
+		}
+	}
+	return con, nil
+}
+
+// Balances consolidates the balance of every client's account.
+func (cv *Converter) Balances(clients ...*Client) (*Consolidated, error) {
+	ms := make([]currency.Money, len(clients))
+	for i, c := range clients {
+		br, err := c.Balance()
+		if err != nil {
+			return nil, fmt.Errorf("retrieving balance: %w", err)
+		}
+		ms[i] = br.Credits
+	}
+	return cv.Consolidate(ms...)
+}
+
+// CreditsSpent consolidates the credits spent by every client's account.
+func (cv *Converter) CreditsSpent(clients ...*Client) (*Consolidated, error) {
+	ms := make([]currency.Money, len(clients))
+	for i, c := range clients {
+		asr, err := c.AccountStats()
+		if err != nil {
+			return nil, fmt.Errorf("retrieving account stats: %w", err)
+		}
+		ms[i] = asr.CreditsSpent
+	}
+	return cv.Consolidate(ms...)
+}
+
+// Orders consolidates the total credits of orders, which may have been placed from different accounts.
+func (cv *Converter) Orders(orders ...*Order) (*Consolidated, error) {
+	ms := make([]currency.Money, len(orders))
+	for i, o := range orders {
+		ms[i] = o.Credits
+	}
+	return cv.Consolidate(ms...)
+}
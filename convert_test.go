@@ -0,0 +1,53 @@
+package gengo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo/currency"
+)
+
+func TestConverterBalances(t *testing.T) {
+	rates := &currency.Table{
+		Base:  currency.USD,
+		Date:  time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+		Rates: map[currency.Code]float64{currency.JPY: 100, currency.EURO: 0.8},
+	}
+	var clients []*Client
+	for _, balance := range []string{
+		`{"credits":"10.00","currency":"USD"}`,
+		`{"credits":"1500","currency":"JPY"}`,
+		`{"credits":"4.00","currency":"EUR"}`,
+	} {
+		c, api := newFakeClient()
+		api.Handle("GET /account/balance", balance)
+		api.Handle("GET /account/stats", `{"credits_spent":"2.00","currency":"EUR"}`)
+		clients = append(clients, c)
+	}
+	cv := NewConverter(currency.USD, rates)
+	con, err := cv.Balances(clients...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if con.Total != currency.MustParse("30", currency.USD) || len(con.Amounts) != 3 {
+		t.Errorf("Balances() = %+v", con)
+	}
+	if a := con.Amounts[1]; a.Original.Currency != currency.JPY || a.Converted.String() != "15.00 USD" || !a.Rate.Date.Equal(rates.Date) {
+		t.Errorf("unexpected converted balance %+v", a)
+	}
+	con, err = cv.CreditsSpent(clients...)
+	if err != nil || con.Total.String() != "7.50 USD" {
+		t.Errorf("CreditsSpent() = %+v, %v", con, err)
+	}
+	orders := []*Order{
+		{Credits: currency.MustParse("1.25", currency.USD), Currency: currency.USD},
+		{Credits: currency.MustParse("500", currency.JPY), Currency: currency.JPY},
+	}
+	con, err = NewConverter(currency.JPY, rates).Orders(orders...)
+	if err != nil || con.Total.String() != "625 JPY" {
+		t.Errorf("Orders() = %+v, %v", con, err)
+	}
+	if _, err := cv.Consolidate(currency.MustParse("1", "GBP")); err == nil {
+		t.Error("expected an error converting a currency without a rate")
+	}
+}
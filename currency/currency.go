@@ -1,10 +1,28 @@
 package currency
 
+// Code is an ISO 4217 currency code.
+type Code string
+
 const (
 	// USD US dollar currency code
-	USD = "USD"
+	USD = Code("USD")
 	// JPY Japanese yen currency code
-	JPY = "JPY"
+	JPY = Code("JPY")
 	// EURO Euro currency code
-	EURO = "EUR"
+	EURO = Code("EUR")
 )
+
+// digits is the number of decimal places of each currency's minor unit.
+var digits = map[Code]int{
+	USD:  2,
+	JPY:  0,
+	EURO: 2,
+}
+
+// Digits returns the number of decimal places the currency is paid in. Unknown currencies have two.
+func (c Code) Digits() int {
+	if d, ok := digits[c]; ok {
+		return d
+	}
+	return 2
+}
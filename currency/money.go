@@ -0,0 +1,251 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places Money keeps exactly, enough for Gengo's unit prices.
+const Scale = 4
+
+const unit = 10000
+
+// ErrCurrencyMismatch is returned when combining money in different currencies.
+var ErrCurrencyMismatch = errors.New("currency: mismatched currencies")
+
+// Money is an exact decimal amount of a currency.
+//
+// Money without a currency, such as the zero value, combines with money in any currency.
+// It marshals to JSON as a decimal string, the form Gengo uses, and unmarshals from strings, numbers or null.
+type Money struct {
+	amount   int64
+	Currency Code
+}
+
+// New creates Money from an amount in the currency's minor unit, such as cents.
+func New(minor int64, c Code) Money {
+	m := Money{amount: minor, Currency: c}
+	for i := c.Digits(); i < Scale; i++ {
+		m.amount *= 10
+	}
+	return m
+}
+
+// FromFloat creates Money from a float, rounded to Scale decimal places.
+func FromFloat(f float64, c Code) Money {
+	return Money{amount: int64(math.Round(f * unit)), Currency: c}
+}
+
+// Parse creates Money from a decimal string such as "12.50". Digits beyond Scale are rounded half away from zero.
+func Parse(s string, c Code) (Money, error) {
+	m := Money{Currency: c}
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return m, fmt.Errorf("currency: invalid amount %q", s)
+	}
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" && frac == "" || strings.Trim(whole+frac, "0123456789") != "" {
+		return m, fmt.Errorf("currency: invalid amount %q", s)
+	}
+	var amount int64
+	if whole != "" {
+		w, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || w > math.MaxInt64/unit {
+			return m, fmt.Errorf("currency: amount %q out of range", s)
+		}
+		amount = w * unit
+	}
+	round := len(frac) > Scale && frac[Scale] >= '5'
+	for i := 0; i < Scale; i++ {
+		d := int64(0)
+		if i < len(frac) {
+			d = int64(frac[i] - '0')
+		}
+		amount += d * int64(math.Pow10(Scale-1-i))
+	}
+	if round {
+		amount++
+	}
+	if neg {
+		amount = -amount
+	}
+	m.amount = amount
+	return m, nil
+}
+
+// MustParse is like Parse but panics if the amount is invalid.
+func MustParse(s string, c Code) Money {
+	m, err := Parse(s, c)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// In returns the same amount in the given currency.
+func (m Money) In(c Code) Money {
+	m.Currency = c
+	return m
+}
+
+func (m Money) currency(o Money) (Code, error) {
+	switch {
+	case m.Currency == o.Currency || o.Currency == "":
+		return m.Currency, nil
+	case m.Currency == "":
+		return o.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+}
+
+// Add returns m+o.
+func (m Money) Add(o Money) (Money, error) {
+	c, err := m.currency(o)
+	if err != nil {
+		return m, err
+	}
+	return Money{amount: m.amount + o.amount, Currency: c}, nil
+}
+
+// Sub returns m-o.
+func (m Money) Sub(o Money) (Money, error) {
+	c, err := m.currency(o)
+	if err != nil {
+		return m, err
+	}
+	return Money{amount: m.amount - o.amount, Currency: c}, nil
+}
+
+// Mul returns m multiplied by n, such as a unit price by a unit count.
+func (m Money) Mul(n int64) Money {
+	m.amount *= n
+	return m
+}
+
+// Cmp compares m and o, returning -1 if m < o, 0 if m == o and +1 if m > o.
+func (m Money) Cmp(o Money) (int, error) {
+	if _, err := m.currency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < o.amount:
+		return -1, nil
+	case m.amount > o.amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Sum adds up money in a single currency.
+func Sum(ms ...Money) (Money, error) {
+	var total Money
+	var err error
+	for _, m := range ms {
+		if total, err = total.Add(m); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Round rounds m half away from zero to the currency's minor unit, such as cents.
+func (m Money) Round() Money {
+	step := int64(math.Pow10(Scale - m.Currency.Digits()))
+	rem := m.amount % step
+	m.amount -= rem
+	if 2*rem >= step {
+		m.amount += step
+	} else if 2*rem <= -step {
+		m.amount -= step
+	}
+	return m
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (m Money) Sign() int {
+	switch {
+	case m.amount < 0:
+		return -1
+	case m.amount > 0:
+		return 1
+	}
+	return 0
+}
+
+// Float64 returns the amount as a float, for display or statistics only.
+func (m Money) Float64() float64 {
+	return float64(m.amount) / unit
+}
+
+// Amount formats the amount as a decimal string with at least the currency's minor unit digits, such as "12.50".
+func (m Money) Amount() string {
+	a := m.amount
+	sign := ""
+	if a < 0 {
+		sign = "-"
+		a = -a
+	}
+	frac := fmt.Sprintf("%0*d", Scale, a%unit)
+	keep := m.Currency.Digits()
+	for len(frac) > keep && frac[len(frac)-1] == '0' {
+		frac = frac[:len(frac)-1]
+	}
+	if frac == "" {
+		return fmt.Sprintf("%s%d", sign, a/unit)
+	}
+	return fmt.Sprintf("%s%d.%s", sign, a/unit, frac)
+}
+
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount()
+	}
+	return m.Amount() + " " + string(m.Currency)
+}
+
+// MarshalJSON implements the Marshaler interface for Money.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Amount())
+}
+
+// UnmarshalJSON implements the Unmarshaler interface for Money. The currency is left unchanged.
+func (m *Money) UnmarshalJSON(d []byte) error {
+	s := strings.TrimSpace(string(d))
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(d, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			m.amount = 0
+			return nil
+		}
+	}
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("currency: invalid amount %s", d)
+		}
+		m.amount = FromFloat(f, m.Currency).amount
+		return nil
+	}
+	p, err := Parse(s, m.Currency)
+	if err != nil {
+		return err
+	}
+	m.amount = p.amount
+	return nil
+}
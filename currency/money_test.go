@@ -0,0 +1,92 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		code Code
+		want string
+	}{
+		{"12.5", USD, "12.50 USD"},
+		{"0.0525", USD, "0.0525 USD"},
+		{"0.00005", USD, "0.0001 USD"},
+		{"-3.14159", EURO, "-3.1416 EUR"},
+		{"1200", JPY, "1200 JPY"},
+		{".5", "", "0.50"},
+	}
+	for _, tt := range tests {
+		m, err := Parse(tt.in, tt.code)
+		if err != nil || m.String() != tt.want {
+			t.Errorf("Parse(%q) = %s, %v, want %s", tt.in, m, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "abc", "1.2.3", "--1", "1e3"} {
+		if _, err := Parse(in, USD); err == nil {
+			t.Errorf("Parse(%q) should fail", in)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	price := MustParse("0.06", USD)
+	total, err := Sum(price.Mul(3), MustParse("0.10", USD), Money{})
+	if err != nil || total != MustParse("0.28", USD) {
+		t.Errorf("Sum() = %s, %v", total, err)
+	}
+	if d, err := total.Sub(MustParse("0.30", USD)); err != nil || d.Sign() != -1 || d.Amount() != "-0.02" {
+		t.Errorf("Sub() = %s, %v", d, err)
+	}
+	if n, err := total.Cmp(MustParse("0.28", "")); err != nil || n != 0 {
+		t.Errorf("Cmp() = %d, %v", n, err)
+	}
+	if _, err := total.Add(MustParse("1", JPY)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() across currencies = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := Sum(MustParse("1", USD), MustParse("1", EURO)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sum() across currencies = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{MustParse("0.0525", USD).Mul(7), "0.37"},
+		{MustParse("0.005", USD), "0.01"},
+		{MustParse("-0.005", USD), "-0.01"},
+		{MustParse("0.004", USD), "0.00"},
+		{MustParse("10.5", JPY), "11"},
+		{New(1250, USD), "12.50"},
+		{New(1250, JPY), "1250"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Round().Amount(); got != tt.want {
+			t.Errorf("%s.Round() = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		A, B, C, D Money
+	}
+	if err := json.Unmarshal([]byte(`{"A":"12.50","B":3.2,"C":null,"D":""}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A.Amount() != "12.50" || v.B.Amount() != "3.20" || !v.C.IsZero() || !v.D.IsZero() {
+		t.Errorf("unexpected amounts %+v", v)
+	}
+	b, err := json.Marshal(MustParse("12.5", USD))
+	if err != nil || string(b) != `"12.50"` {
+		t.Errorf("Marshal() = %s, %v", b, err)
+	}
+	if err := json.Unmarshal([]byte(`"twelve"`), &v.A); err == nil {
+		t.Error("expected an error unmarshalling an invalid amount")
+	}
+}
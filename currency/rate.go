@@ -0,0 +1,170 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+// DateFormat is the layout of rate dates in rate tables.
+const DateFormat = "2006-01-02"
+
+// ErrNoRate is returned when a RateProvider has no rate between two currencies.
+var ErrNoRate = errors.New("currency: no exchange rate")
+
+// Rate is the value of one unit of From in To, as of Date.
+type Rate struct {
+	From  Code      `json:"from"`
+	To    Code      `json:"to"`
+	Value float64   `json:"value"`
+	Date  time.Time `json:"date"`
+}
+
+// Convert converts money in the rate's From currency, or without a currency, into its To currency.
+func (r Rate) Convert(m Money) (Money, error) {
+	if m.Currency != "" && m.Currency != r.From {
+		return m, fmt.Errorf("%w: cannot convert %s with a %s rate", ErrCurrencyMismatch, m.Currency, r.From)
+	}
+	return Money{amount: int64(math.Round(float64(m.amount) * r.Value)), Currency: r.To}, nil
+}
+
+// RateProvider provides exchange rates.
+type RateProvider interface {
+	Rate(from, to Code) (Rate, error)
+}
+
+// Convert converts m into the currency to using rates from p, returning the rate used.
+// Money already in to is returned unchanged with a rate of 1.
+func Convert(m Money, to Code, p RateProvider) (Money, Rate, error) {
+	if m.Currency == to {
+		return m, Rate{From: to, To: to, Value: 1}, nil
+	}
+	if m.Currency == "" {
+		return m, Rate{}, fmt.Errorf("currency: cannot convert %s without a currency", m.Amount())
+	}
+	r, err := p.Rate(m.Currency, to)
+	if err != nil {
+		return m, r, err
+	}
+	c, err := r.Convert(m)
+	return c, r, err
+}
+
+// Table is a RateProvider with fixed rates, each the value of one unit of Base in a currency, as of Date.
+// Rates between two non-base currencies are crossed through Base.
+type Table struct {
+	Base  Code
+	Date  time.Time
+	Rates map[Code]float64
+}
+
+// Rate implements RateProvider.
+func (t *Table) Rate(from, to Code) (Rate, error) {
+	r := Rate{From: from, To: to, Date: t.Date}
+	f, ok := t.value(from)
+	if !ok {
+		return r, fmt.Errorf("%w from %s", ErrNoRate, from)
+	}
+	v, ok := t.value(to)
+	if !ok {
+		return r, fmt.Errorf("%w to %s", ErrNoRate, to)
+	}
+	r.Value = v / f
+	return r, nil
+}
+
+func (t *Table) value(c Code) (float64, bool) {
+	if c == t.Base {
+		return 1, true
+	}
+	v, ok := t.Rates[c]
+	return v, ok && v > 0
+}
+
+type table struct {
+	Base  Code             `json:"base"`
+	Date  string           `json:"date"`
+	Rates map[Code]float64 `json:"rates"`
+}
+
+// MarshalJSON implements the Marshaler interface for Table.
+func (t *Table) MarshalJSON() ([]byte, error) {
+	x := table{Base: t.Base, Rates: t.Rates}
+	if !t.Date.IsZero() {
+		x.Date = t.Date.Format(DateFormat)
+	}
+	return json.Marshal(x)
+}
+
+// UnmarshalJSON implements the Unmarshaler interface for Table.
+// The date may be given as a day, such as "2020-01-31", or as an RFC 3339 time.
+func (t *Table) UnmarshalJSON(d []byte) error {
+	var x table
+	if err := json.Unmarshal(d, &x); err != nil {
+		return err
+	}
+	*t = Table{Base: x.Base, Rates: x.Rates}
+	if x.Date == "" {
+		return nil
+	}
+	var err error
+	if t.Date, err = time.Parse(DateFormat, x.Date); err != nil {
+		if t.Date, err = time.Parse(time.RFC3339, x.Date); err != nil {
+			return fmt.Errorf("currency: invalid rate date %q", x.Date)
+		}
+	}
+	return nil
+}
+
+// File is a RateProvider reading a Table from a JSON file, such as
+//
+//	{"base": "USD", "date": "2020-01-31", "rates": {"JPY": 108.9, "EUR": 0.9}}
+//
+// The file is read again whenever it changes, so that rates can be updated without restarting.
+type File struct {
+	Path string
+
+	mu      sync.Mutex
+	table   *Table
+	modTime time.Time
+}
+
+// NewFile creates a new File reading rates from path.
+func NewFile(path string) *File {
+	return &File{Path: path}
+}
+
+// Rate implements RateProvider.
+func (f *File) Rate(from, to Code) (Rate, error) {
+	t, err := f.load()
+	if err != nil {
+		return Rate{From: from, To: to}, err
+	}
+	return t.Rate(from, to)
+}
+
+func (f *File) load() (*Table, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fi, err := os.Stat(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	if f.table != nil && fi.ModTime().Equal(f.modTime) {
+		return f.table, nil
+	}
+	b, err := os.ReadFile(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	t := new(Table)
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, fmt.Errorf("reading rates %s: %v", f.Path, err)
+	}
+	f.table, f.modTime = t, fi.ModTime()
+	return t, nil
+}
@@ -0,0 +1,66 @@
+package currency
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testTable = &Table{
+	Base:  USD,
+	Date:  time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+	Rates: map[Code]float64{JPY: 110, EURO: 0.9},
+}
+
+func TestTableRate(t *testing.T) {
+	tests := []struct {
+		from, to Code
+		in, want Money
+	}{
+		{USD, JPY, MustParse("12.50", USD), MustParse("1375", JPY)},
+		{JPY, USD, MustParse("1100", JPY), MustParse("10", USD)},
+		{EURO, JPY, MustParse("9", EURO), MustParse("1100", JPY)},
+	}
+	for _, tt := range tests {
+		got, r, err := Convert(tt.in, tt.to, testTable)
+		if err != nil || got.Round() != tt.want || r.From != tt.from || !r.Date.Equal(testTable.Date) {
+			t.Errorf("Convert(%s, %s) = %s, %+v, %v, want %s", tt.in, tt.to, got, r, err, tt.want)
+		}
+	}
+	if _, err := testTable.Rate(USD, "GBP"); !errors.Is(err, ErrNoRate) {
+		t.Errorf("Rate(USD, GBP) = %v, want ErrNoRate", err)
+	}
+	if m, r, err := Convert(MustParse("3", JPY), JPY, nil); err != nil || r.Value != 1 || m != MustParse("3", JPY) {
+		t.Errorf("Convert() to the same currency = %s, %+v, %v", m, r, err)
+	}
+	if _, err := (Rate{From: USD, To: JPY, Value: 110}).Convert(MustParse("1", EURO)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Rate.Convert() of the wrong currency = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	f := NewFile(path)
+	if _, err := f.Rate(USD, JPY); err == nil {
+		t.Error("expected an error reading a missing rates file")
+	}
+	if err := os.WriteFile(path, []byte(`{"base":"USD","date":"2020-01-31","rates":{"JPY":110}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := f.Rate(JPY, USD)
+	if err != nil || r.Value != 1.0/110 || r.Date.Format(DateFormat) != "2020-01-31" {
+		t.Errorf("Rate(JPY, USD) = %+v, %v", r, err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.WriteFile(path, []byte(`{"base":"USD","date":"2020-02-01T00:00:00Z","rates":{"JPY":100}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := f.Rate(USD, JPY); err != nil || r.Value != 100 || r.Date.Format(DateFormat) != "2020-02-01" {
+		t.Errorf("Rate(USD, JPY) after update = %+v, %v", r, err)
+	}
+}
@@ -0,0 +1,307 @@
+// Package diff computes word and character level differences between texts,
+// such as two revisions of a Gengo translation.
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Op is the operation applied to a span of text.
+type Op int
+
+const (
+	// Equal marks text present in both texts.
+	Equal Op = iota
+	// Insert marks text only present in the new text.
+	Insert
+	// Delete marks text only present in the old text.
+	Delete
+)
+
+func (o Op) String() string {
+	switch o {
+	case Equal:
+		return "equal"
+	case Insert:
+		return "insert"
+	case Delete:
+		return "delete"
+	}
+	return fmt.Sprintf("Op(%d)", int(o))
+}
+
+// MarshalJSON implements the Marshaler interface for Op.
+func (o Op) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.String())
+}
+
+// UnmarshalJSON implements the Unmarshaler interface for Op.
+func (o *Op) UnmarshalJSON(d []byte) error {
+	var s string
+	if err := json.Unmarshal(d, &s); err != nil {
+		return err
+	}
+	for _, op := range []Op{Equal, Insert, Delete} {
+		if op.String() == s {
+			*o = op
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown diff op %q", s)
+}
+
+// Edit is a span of text and the operation applied to it.
+type Edit struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Diff is the list of edits turning an old text into a new text.
+type Diff []Edit
+
+// Words computes a word level diff between a and b.
+// Whitespace and punctuation are kept as separate tokens so the texts can be rebuilt exactly.
+func Words(a, b string) Diff {
+	return compute(splitWords(a), splitWords(b))
+}
+
+// Chars computes a character level diff between a and b,
+// which suits languages such as Japanese or Chinese that do not delimit words with spaces.
+func Chars(a, b string) Diff {
+	return compute(splitChars(a), splitChars(b))
+}
+
+// Changed reports whether the diff contains any insertions or deletions.
+func (d Diff) Changed() bool {
+	for _, e := range d {
+		if e.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// Old rebuilds the old text from the diff.
+func (d Diff) Old() string {
+	return d.text(Delete)
+}
+
+// New rebuilds the new text from the diff.
+func (d Diff) New() string {
+	return d.text(Insert)
+}
+
+func (d Diff) text(side Op) string {
+	var b strings.Builder
+	for _, e := range d {
+		if e.Op == Equal || e.Op == side {
+			b.WriteString(e.Text)
+		}
+	}
+	return b.String()
+}
+
+// Unified renders the diff inline in the style of git's word diff,
+// wrapping deletions in [-...-] and insertions in {+...+}.
+func (d Diff) Unified() string {
+	var b strings.Builder
+	for _, e := range d {
+		switch e.Op {
+		case Equal:
+			b.WriteString(e.Text)
+		case Insert:
+			b.WriteString("{+" + e.Text + "+}")
+		case Delete:
+			b.WriteString("[-" + e.Text + "-]")
+		}
+	}
+	return b.String()
+}
+
+// HTML renders the diff as escaped HTML using <del> and <ins> elements.
+func (d Diff) HTML() string {
+	var b bytes.Buffer
+	for _, e := range d {
+		text := html.EscapeString(e.Text)
+		switch e.Op {
+		case Equal:
+			b.WriteString(text)
+		case Insert:
+			b.WriteString("<ins>" + text + "</ins>")
+		case Delete:
+			b.WriteString("<del>" + text + "</del>")
+		}
+	}
+	return b.String()
+}
+
+type class int
+
+const (
+	classSpace class = iota
+	classWord
+	classOther
+)
+
+func classify(r rune) class {
+	switch {
+	case unicode.IsSpace(r):
+		return classSpace
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+		return classWord
+	}
+	return classOther
+}
+
+func splitWords(s string) []string {
+	var tokens []string
+	start := -1
+	var last class
+	for i, r := range s {
+		c := classify(r)
+		if start >= 0 && (c != last || c == classOther) {
+			tokens = append(tokens, s[start:i])
+			start = -1
+		}
+		if start < 0 {
+			start = i
+		}
+		last = c
+	}
+	if start >= 0 {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func splitChars(s string) []string {
+	tokens := make([]string, 0, len(s))
+	for _, r := range s {
+		tokens = append(tokens, string(r))
+	}
+	return tokens
+}
+
+// compute runs the Myers diff algorithm over the tokens.
+func compute(a, b []string) Diff {
+	return Diff(nil).script(a, b)
+}
+
+// append adds tokens to the diff, merging them into the last edit if it has the same operation.
+func (d Diff) append(op Op, tokens ...string) Diff {
+	for _, t := range tokens {
+		if n := len(d); n > 0 && d[n-1].Op == op {
+			d[n-1].Text += t
+			continue
+		}
+		d = append(d, Edit{Op: op, Text: t})
+	}
+	return d
+}
+
+// script appends the edits turning a into b to the diff. It uses the linear space variant of the Myers algorithm:
+// after trimming the common prefix and suffix, the texts are split where the forward and backward searches of the
+// shortest edit script meet, and each half is diffed in turn, so memory stays proportional to the texts.
+func (d Diff) script(a, b []string) Diff {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	d = d.append(Equal, a[:prefix]...)
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	switch x, y := middleSnake(midA, midB); {
+	case len(midA) == 0 || len(midB) == 0 || x <= 0 && y <= 0 || x >= len(midA) && y >= len(midB):
+		d = d.append(Delete, midA...)
+		d = d.append(Insert, midB...)
+	default:
+		d = d.script(midA[:x], midB[:y])
+		d = d.script(midA[x:], midB[y:])
+	}
+	return d.append(Equal, a[len(a)-suffix:]...)
+}
+
+// middleSnake returns the point of the edit graph of a and b where the shortest edit scripts searched from both
+// ends meet, or -1, -1 if the texts have nothing in common.
+func middleSnake(a, b []string) (int, int) {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return -1, -1
+	}
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	size := 2*maxD + 2
+	forward, backward := make([]int, size), make([]int, size)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	delta := n - m
+	// With an odd delta the searches meet on a forward step, otherwise on a backward one.
+	odd := delta%2 != 0
+	// The ranges of diagonals which have not run off the graph.
+	var fStart, fEnd, bStart, bEnd int
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[i] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				if j := offset + delta - k; j >= 0 && j < size && backward[j] != -1 && x >= n-backward[j] {
+					return x, y
+				}
+			}
+		}
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x = backward[i+1]
+			} else {
+				x = backward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[i] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !odd:
+				if j := offset + delta - k; j >= 0 && j < size && forward[j] != -1 {
+					fx := forward[j]
+					if fx >= n-x {
+						return fx, fx - (j - offset)
+					}
+				}
+			}
+		}
+	}
+	return -1, -1
+}
@@ -0,0 +1,121 @@
+package diff
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		unified string
+	}{
+		{"", "", ""},
+		{"same text", "same text", "same text"},
+		{"the quick fox", "the slow fox", "the [-quick-]{+slow+} fox"},
+		{"Hello, world.", "Hello, brave new world.", "Hello, {+brave new +}world."},
+		{"drop this word", "drop word", "drop [-this -]word"},
+		{"", "all new", "{+all new+}"},
+	}
+	for _, tt := range tests {
+		d := Words(tt.a, tt.b)
+		if u := d.Unified(); u != tt.unified {
+			t.Errorf("Words(%q, %q).Unified() = %q, want %q", tt.a, tt.b, u, tt.unified)
+		}
+		if d.Old() != tt.a || d.New() != tt.b {
+			t.Errorf("Words(%q, %q) rebuilds %q and %q", tt.a, tt.b, d.Old(), d.New())
+		}
+	}
+}
+
+func TestChars(t *testing.T) {
+	d := Chars("翻訳するテキスト", "翻訳したテキスト")
+	if u := d.Unified(); u != "翻訳[-する-]{+した+}テキスト" {
+		t.Errorf("Chars().Unified() = %q", u)
+	}
+	if d.Old() != "翻訳するテキスト" || d.New() != "翻訳したテキスト" {
+		t.Errorf("Chars() rebuilds %q and %q", d.Old(), d.New())
+	}
+}
+
+// lcs returns the length of the longest common subsequence of a and b, which a shortest diff keeps equal.
+func lcs(a, b []string) int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func TestCharsShortest(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	text := func() string {
+		b := make([]byte, r.Intn(30))
+		for i := range b {
+			b[i] = "abcd"[r.Intn(4)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 500; i++ {
+		a, b := text(), text()
+		d := Chars(a, b)
+		if d.Old() != a || d.New() != b {
+			t.Fatalf("Chars(%q, %q) rebuilds %q and %q", a, b, d.Old(), d.New())
+		}
+		equal := 0
+		for _, e := range d {
+			if e.Op == Equal {
+				equal += len(e.Text)
+			}
+		}
+		if want := lcs(splitChars(a), splitChars(b)); equal != want {
+			t.Fatalf("Chars(%q, %q) keeps %d characters, want %d: %s", a, b, equal, want, d.Unified())
+		}
+	}
+}
+
+func TestCharsLarge(t *testing.T) {
+	a := strings.Repeat("abcdefghij", 1000)
+	b := strings.Repeat("abcdefghiX", 1000)
+	if d := Chars(a, b); d.Old() != a || d.New() != b {
+		t.Error("Chars() of large texts does not rebuild them")
+	}
+}
+
+func TestHTML(t *testing.T) {
+	d := Words("a <b>", "a <i>")
+	if h := d.HTML(); h != "a &lt;<del>b</del><ins>i</ins>&gt;" {
+		t.Errorf("HTML() = %q", h)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	d := Words("old text", "new text")
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `[{"op":"delete","text":"old"},{"op":"insert","text":"new"},{"op":"equal","text":" text"}]` {
+		t.Errorf("json.Marshal() = %s", b)
+	}
+	var back Diff
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, d) {
+		t.Errorf("round trip = %v, want %v", back, d)
+	}
+}
@@ -0,0 +1,82 @@
+package gengo
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/trinchan/gengo/currency"
+	"github.com/trinchan/gengo/lang"
+)
+
+// CountUnits counts text the way Gengo bills it in the given language.
+// Languages counted in words count runs of non-space characters containing a letter or digit;
+// languages counted in characters, such as Japanese, count every character except spaces.
+func CountUnits(text string, code lang.Code) int {
+	n := 0
+	if code.UnitType() == lang.UnitCharacter {
+		for _, r := range text {
+			if !unicode.IsSpace(r) {
+				n++
+			}
+		}
+		return n
+	}
+	for _, word := range strings.Fields(text) {
+		if strings.IndexFunc(word, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Estimator quotes text jobs locally by counting units and applying the unit prices from a Catalog,
+// avoiding a QuoteText() call per batch. Estimates do not include an ETA. Units are counted by Gengo's published
+// rules, which its quotes may not follow exactly for every text; QuoteText() remains the reference.
+type Estimator struct {
+	catalog *Catalog
+}
+
+// NewEstimator creates a new Estimator pricing jobs with the catalog's unit prices.
+func NewEstimator(c *Catalog) *Estimator {
+	return &Estimator{catalog: c}
+}
+
+// QuoteText estimates the cost of the jobs in the request, returning a response shaped like the QuoteText() endpoint's.
+func (e *Estimator) QuoteText(req *QuoteTextRequest) (*QuoteTextResponse, error) {
+	qr := &QuoteTextResponse{Jobs: make([]TextQuote, 0, len(req.Jobs))}
+	for i, job := range req.Jobs {
+		q, err := e.quote(job)
+		if err != nil {
+			return nil, fmt.Errorf("estimating job %d: %w", i, err)
+		}
+		qr.Jobs = append(qr.Jobs, q)
+	}
+	return qr, nil
+}
+
+func (e *Estimator) quote(job *JobRequest) (TextQuote, error) {
+	if job.BodySrc == nil {
+		return TextQuote{}, fmt.Errorf("only text jobs can be estimated")
+	}
+	price, err := e.catalog.UnitPrice(job.Pair, job.Tier)
+	if err != nil {
+		return TextQuote{}, err
+	}
+	units := CountUnits(*job.BodySrc, job.Source)
+	return TextQuote{
+		Type:      JobTypeText,
+		Credits:   price.Mul(int64(units)).Round(),
+		UnitCount: Int(units),
+		Currency:  price.Currency,
+	}, nil
+}
+
+// Total returns the total credits of every quoted job. Quotes in different currencies cannot be totalled.
+func (q *QuoteTextResponse) Total() (currency.Money, error) {
+	credits := make([]currency.Money, len(q.Jobs))
+	for i, j := range q.Jobs {
+		credits[i] = j.Credits
+	}
+	return currency.Sum(credits...)
+}
@@ -0,0 +1,135 @@
+package gengo
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/trinchan/gengo/currency"
+	"github.com/trinchan/gengo/lang"
+)
+
+// quoteSample is a job of testdata/estimates.json with its units counted by hand by the rules of CountUnits, and
+// the credits they cost at the unit price of its pair.
+type quoteSample struct {
+	LanguagePairWithPrice
+	BodySrc   string         `json:"body_src"`
+	UnitCount int            `json:"unit_count"`
+	Credits   currency.Money `json:"credits"`
+}
+
+func loadQuoteSamples(t *testing.T) []quoteSample {
+	t.Helper()
+	b, err := os.ReadFile("testdata/estimates.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var samples []quoteSample
+	if err := json.Unmarshal(b, &samples); err != nil {
+		t.Fatal(err)
+	}
+	return samples
+}
+
+func TestCountUnits(t *testing.T) {
+	tests := []struct {
+		text string
+		code lang.Code
+		want int
+	}{
+		{"", lang.English, 0},
+		// Welcome / back! / You / have / 3 / new / messages.
+		{"Welcome back! You have 3 new messages.", lang.English, 7},
+		// The dash and the lone punctuation marks are not words.
+		{"Tap \"Save\" — or not ?", lang.English, 4},
+		{"  line one\n\tline two  ", lang.English, 4},
+		{"soporte@example.com 2-3", lang.Spanish, 2},
+		// 本日は / ご利用 / ありがとう / ございます / 。: 3 + 3 + 5 + 5 + 1, without the ideographic space.
+		{"本日は　ご利用 ありがとうございます。", lang.Japanese, 17},
+		// ข อ บ ค ุ ณ: the vowel sign is a character of its own.
+		{"ขอบคุณ", lang.Thai, 6},
+	}
+	for _, tt := range tests {
+		if n := CountUnits(tt.text, tt.code); n != tt.want {
+			t.Errorf("CountUnits(%q, %s) = %d, want %d", tt.text, tt.code, n, tt.want)
+		}
+	}
+	for _, s := range loadQuoteSamples(t) {
+		if n := CountUnits(s.BodySrc, s.Source); n != s.UnitCount {
+			t.Errorf("CountUnits(%q, %s) = %d, want %d", s.BodySrc, s.Source, n, s.UnitCount)
+		}
+	}
+}
+
+func TestEstimator(t *testing.T) {
+	samples := loadQuoteSamples(t)
+	pairs := make([]LanguagePairWithPrice, len(samples))
+	jobs := make([]*JobRequest, len(samples))
+	for i, s := range samples {
+		pairs[i] = s.LanguagePairWithPrice
+		jobs[i] = NewJobRequest(s.BodySrc, s.Pair, s.Tier)
+	}
+	b, _ := json.Marshal(pairs)
+	c, api := newFakeClient()
+	api.Handle("GET /translate/service/language_pairs", string(b))
+	api.Handle("GET /translate/service/languages", `[]`)
+
+	qr, err := NewEstimator(NewCatalog(c)).QuoteText(NewQuoteTextRequest(jobs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := currency.New(0, currency.USD)
+	for i, s := range samples {
+		q := qr.Jobs[i]
+		quoted := s.Credits.In(s.Currency)
+		if int(q.UnitCount) != s.UnitCount || q.Credits != quoted {
+			t.Errorf("estimate for %s %q = %d units, %s, want %d units, %s",
+				s.Source, s.BodySrc, q.UnitCount, q.Credits, s.UnitCount, quoted)
+		}
+		want, _ = want.Add(quoted)
+	}
+	if total, err := qr.Total(); err != nil || total != want {
+		t.Errorf("Total() = %s, %v, want %s", total, err, want)
+	}
+}
+
+// TestEstimatorMatchesGengo compares estimates of the texts of testdata/estimates.json with live quotes.
+// It only runs when GENGO_PUBLIC_KEY and GENGO_PRIVATE_KEY are set.
+func TestEstimatorMatchesGengo(t *testing.T) {
+	if os.Getenv("GENGO_PUBLIC_KEY") == "" || os.Getenv("GENGO_PRIVATE_KEY") == "" {
+		t.Skip("GENGO_PUBLIC_KEY and GENGO_PRIVATE_KEY are not set")
+	}
+	c := NewFromEnv()
+	var jobs []*JobRequest
+	for _, s := range loadQuoteSamples(t) {
+		jobs = append(jobs, NewJobRequest(s.BodySrc, s.Pair, s.Tier))
+	}
+	quoted, err := c.QuoteText(NewQuoteTextRequest(jobs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimated, err := NewEstimator(NewCatalog(c)).QuoteText(NewQuoteTextRequest(jobs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quoted.Jobs) != len(jobs) {
+		t.Fatalf("Gengo quoted %d jobs, want %d", len(quoted.Jobs), len(jobs))
+	}
+	for i, job := range jobs {
+		q, e := quoted.Jobs[i], estimated.Jobs[i]
+		if e.UnitCount != q.UnitCount || e.Credits != q.Credits {
+			t.Errorf("estimate for %s %q = %d units, %s; Gengo quoted %d units, %s",
+				job.Source, *job.BodySrc, e.UnitCount, e.Credits, q.UnitCount, q.Credits)
+		}
+	}
+}
+
+func TestEstimatorUnavailablePair(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("GET /translate/service/language_pairs", testLanguagePairs)
+	api.Handle("GET /translate/service/languages", testLanguages)
+	job := NewJobRequest("Bonjour", lang.NewPair(lang.French, lang.Japanese), TierStandard)
+	if _, err := NewEstimator(NewCatalog(c)).QuoteText(NewQuoteTextRequest(job)); err == nil {
+		t.Error("expected an error estimating an unavailable pair")
+	}
+}
@@ -3,6 +3,7 @@ package gengo
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -24,6 +25,8 @@ const (
 	SandboxBaseURL = "http://api.sandbox.gengo.com/v2"
 	// ProductionBaseURL is the Gengo production base URL
 	ProductionBaseURL = "http://api.gengo.com/v2"
+
+	userAgent = "Gengo Go Library; Version 0.0.1; https://www.gengo.com"
 )
 
 // SetLogger lets library users supply a logger, so that api debugging
@@ -39,6 +42,7 @@ type Client struct {
 	BaseURL      string
 	RoundTripper http.RoundTripper
 	signer       sign.Signer
+	budget       *BudgetGuard
 }
 
 // New creates a new Gengo Client with the given keys and base URL
@@ -170,9 +174,54 @@ func (c *Client) vals(body io.Reader) url.Values {
 	}
 	return vals
 }
+
+// open starts retrieving a raw (non-JSON) resource, such as a file, returning its body and content type. The API
+// resources of authenticated requests are signed like other calls, and Gengo reports their failures with its usual
+// JSON envelope, which is decoded into an error; links from API responses, such as captcha images, are opened as is.
+// Redirects are followed, through the client's RoundTripper.
+func (c *Client) open(rawURL string, authenticated bool) (io.ReadCloser, string, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if authenticated {
+		vals := req.URL.Query()
+		for key, param := range c.vals(nil) {
+			vals[key] = param
+		}
+		req.URL.RawQuery = vals.Encode()
+	}
+	req.Header.Set("User-Agent", userAgent)
+	re, err := (&http.Client{Transport: c.RoundTripper}).Do(req)
+	if err != nil {
+		// The URL of the error would reveal the signature of the request.
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+		return nil, "", fmt.Errorf("fetching %s: %w", rawURL, err)
+	}
+	if authenticated && strings.HasPrefix(re.Header.Get("Content-Type"), "application/json") {
+		defer re.Body.Close()
+		r := new(response)
+		if err := json.NewDecoder(re.Body).Decode(r); err != nil {
+			return nil, "", err
+		}
+		if r.OPStat != OPStatOK {
+			return nil, "", r.Error
+		}
+		return nil, "", fmt.Errorf("unexpected json response fetching %s", rawURL)
+	}
+	if re.StatusCode != http.StatusOK {
+		re.Body.Close()
+		return nil, "", fmt.Errorf("fetching %s: %s", rawURL, re.Status)
+	}
+	return re.Body, re.Header.Get("Content-Type"), nil
+}
+
 func (c *Client) do(req *http.Request, resp interface{}) error {
 	req.Header.Add("Accept", "application/json")
-	req.Header.Set("User-Agent", "Gengo Go Library; Version 0.0.1; https://www.gengo.com")
+	req.Header.Set("User-Agent", userAgent)
 	re, err := c.RoundTripper.RoundTrip(req)
 	if err != nil {
 		return err
@@ -187,7 +236,7 @@ func (c *Client) do(req *http.Request, resp interface{}) error {
 	if r.OPStat != OPStatOK {
 		return r.Error
 	}
-	if len(r.Response) > 0 {
+	if len(r.Response) > 0 && resp != nil {
 		err = json.Unmarshal(r.Response, resp)
 	}
 	return err
@@ -12,7 +12,7 @@ func ExampleNew() {
 		fmt.Printf("Error retrieving account stats: %v\n", err)
 	}
 	fmt.Printf("User since: %s\n", r.UserSince)
-	fmt.Printf("Credits spent: %0.2f %s\n", r.CreditsSpent, r.Currency)
+	fmt.Printf("Credits spent: %s\n", r.CreditsSpent)
 }
 
 func ExampleNewFromEnv() {
@@ -22,5 +22,5 @@ func ExampleNewFromEnv() {
 		fmt.Printf("Error retrieving account stats: %v\n", err)
 	}
 	fmt.Printf("User since: %s\n", r.UserSince)
-	fmt.Printf("Credits spent: %0.2f %s\n", r.CreditsSpent, r.Currency)
+	fmt.Printf("Credits spent: %s\n", r.CreditsSpent)
 }
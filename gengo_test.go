@@ -0,0 +1,22 @@
+package gengo
+
+import (
+	"testing"
+
+	"github.com/trinchan/gengo/internal/gengotest"
+)
+
+func newFakeClient() (*Client, *gengotest.API) {
+	api := gengotest.New(nil)
+	c := New("public", "private", SandboxBaseURL)
+	c.SetRoundTripper(api)
+	return c, api
+}
+
+func TestClientError(t *testing.T) {
+	c, _ := newFakeClient()
+	_, err := c.GetOrder(NewOrderGetRequest(1))
+	if _, ok := err.(ErrorResponse); !ok {
+		t.Fatalf("expected ErrorResponse, got %T %v", err, err)
+	}
+}
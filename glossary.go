@@ -3,8 +3,13 @@ package gengo
 // Glossary is implemented in Go, so we can code nicely here thank god
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
 
 	"github.com/trinchan/gengo/lang"
 )
@@ -49,6 +54,27 @@ type GlossaryLanguage struct {
 	Code lang.Code `json:"1"`
 }
 
+// UnmarshalJSON implements the Unmarshaler interface since Gengo sends glossary languages as [id, code] pairs.
+func (g *GlossaryLanguage) UnmarshalJSON(b []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(b, &pair); err != nil {
+		type gl GlossaryLanguage
+		x := new(gl)
+		if err := json.Unmarshal(b, x); err != nil {
+			return err
+		}
+		*g = GlossaryLanguage(*x)
+		return nil
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected [id, code] glossary language, got %s", b)
+	}
+	if err := json.Unmarshal(pair[0], &g.ID); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &g.Code)
+}
+
 // ListGlossaries retrieves a list of glossaries that belongs to the authenticated user.
 func (c *Client) ListGlossaries() (*ListGlossariesResponse, error) {
 	glr := new(ListGlossariesResponse)
@@ -91,3 +117,218 @@ func (c *Client) GetGlossaryByID(req *GetGlossaryRequest) (*GlossaryResponse, er
 	err := c.get(glossaryNamespace+fmt.Sprintf("/%d", req.ID), nil, gr)
 	return gr, err
 }
+
+// GlossaryTerm defines a source term and its translations.
+type GlossaryTerm struct {
+	ID           int                   `json:"id,omitempty"`
+	Source       string                `json:"source"`
+	Translations []GlossaryTranslation `json:"translations"`
+	Comment      string                `json:"comment,omitempty"`
+}
+
+// GlossaryTranslation defines the translation of a GlossaryTerm into a target language.
+type GlossaryTranslation struct {
+	Code lang.Code `json:"lc"`
+	Term string    `json:"term"`
+}
+
+// GlossaryAttributes defines the editable attributes of a glossary.
+type GlossaryAttributes struct {
+	Title  *string `json:"title,omitempty"`
+	Public *Bool   `json:"is_public,omitempty"`
+}
+
+// GlossaryOption sets an attribute of a glossary being created or updated.
+type GlossaryOption func(*GlossaryAttributes)
+
+// WithGlossaryTitle sets the title of the glossary.
+func WithGlossaryTitle(s string) GlossaryOption {
+	return func(a *GlossaryAttributes) {
+		a.Title = &s
+	}
+}
+
+// WithGlossaryPublic sets whether the glossary is shared publicly.
+func WithGlossaryPublic(b Bool) GlossaryOption {
+	return func(a *GlossaryAttributes) {
+		a.Public = &b
+	}
+}
+
+// CreateGlossaryRequest defines the request parameters for the CreateGlossary() endpoint.
+type CreateGlossaryRequest struct {
+	GlossaryAttributes
+	SourceCode  lang.Code      `json:"source_language_code"`
+	TargetCodes []lang.Code    `json:"target_language_codes"`
+	Terms       []GlossaryTerm `json:"terms"`
+}
+
+// NewCreateGlossaryRequest creates a new CreateGlossaryRequest with the given title, languages, terms and options.
+func NewCreateGlossaryRequest(title string, source lang.Code, targets []lang.Code, terms []GlossaryTerm, options ...GlossaryOption) *CreateGlossaryRequest {
+	g := &CreateGlossaryRequest{
+		SourceCode:  source,
+		TargetCodes: targets,
+		Terms:       terms,
+	}
+	g.Title = &title
+	for _, option := range options {
+		option(&g.GlossaryAttributes)
+	}
+	return g
+}
+
+// CreateGlossary creates a new glossary from a list of terms.
+func (c *Client) CreateGlossary(req *CreateGlossaryRequest) (*GlossaryResponse, error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	gr := new(GlossaryResponse)
+	err = c.post(glossaryNamespace, bytes.NewReader(b), gr)
+	return gr, err
+}
+
+// UploadGlossaryRequest defines the request parameters for the UploadGlossary() endpoint.
+type UploadGlossaryRequest struct {
+	GlossaryAttributes
+	ID       int    `json:"-"`
+	FilePath string `json:"-"`
+}
+
+// NewUploadGlossaryRequest creates a new UploadGlossaryRequest which creates a glossary from the given file.
+func NewUploadGlossaryRequest(filePath string, options ...GlossaryOption) *UploadGlossaryRequest {
+	g := &UploadGlossaryRequest{
+		FilePath: filePath,
+	}
+	for _, option := range options {
+		option(&g.GlossaryAttributes)
+	}
+	return g
+}
+
+// NewReplaceGlossaryFileRequest creates a new UploadGlossaryRequest which replaces the terms of the glossary with the given id
+// by the contents of the given file.
+func NewReplaceGlossaryFileRequest(id int, filePath string, options ...GlossaryOption) *UploadGlossaryRequest {
+	g := NewUploadGlossaryRequest(filePath, options...)
+	g.ID = id
+	return g
+}
+
+// UploadGlossary creates a glossary from a file, or replaces an existing glossary's file if the request has an id.
+func (c *Client) UploadGlossary(req *UploadGlossaryRequest) (*GlossaryResponse, error) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filepath.Base(req.FilePath))
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(req.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	_, err = io.Copy(part, file)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	writer.WriteField("data", string(b))
+	path := glossaryNamespace + "/file"
+	if req.ID != 0 {
+		path = glossaryNamespace + fmt.Sprintf("/%d/file", req.ID)
+	}
+	gr := new(GlossaryResponse)
+	err = c.multipart(path, body, writer, gr)
+	return gr, err
+}
+
+// UpdateGlossaryRequest defines the request parameters for the UpdateGlossary() endpoint.
+type UpdateGlossaryRequest struct {
+	GlossaryAttributes
+	ID int `json:"-"`
+}
+
+// NewUpdateGlossaryRequest creates a new UpdateGlossaryRequest with the given id and the attributes to change.
+func NewUpdateGlossaryRequest(id int, options ...GlossaryOption) *UpdateGlossaryRequest {
+	g := &UpdateGlossaryRequest{
+		ID: id,
+	}
+	for _, option := range options {
+		option(&g.GlossaryAttributes)
+	}
+	return g
+}
+
+// UpdateGlossary updates the title or visibility of a glossary.
+func (c *Client) UpdateGlossary(req *UpdateGlossaryRequest) (*GlossaryResponse, error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	gr := new(GlossaryResponse)
+	err = c.put(glossaryNamespace+fmt.Sprintf("/%d", req.ID), bytes.NewReader(b), gr)
+	return gr, err
+}
+
+// DeleteGlossaryRequest defines the request parameters for the DeleteGlossary() endpoint.
+type DeleteGlossaryRequest struct {
+	ID int
+}
+
+// NewDeleteGlossaryRequest creates a new DeleteGlossaryRequest with the given id.
+func NewDeleteGlossaryRequest(id int) *DeleteGlossaryRequest {
+	return &DeleteGlossaryRequest{ID: id}
+}
+
+// DeleteGlossary deletes a glossary.
+func (c *Client) DeleteGlossary(req *DeleteGlossaryRequest) error {
+	err := c.delete(glossaryNamespace+fmt.Sprintf("/%d", req.ID), nil, nil)
+	return err
+}
+
+// GlossaryTermsRequest defines the request parameters for the GlossaryTerms() and DownloadGlossary() endpoints.
+type GlossaryTermsRequest struct {
+	ID int
+}
+
+// NewGlossaryTermsRequest creates a new GlossaryTermsRequest with the given id.
+func NewGlossaryTermsRequest(id int) *GlossaryTermsRequest {
+	return &GlossaryTermsRequest{ID: id}
+}
+
+// GlossaryTermsResponse defines the response from the GlossaryTerms() endpoint.
+type GlossaryTermsResponse struct {
+	Terms []GlossaryTerm
+}
+
+// UnmarshalJSON implements the Unmarshaler interface so we can keep our response types consistent.
+func (g *GlossaryTermsResponse) UnmarshalJSON(b []byte) error {
+	ts := new([]GlossaryTerm)
+	err := json.Unmarshal(b, ts)
+	if err != nil {
+		return err
+	}
+	g.Terms = *ts
+	return nil
+}
+
+// GlossaryTerms retrieves the terms of a glossary.
+func (c *Client) GlossaryTerms(req *GlossaryTermsRequest) (*GlossaryTermsResponse, error) {
+	gtr := new(GlossaryTermsResponse)
+	err := c.get(glossaryNamespace+fmt.Sprintf("/%d/terms", req.ID), nil, gtr)
+	return gtr, err
+}
+
+// DownloadGlossary writes the glossary's file, as it would be uploaded, to w.
+func (c *Client) DownloadGlossary(req *GlossaryTermsRequest, w io.Writer) error {
+	body, _, err := c.open(c.BaseURL+glossaryNamespace+fmt.Sprintf("/%d/download", req.ID), true)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, err = io.Copy(w, body)
+	return err
+}
@@ -0,0 +1,131 @@
+package glossary
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+)
+
+// Violation describes a source term whose mandated translation is missing from a translation.
+type Violation struct {
+	Code    lang.Code
+	Source  string
+	Target  string
+	Comment string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%q should be translated as %q", v.Source, v.Target)
+}
+
+// Checker finds glossary terms which were not translated as mandated.
+//
+// Terms are compared after case folding. Terms written in scripts without spaces between words,
+// such as Japanese, Chinese, Korean and Thai, are matched as substrings. Other terms are matched
+// word by word, tolerating differing word endings so that inflected forms such as "servers" or
+// "cities" match the terms "server" and "city".
+type Checker struct {
+	glossary *Glossary
+	fold     cases.Caser
+	// MaxSuffix is the number of characters an inflected word may differ by at its end. The default is 3.
+	MaxSuffix int
+}
+
+// NewChecker creates a Checker for the terms of g.
+func NewChecker(g *Glossary) *Checker {
+	return &Checker{glossary: g, fold: cases.Fold(), MaxSuffix: 3}
+}
+
+// Check returns the terms found in src whose translation into pair.Target is missing from tgt.
+// Nothing is reported if the pair's source language is not the glossary's source language.
+func (c *Checker) Check(pair lang.Pair, src, tgt string) []Violation {
+	if pair.Source != c.glossary.Source {
+		return nil
+	}
+	src, tgt = c.fold.String(src), c.fold.String(tgt)
+	srcWords, tgtWords := words(src), words(tgt)
+	var violations []Violation
+	for _, t := range c.glossary.Terms {
+		target, ok := t.Translations[pair.Target]
+		if !ok || t.Source == "" {
+			continue
+		}
+		if !c.contains(src, srcWords, t.Source) || c.contains(tgt, tgtWords, target) {
+			continue
+		}
+		violations = append(violations, Violation{Code: pair.Target, Source: t.Source, Target: target, Comment: t.Comment})
+	}
+	return violations
+}
+
+// CheckJob checks the source and translation of a job.
+func (c *Checker) CheckJob(job *gengo.GetJobResponse) []Violation {
+	return c.Check(job.Pair, job.BodySrc, job.BodyTgt)
+}
+
+// contains reports whether the folded text, or its words, contain the term.
+func (c *Checker) contains(text string, textWords []string, term string) bool {
+	term = c.fold.String(term)
+	if unspaced(term) {
+		return strings.Contains(text, strings.Join(strings.Fields(term), ""))
+	}
+	termWords := words(term)
+	if len(termWords) == 0 {
+		return false
+	}
+	for i := 0; i+len(termWords) <= len(textWords); i++ {
+		match := true
+		for j, w := range termWords {
+			if !c.inflected(textWords[i+j], w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// inflected reports whether word is the term word or an inflection of it. Short words must match exactly. Of longer
+// words, the shorter must be the stem of the other, which only adds up to MaxSuffix characters to it. A final "y" of
+// the stem may become "i", as in "city" and "cities".
+func (c *Checker) inflected(word, term string) bool {
+	if word == term {
+		return true
+	}
+	stem, longer := []rune(term), []rune(word)
+	if len(longer) < len(stem) {
+		stem, longer = longer, stem
+	}
+	if len(stem) < 4 || len(longer)-len(stem) > c.MaxSuffix {
+		return false
+	}
+	if stem[len(stem)-1] == 'y' && longer[len(stem)-1] == 'i' {
+		stem = append(stem[:len(stem)-1:len(stem)-1], 'i')
+	}
+	return string(longer[:len(stem)]) == string(stem)
+}
+
+// words splits text into runs of letters, marks and digits.
+func words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+}
+
+// unspaced reports whether the text is written in a script which does not separate words with spaces.
+func unspaced(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package glossary
+
+import (
+	"testing"
+
+	"github.com/trinchan/gengo/lang"
+)
+
+func TestChecker(t *testing.T) {
+	g := New(lang.English, lang.French, lang.Japanese)
+	g.Add(Term{Source: "server", Translations: map[lang.Code]string{lang.French: "serveur", lang.Japanese: "サーバー"}})
+	g.Add(Term{Source: "city", Translations: map[lang.Code]string{lang.French: "ville"}})
+	g.Add(Term{Source: "Sign in", Translations: map[lang.Code]string{lang.French: "se connecter", lang.Japanese: "サインイン"}})
+	g.Add(Term{Source: "app", Translations: map[lang.Code]string{lang.French: "appli"}})
+	g.Add(Term{Source: "date", Translations: map[lang.Code]string{lang.French: "date"}})
+	g.Add(Term{Source: "test", Translations: map[lang.Code]string{lang.French: "test"}})
+	c := NewChecker(g)
+	enFr, enJa := lang.NewPair(lang.English, lang.French), lang.NewPair(lang.English, lang.Japanese)
+
+	tests := []struct {
+		name    string
+		pair    lang.Pair
+		src     string
+		tgt     string
+		missing []string
+	}{
+		{"all present", enFr, "Restart the server", "Redémarrez le serveur", nil},
+		{"case folding", enFr, "SERVER down", "Serveur en panne", nil},
+		{"inflected source", enFr, "Both servers and cities", "Les serveurs et les villes", nil},
+		{"missing", enFr, "Restart the server", "Redémarrez la machine", []string{"server"}},
+		{"multi word", enFr, "Please sign in.", "Veuillez vous connecter.", []string{"Sign in"}},
+		{"inflected target", enFr, "Two servers", "Deux serveurs", nil},
+		{"inflected y", enFr, "Two cities", "Deux endroits", []string{"city"}},
+		{"different ending", enFr, "Change the date", "Changez la data", []string{"date"}},
+		{"different stem", enFr, "Run the test", "Lancez le texte", []string{"test"}},
+		{"source not a stem", enFr, "Save the data", "Enregistrez les données", nil},
+		{"short words are exact", enFr, "Apply the settings", "Appliquez les réglages", nil},
+		{"cjk substring", enJa, "Sign in to the server", "サーバーにサインインしてください", nil},
+		{"cjk missing", enJa, "Restart the server", "サーバを再起動してください", []string{"server"}},
+		{"other source language", lang.NewPair(lang.French, lang.English), "server", "machine", nil},
+	}
+	for _, tt := range tests {
+		vs := c.Check(tt.pair, tt.src, tt.tgt)
+		var missing []string
+		for _, v := range vs {
+			missing = append(missing, v.Source)
+		}
+		if len(missing) != len(tt.missing) {
+			t.Errorf("%s: Check() = %v, want %v", tt.name, missing, tt.missing)
+			continue
+		}
+		for i := range missing {
+			if missing[i] != tt.missing[i] {
+				t.Errorf("%s: Check() = %v, want %v", tt.name, missing, tt.missing)
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package glossary
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/trinchan/gengo/lang"
+)
+
+// commentColumns are the header names recognized as the comment column of a glossary CSV.
+var commentColumns = map[string]bool{
+	"comment":  true,
+	"comments": true,
+	"note":     true,
+	"notes":    true,
+}
+
+// ReadCSV reads a glossary in Gengo's CSV format: a header row of language codes,
+// the first of which is the source language, optionally followed by a comment column,
+// and one row per term. Language codes may also be BCP 47 tags such as en-US.
+func ReadCSV(r io.Reader) (*Glossary, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("glossary csv has no header row")
+		}
+		return nil, err
+	}
+	codes := make([]lang.Code, len(header))
+	comment := -1
+	for i, h := range header {
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+		if commentColumns[strings.ToLower(h)] {
+			comment = i
+			continue
+		}
+		if h == "" {
+			return nil, fmt.Errorf("glossary csv column %d has no language code", i+1)
+		}
+		code, err := lang.Parse(h)
+		if err != nil {
+			return nil, fmt.Errorf("glossary csv column %d: %v", i+1, err)
+		}
+		codes[i] = code
+	}
+	if comment == 0 || len(header) < 2 {
+		return nil, fmt.Errorf("glossary csv header must start with the source language followed by target languages")
+	}
+	g := New(codes[0])
+	for i := 1; i < len(codes); i++ {
+		if codes[i] != "" {
+			g.Targets = append(g.Targets, codes[i])
+		}
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) > len(header) {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("glossary csv line %d has %d columns, the header has %d", line, len(record), len(header))
+		}
+		t := Term{Translations: map[lang.Code]string{}}
+		for i, field := range record {
+			field = strings.TrimSpace(field)
+			switch {
+			case i == 0:
+				t.Source = field
+			case i == comment:
+				t.Comment = field
+			case field != "":
+				t.Translations[codes[i]] = field
+			}
+		}
+		if t.Source == "" && len(t.Translations) == 0 && t.Comment == "" {
+			continue
+		}
+		g.Terms = append(g.Terms, t)
+	}
+	return g, nil
+}
+
+// WriteCSV writes the glossary in Gengo's CSV format.
+func (g *Glossary) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{string(g.Source)}
+	for _, code := range g.Targets {
+		header = append(header, string(code))
+	}
+	header = append(header, "comment")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, t := range g.Terms {
+		record := []string{t.Source}
+		for _, code := range g.Targets {
+			record = append(record, t.Translations[code])
+		}
+		record = append(record, t.Comment)
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
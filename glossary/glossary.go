@@ -0,0 +1,183 @@
+// Package glossary reads and writes terminology in Gengo CSV and TBX formats
+// so that it can be validated and uploaded as a Gengo glossary.
+package glossary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+)
+
+// Term is a source term and its translations, keyed by language code.
+type Term struct {
+	Source       string
+	Translations map[lang.Code]string
+	Comment      string
+}
+
+// Glossary is a list of terms in a source language and their translations into target languages.
+type Glossary struct {
+	Source  lang.Code
+	Targets []lang.Code
+	Terms   []Term
+}
+
+// New creates an empty glossary translating source into targets.
+func New(source lang.Code, targets ...lang.Code) *Glossary {
+	return &Glossary{Source: source, Targets: targets}
+}
+
+// Add adds a term, adding any new target languages it is translated into.
+func (g *Glossary) Add(t Term) {
+	for _, code := range sortedCodes(t.Translations) {
+		if !g.hasTarget(code) {
+			g.Targets = append(g.Targets, code)
+		}
+	}
+	g.Terms = append(g.Terms, t)
+}
+
+func (g *Glossary) hasTarget(code lang.Code) bool {
+	for _, t := range g.Targets {
+		if t == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the term with the given source text, compared case insensitively.
+func (g *Glossary) Lookup(source string) (Term, bool) {
+	key := normalize(source)
+	for _, t := range g.Terms {
+		if normalize(t.Source) == key {
+			return t, true
+		}
+	}
+	return Term{}, false
+}
+
+// Problem describes an issue with a term found by Validate.
+type Problem struct {
+	// Index is the position of the term in Terms.
+	Index  int
+	Source string
+	Code   lang.Code
+	Reason string
+}
+
+func (p Problem) String() string {
+	if p.Code != "" {
+		return fmt.Sprintf("term %d %q (%s): %s", p.Index+1, p.Source, p.Code, p.Reason)
+	}
+	return fmt.Sprintf("term %d %q: %s", p.Index+1, p.Source, p.Reason)
+}
+
+// ValidationError lists every problem found in a glossary.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = p.String()
+	}
+	return fmt.Sprintf("glossary has %d problem(s):\n%s", len(e.Problems), strings.Join(lines, "\n"))
+}
+
+// Validate checks for empty terms, languages outside the glossary and source terms listed more than once.
+// A repeated source term is a duplicate if its translations agree and a conflict if they differ.
+// It returns a *ValidationError listing every problem found.
+func (g *Glossary) Validate() error {
+	var problems []Problem
+	seen := map[string]int{}
+	for i, t := range g.Terms {
+		if strings.TrimSpace(t.Source) == "" {
+			problems = append(problems, Problem{Index: i, Reason: "source term is empty"})
+			continue
+		}
+		for _, code := range sortedCodes(t.Translations) {
+			if !g.hasTarget(code) {
+				problems = append(problems, Problem{Index: i, Source: t.Source, Code: code, Reason: "language is not a glossary target"})
+			}
+		}
+		key := normalize(t.Source)
+		first, ok := seen[key]
+		if !ok {
+			seen[key] = i
+			continue
+		}
+		conflict := false
+		for _, code := range sortedCodes(t.Translations) {
+			prev, ok := g.Terms[first].Translations[code]
+			if ok && normalize(prev) != normalize(t.Translations[code]) {
+				conflict = true
+				problems = append(problems, Problem{Index: i, Source: t.Source, Code: code,
+					Reason: fmt.Sprintf("conflicts with term %d: %q vs %q", first+1, prev, t.Translations[code])})
+			}
+		}
+		if !conflict {
+			problems = append(problems, Problem{Index: i, Source: t.Source, Reason: fmt.Sprintf("duplicates term %d", first+1)})
+		}
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// GengoTerms converts the terms for use with the Gengo glossary API.
+func (g *Glossary) GengoTerms() []gengo.GlossaryTerm {
+	terms := make([]gengo.GlossaryTerm, 0, len(g.Terms))
+	for _, t := range g.Terms {
+		gt := gengo.GlossaryTerm{Source: t.Source, Comment: t.Comment}
+		for _, code := range g.Targets {
+			if tr, ok := t.Translations[code]; ok {
+				gt.Translations = append(gt.Translations, gengo.GlossaryTranslation{Code: code, Term: tr})
+			}
+		}
+		terms = append(terms, gt)
+	}
+	return terms
+}
+
+// CreateRequest builds a request to create the glossary on Gengo. The glossary is validated first.
+func (g *Glossary) CreateRequest(title string, options ...gengo.GlossaryOption) (*gengo.CreateGlossaryRequest, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	return gengo.NewCreateGlossaryRequest(title, g.Source, g.Targets, g.GengoTerms(), options...), nil
+}
+
+// FromGengo builds a glossary from a Gengo glossary and its terms.
+func FromGengo(gl gengo.Glossary, terms []gengo.GlossaryTerm) *Glossary {
+	g := New(gl.SourceCode)
+	for _, t := range gl.Targets {
+		g.Targets = append(g.Targets, t.Code)
+	}
+	for _, gt := range terms {
+		t := Term{Source: gt.Source, Comment: gt.Comment, Translations: map[lang.Code]string{}}
+		for _, tr := range gt.Translations {
+			t.Translations[tr.Code] = tr.Term
+		}
+		g.Add(t)
+	}
+	return g
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func sortedCodes(m map[lang.Code]string) []lang.Code {
+	codes := make([]lang.Code, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
@@ -0,0 +1,136 @@
+package glossary
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/trinchan/gengo/lang"
+)
+
+func readTestCSV(t *testing.T) *Glossary {
+	t.Helper()
+	f, err := os.Open("testdata/ui.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := ReadCSV(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestReadCSV(t *testing.T) {
+	g := readTestCSV(t)
+	if g.Source != lang.English || !reflect.DeepEqual(g.Targets, []lang.Code{lang.Japanese, lang.French}) {
+		t.Fatalf("unexpected languages %s -> %v", g.Source, g.Targets)
+	}
+	if len(g.Terms) != 3 {
+		t.Fatalf("expected 3 terms, got %d", len(g.Terms))
+	}
+	want := Term{Source: "dashboard", Comment: "Main screen", Translations: map[lang.Code]string{lang.Japanese: "ダッシュボード", lang.French: "tableau de bord"}}
+	if !reflect.DeepEqual(g.Terms[0], want) {
+		t.Errorf("first term = %+v, want %+v", g.Terms[0], want)
+	}
+	if _, ok := g.Terms[2].Translations[lang.French]; ok {
+		t.Errorf("empty cells should not be translations: %+v", g.Terms[2])
+	}
+	if err := g.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	g := readTestCSV(t)
+	buf := new(bytes.Buffer)
+	if err := g.WriteCSV(buf); err != nil {
+		t.Fatal(err)
+	}
+	back, err := ReadCSV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, g) {
+		t.Errorf("round trip = %+v, want %+v", back, g)
+	}
+}
+
+func TestReadTBX(t *testing.T) {
+	f, err := os.Open("testdata/ui.tbx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := ReadTBX(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Source != lang.English || len(g.Terms) != 2 {
+		t.Fatalf("unexpected glossary %+v", g)
+	}
+	want := readTestCSV(t).Terms[0]
+	if !reflect.DeepEqual(g.Terms[0], want) {
+		t.Errorf("first term = %+v, want %+v", g.Terms[0], want)
+	}
+	if g.Terms[1].Source != "server" {
+		t.Errorf("only the first synonym should be kept, got %q", g.Terms[1].Source)
+	}
+}
+
+func TestTBXRoundTrip(t *testing.T) {
+	g := readTestCSV(t)
+	buf := new(bytes.Buffer)
+	if err := g.WriteTBX(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `<langSet xml:lang="ja">`) {
+		t.Fatalf("unexpected tbx:\n%s", buf)
+	}
+	back, err := ReadTBX(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, g) {
+		t.Errorf("round trip = %+v, want %+v", back, g)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	g := New(lang.English, lang.Japanese)
+	g.Add(Term{Source: "Server", Translations: map[lang.Code]string{lang.Japanese: "サーバー"}})
+	g.Add(Term{Source: "server", Translations: map[lang.Code]string{lang.Japanese: "サーバー"}})
+	g.Add(Term{Source: "SERVER", Translations: map[lang.Code]string{lang.Japanese: "サーバ"}})
+	g.Add(Term{Source: " "})
+	err := g.Validate()
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	if len(ve.Problems) != 3 {
+		t.Fatalf("expected 3 problems, got %v", ve)
+	}
+	for i, want := range []string{"duplicates term 1", "conflicts with term 1", "source term is empty"} {
+		if !strings.Contains(ve.Problems[i].Reason, want) {
+			t.Errorf("problem %d = %q, want %q", i, ve.Problems[i].Reason, want)
+		}
+	}
+	if _, err := g.CreateRequest("UI"); err == nil {
+		t.Error("CreateRequest() should refuse an invalid glossary")
+	}
+}
+
+func TestCreateRequest(t *testing.T) {
+	g := readTestCSV(t)
+	req, err := g.CreateRequest("UI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *req.Title != "UI" || req.SourceCode != lang.English || len(req.Terms) != 3 || len(req.Terms[2].Translations) != 1 {
+		t.Errorf("unexpected request %+v", req)
+	}
+}
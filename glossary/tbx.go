@@ -0,0 +1,171 @@
+package glossary
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/trinchan/gengo/lang"
+)
+
+// tbxDocument decodes both TBX 2 (martif, ISO 30042:2008) and TBX 3 (tbx, ISO 30042:2019) documents.
+type tbxDocument struct {
+	Lang     string     `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Entries  []tbxEntry `xml:"text>body>termEntry"`
+	Concepts []tbxEntry `xml:"text>body>conceptEntry"`
+}
+
+type tbxEntry struct {
+	Notes    []string     `xml:"note"`
+	Descrips []string     `xml:"descrip"`
+	LangSets []tbxLangSet `xml:"langSet"`
+	LangSecs []tbxLangSet `xml:"langSec"`
+	Groups   []tbxDescGrp `xml:"descripGrp"`
+}
+
+type tbxDescGrp struct {
+	Descrip string `xml:"descrip"`
+}
+
+type tbxLangSet struct {
+	Lang     string   `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Tigs     []string `xml:"tig>term"`
+	Ntigs    []string `xml:"ntig>termGrp>term"`
+	TermSecs []string `xml:"termSec>term"`
+}
+
+func (ls tbxLangSet) term() string {
+	for _, terms := range [][]string{ls.Tigs, ls.Ntigs, ls.TermSecs} {
+		for _, t := range terms {
+			if t = strings.TrimSpace(t); t != "" {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+func (e tbxEntry) comment() string {
+	for _, s := range append(append(e.Notes, e.Descrips...), groupDescrips(e.Groups)...) {
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func groupDescrips(groups []tbxDescGrp) []string {
+	ds := make([]string, len(groups))
+	for i, g := range groups {
+		ds[i] = g.Descrip
+	}
+	return ds
+}
+
+// ReadTBX reads a glossary from a TBX (ISO 30042) document.
+// The source language is taken from the document's xml:lang attribute,
+// or from the first language of the first entry if the document has none.
+// Language tags are mapped to the closest Gengo language, so en-US is read as English.
+// Only the first term of each language in an entry is kept.
+func ReadTBX(r io.Reader) (*Glossary, error) {
+	doc := new(tbxDocument)
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("reading tbx: %v", err)
+	}
+	entries := append(doc.Entries, doc.Concepts...)
+	sourceLang := doc.Lang
+	if sourceLang == "" {
+		for _, e := range entries {
+			if sets := append(e.LangSets, e.LangSecs...); len(sets) > 0 {
+				sourceLang = sets[0].Lang
+				break
+			}
+		}
+	}
+	if sourceLang == "" {
+		return nil, fmt.Errorf("reading tbx: cannot determine the source language")
+	}
+	source, err := lang.Parse(sourceLang)
+	if err != nil {
+		return nil, fmt.Errorf("reading tbx: %v", err)
+	}
+	g := New(source)
+	for _, e := range entries {
+		t := Term{Comment: e.comment(), Translations: map[lang.Code]string{}}
+		for _, ls := range append(e.LangSets, e.LangSecs...) {
+			code, err := lang.Parse(ls.Lang)
+			if err != nil {
+				return nil, fmt.Errorf("reading tbx: %v", err)
+			}
+			term := ls.term()
+			switch {
+			case term == "":
+			case code == source:
+				if t.Source == "" {
+					t.Source = term
+				}
+			default:
+				if !g.hasTarget(code) {
+					g.Targets = append(g.Targets, code)
+				}
+				if _, ok := t.Translations[code]; !ok {
+					t.Translations[code] = term
+				}
+			}
+		}
+		g.Add(t)
+	}
+	return g, nil
+}
+
+type martif struct {
+	XMLName xml.Name     `xml:"martif"`
+	Type    string       `xml:"type,attr"`
+	Lang    string       `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Source  string       `xml:"martifHeader>fileDesc>sourceDesc>p"`
+	Entries []martifTerm `xml:"text>body>termEntry"`
+}
+
+type martifTerm struct {
+	ID       string          `xml:"id,attr"`
+	Note     string          `xml:"note,omitempty"`
+	LangSets []martifLangSet `xml:"langSet"`
+}
+
+type martifLangSet struct {
+	Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Term string `xml:"tig>term"`
+}
+
+// WriteTBX writes the glossary as a TBX-Basic document.
+func (g *Glossary) WriteTBX(w io.Writer) error {
+	doc := martif{
+		Type:   "TBX-Basic",
+		Lang:   string(g.Source),
+		Source: "Exported by github.com/trinchan/gengo/glossary",
+	}
+	for i, t := range g.Terms {
+		entry := martifTerm{
+			ID:       fmt.Sprintf("t%d", i+1),
+			Note:     t.Comment,
+			LangSets: []martifLangSet{{Lang: string(g.Source), Term: t.Source}},
+		}
+		for _, code := range g.Targets {
+			if tr, ok := t.Translations[code]; ok {
+				entry.LangSets = append(entry.LangSets, martifLangSet{Lang: string(code), Term: tr})
+			}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,99 @@
+package gengo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/trinchan/gengo/lang"
+)
+
+const testGlossary = `{"id":9,"title":"UI","source_language_code":"en","target_languages":[[2,"ja"]],"is_public":false}`
+
+func TestCreateGlossary(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("POST /translate/glossary", testGlossary)
+	terms := []GlossaryTerm{{Source: "dashboard", Translations: []GlossaryTranslation{{Code: lang.Japanese, Term: "ダッシュボード"}}}}
+	r, err := c.CreateGlossary(NewCreateGlossaryRequest("UI", lang.English, []lang.Code{lang.Japanese}, terms, WithGlossaryPublic(false)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Glossary.ID != 9 || r.Glossary.Targets[0].Code != lang.Japanese {
+		t.Errorf("unexpected glossary %+v", r.Glossary)
+	}
+	var sent CreateGlossaryRequest
+	api.Data(t, 0, &sent)
+	if *sent.Title != "UI" || sent.Public == nil || bool(*sent.Public) || sent.Terms[0].Translations[0].Term != "ダッシュボード" {
+		t.Errorf("unexpected create request %+v", sent)
+	}
+}
+
+func TestUpdateGlossary(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("PUT /translate/glossary/9", testGlossary)
+	if _, err := c.UpdateGlossary(NewUpdateGlossaryRequest(9, WithGlossaryTitle("UI"))); err != nil {
+		t.Fatal(err)
+	}
+	var sent map[string]interface{}
+	api.Data(t, 0, &sent)
+	if len(sent) != 1 || sent["title"] != "UI" {
+		t.Errorf("update should only send the changed title, sent %v", sent)
+	}
+}
+
+func TestUploadGlossary(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("POST /translate/glossary/9/file", testGlossary)
+	path := filepath.Join(t.TempDir(), "ui.csv")
+	if err := os.WriteFile(path, []byte("en,ja\ndashboard,ダッシュボード\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UploadGlossary(NewReplaceGlossaryFileRequest(9, path)); err != nil {
+		t.Fatal(err)
+	}
+	if api.Form(0).Get("api_key") != "public" {
+		t.Errorf("upload was not signed: %v", api.Form(0))
+	}
+}
+
+func TestDeleteGlossary(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("DELETE /translate/glossary/9", `{}`)
+	if err := c.DeleteGlossary(NewDeleteGlossaryRequest(9)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlossaryTerms(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("GET /translate/glossary/9/terms", `[{"id":1,"source":"dashboard","translations":[{"lc":"ja","term":"ダッシュボード"}]}]`)
+	api.HandleRaw("GET /translate/glossary/9/download", "en,ja\ndashboard,ダッシュボード\n")
+	r, err := c.GlossaryTerms(NewGlossaryTermsRequest(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Terms) != 1 || r.Terms[0].Source != "dashboard" {
+		t.Errorf("unexpected terms %+v", r.Terms)
+	}
+	buf := new(bytes.Buffer)
+	if err := c.DownloadGlossary(NewGlossaryTermsRequest(9), buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "en,ja\ndashboard,ダッシュボード\n" {
+		t.Errorf("downloaded %q", buf)
+	}
+	if err := c.DownloadGlossary(NewGlossaryTermsRequest(10), buf); err == nil {
+		t.Error("expected an error downloading a missing glossary")
+	}
+
+	api.HandleRedirect("GET /translate/glossary/11/download", "https://files.example/glossary-11.csv")
+	api.HandleRaw("GET /glossary-11.csv", "en,fr\n")
+	buf.Reset()
+	if err := c.DownloadGlossary(NewGlossaryTermsRequest(11), buf); err != nil || buf.String() != "en,fr\n" {
+		t.Errorf("DownloadGlossary() of a redirected file = %q, %v", buf, err)
+	}
+	if q := api.Requests()[api.Len()-2].URL.Query(); q.Get("api_key") != "public" || q.Get("api_sig") == "" {
+		t.Errorf("glossary download was not signed: %s", q.Encode())
+	}
+}
@@ -0,0 +1,215 @@
+// Package gengotest provides a fake Gengo API for the tests of the gengo packages.
+package gengotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// API is a RoundTripper answering requests by "METHOD /path", without the /v2 prefix of the API, such as
+// "GET /translate/order/1". It wraps the JSON responses of Routes and Funcs in the envelope of Gengo responses and
+// returns those of Raw as is, such as file downloads. Redirects answer routes with a 302 to their URL. Unknown API routes get a Gengo error response, and other
+// unknown URLs a 404. It records every request and is safe for concurrent use.
+type API struct {
+	Routes map[string]string
+	Raw    map[string]string
+	// Funcs answer the routes whose response depends on the request, such as on its query.
+	Funcs     map[string]func(req *http.Request) string
+	Redirects map[string]string
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+// New creates an API answering the routes given.
+func New(routes map[string]string) *API {
+	if routes == nil {
+		routes = map[string]string{}
+	}
+	return &API{Routes: routes, Raw: map[string]string{}, Funcs: map[string]func(*http.Request) string{}, Redirects: map[string]string{}}
+}
+
+// Handle registers the JSON response of a route, e.g. Handle("GET /translate/order/1", `{...}`).
+func (a *API) Handle(route, response string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Routes[route] = response
+}
+
+// HandleRaw registers a non-JSON response of a route, such as a file download.
+func (a *API) HandleRaw(route, body string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Raw[route] = body
+}
+
+// HandleFunc registers a function returning the JSON response of a route.
+func (a *API) HandleFunc(route string, f func(req *http.Request) string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Funcs[route] = f
+}
+
+// HandleRedirect redirects a route to a URL, as file downloads may be.
+func (a *API) HandleRedirect(route, location string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Redirects[route] = location
+}
+
+// RoundTrip implements http.RoundTripper.
+func (a *API) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		body = string(b)
+	}
+	route := req.Method + " " + strings.TrimPrefix(req.URL.Path, "/v2")
+	a.mu.Lock()
+	a.requests = append(a.requests, req)
+	a.bodies = append(a.bodies, body)
+	raw, isRaw := a.Raw[route]
+	resp, ok := a.Routes[route]
+	f := a.Funcs[route]
+	location, isRedirect := a.Redirects[route]
+	a.mu.Unlock()
+
+	switch {
+	case isRedirect:
+		re := response(req, http.StatusFound, "text/plain", "")
+		re.Header.Set("Location", location)
+		return re, nil
+	case isRaw:
+		return response(req, http.StatusOK, "application/octet-stream", raw), nil
+	case f != nil:
+		resp, ok = f(req), true
+	case !ok && !strings.HasPrefix(req.URL.Path, "/v2"):
+		return response(req, http.StatusNotFound, "text/plain", ""), nil
+	}
+	if ok {
+		resp = `{"opstat":"ok","response":` + resp + `}`
+	} else {
+		msg, _ := json.Marshal("no fake response for " + route)
+		resp = `{"opstat":"error","err":{"code":404,"msg":` + string(msg) + `}}`
+	}
+	return response(req, http.StatusOK, "application/json", resp), nil
+}
+
+func response(req *http.Request, code int, contentType, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Header:     http.Header{"Content-Type": []string{contentType}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// Requests returns the requests made so far.
+func (a *API) Requests() []*http.Request {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]*http.Request(nil), a.requests...)
+}
+
+// Len returns the number of requests made so far.
+func (a *API) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.requests)
+}
+
+// Body returns the body of the i-th request.
+func (a *API) Body(i int) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.bodies[i]
+}
+
+// Form returns the form parameters sent in the body of the i-th request, which is URL-encoded or a multipart form.
+func (a *API) Form(i int) url.Values {
+	a.mu.Lock()
+	req, body := a.requests[i], a.bodies[i]
+	a.mu.Unlock()
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") {
+		v, _ := url.ParseQuery(body)
+		return v
+	}
+	mr, err := (&http.Request{Header: req.Header, Body: io.NopCloser(strings.NewReader(body))}).MultipartReader()
+	if err != nil {
+		return nil
+	}
+	form, err := mr.ReadForm(1 << 20)
+	if err != nil {
+		return nil
+	}
+	return url.Values(form.Value)
+}
+
+// Data decodes the JSON "data" parameter of the i-th request into v.
+func (a *API) Data(t testing.TB, i int, v interface{}) {
+	t.Helper()
+	if n := a.Len(); i >= n {
+		t.Fatalf("request %d was never made (%d requests)", i, n)
+	}
+	if err := json.Unmarshal([]byte(a.Form(i).Get("data")), v); err != nil {
+		t.Fatalf("decoding data of request %d: %v", i, err)
+	}
+}
+
+// Job is a job of a history listed by Jobs.
+type Job struct {
+	ID     int
+	Ctime  int64
+	Status string
+}
+
+// Jobs returns a handler for GET /translate/jobs which lists the jobs of a history as Gengo does: those with the
+// status and created after the timestamp_after of the request, newest first, count at a time (10 by default, 200
+// at most). With oldestFirst, it lists the oldest jobs after timestamp_after first instead.
+func Jobs(history []Job, oldestFirst bool) func(req *http.Request) string {
+	return func(req *http.Request) string {
+		q := req.URL.Query()
+		after, _ := strconv.ParseInt(q.Get("timestamp_after"), 10, 64)
+		count, err := strconv.Atoi(q.Get("count"))
+		if err != nil || count <= 0 {
+			count = 10
+		} else if count > 200 {
+			count = 200
+		}
+		var jobs []Job
+		for _, j := range history {
+			if j.Ctime > after && (q.Get("status") == "" || j.Status == q.Get("status")) {
+				jobs = append(jobs, j)
+			}
+		}
+		sort.SliceStable(jobs, func(a, b int) bool {
+			if oldestFirst {
+				return jobs[a].Ctime < jobs[b].Ctime
+			}
+			return jobs[a].Ctime > jobs[b].Ctime
+		})
+		if len(jobs) > count {
+			jobs = jobs[:count]
+		}
+		out := make([]string, len(jobs))
+		for i, j := range jobs {
+			out[i] = fmt.Sprintf(`{"job_id":"%d","ctime":%d}`, j.ID, j.Ctime)
+		}
+		return "[" + strings.Join(out, ",") + "]"
+	}
+}
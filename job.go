@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/trinchan/gengo/lang"
 )
@@ -22,6 +23,18 @@ const (
 	JobTypeFile = "file"
 )
 
+const (
+	JobStatusQueued     = "queued"
+	JobStatusAvailable  = "available"
+	JobStatusPending    = "pending"
+	JobStatusReviewable = "reviewable"
+	JobStatusRevising   = "revising"
+	JobStatusApproved   = "approved"
+	JobStatusRejected   = "rejected"
+	JobStatusCanceled   = "canceled"
+	JobStatusHeld       = "hold"
+)
+
 type Attachment struct {
 	URL      string `json:"url"`
 	Name     string `json:"filename"`
@@ -188,6 +201,7 @@ type PostJobResponse struct {
 	AutoApprove        Bool    `json:"auto_approve"`
 	Ctime              Time    `json:"ctime"`
 	CustomData         string  `json:"custom_data"`
+	Slug               string  `json:"slug"`
 	MachineTranslation Bool    `json:"mt"`
 	FileSourceURL      string  `json:"file_url_src"`
 	FileTargetURL      string  `json:"file_url_tgt"`
@@ -207,6 +221,15 @@ func (jr *PostJobResponse) UnmarshalJSON(d []byte) error {
 
 type GetJobResponse PostJobResponse
 
+// TargetFile opens the translated file of a file job, from its FileTargetURL. The caller must close it.
+func (c *Client) TargetFile(job *GetJobResponse) (io.ReadCloser, error) {
+	if job.FileTargetURL == "" {
+		return nil, fmt.Errorf("job %d has no target file", job.ID)
+	}
+	body, _, err := c.open(job.FileTargetURL, false)
+	return body, err
+}
+
 type ReviseJobRequest struct {
 	ID      int     `json:"job_id"`
 	Comment *string `json:"comment,omitempty"`
@@ -0,0 +1,34 @@
+package gengo
+
+import (
+	"io"
+	"testing"
+)
+
+func TestTargetFile(t *testing.T) {
+	c, api := newFakeClient()
+	api.HandleRaw("GET /files/101.docx", "translated")
+	body, err := c.TargetFile(&GetJobResponse{ID: 101, FileTargetURL: SandboxBaseURL + "/files/101.docx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	b, err := io.ReadAll(body)
+	if err != nil || string(b) != "translated" {
+		t.Errorf("TargetFile() = %q, %v", b, err)
+	}
+
+	api.HandleRedirect("GET /files/103.docx", "https://files.example/103.docx")
+	api.HandleRaw("GET /103.docx", "moved")
+	body, err = c.TargetFile(&GetJobResponse{ID: 103, FileTargetURL: SandboxBaseURL + "/files/103.docx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if b, err := io.ReadAll(body); err != nil || string(b) != "moved" {
+		t.Errorf("TargetFile() of a redirected file = %q, %v", b, err)
+	}
+	if _, err := c.TargetFile(&GetJobResponse{ID: 102}); err == nil {
+		t.Error("expected an error for a job without a target file")
+	}
+}
@@ -5,13 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/trinchan/gengo/currency"
 )
 
 const (
 	jobsNamespace = "/translate/jobs"
+	// getJobsByIDBatchSize is the number of jobs GetJobsByID() retrieves per API call.
+	getJobsByIDBatchSize = 50
+	// MaxJobsPerPage is the most jobs GetJobs() lists per call.
+	MaxJobsPerPage = 200
 )
 
 type PostJobsRequest struct {
@@ -38,12 +45,32 @@ func NewPostJobsRequest(jobs []*JobRequest, options ...PostJobsRequestOption) *P
 type PostJobsResponse struct {
 	OrderID     int               `json:"order_id"`
 	Count       int               `json:"job_count"`
-	CreditsUsed Float64           `json:"credits_used"`
-	Currency    string            `json:"currency"`
+	CreditsUsed currency.Money    `json:"credits_used"`
+	Currency    currency.Code     `json:"currency"`
 	Jobs        []PostJobResponse `json:"jobs,omitempty"`
 }
 
+func (p *PostJobsResponse) UnmarshalJSON(d []byte) error {
+	type pjr PostJobsResponse
+	x := new(pjr)
+	if err := json.Unmarshal(d, x); err != nil {
+		return err
+	}
+	*p = PostJobsResponse(*x)
+	p.CreditsUsed = p.CreditsUsed.In(p.Currency)
+	return nil
+}
+
 func (c *Client) PostJobs(req *PostJobsRequest) (*PostJobsResponse, error) {
+	if c.budget != nil {
+		return c.budget.post(c, req, func() (*PostJobsResponse, error) {
+			return c.postJobs(req)
+		})
+	}
+	return c.postJobs(req)
+}
+
+func (c *Client) postJobs(req *PostJobsRequest) (*PostJobsResponse, error) {
 	b, err := json.Marshal(req)
 	if err != nil {
 		return nil, err
@@ -107,6 +134,93 @@ func (c *Client) GetJobs(req *GetJobsRequest) (*GetJobsResponse, error) {
 	return pjr, err
 }
 
+// IncompleteListError is returned by ListJobs() with the jobs it listed when it could not reach the others. GetJobs()
+// can only be narrowed to the jobs created after a time, so when it lists the newest jobs first, the jobs older than
+// a full page are out of reach.
+type IncompleteListError struct {
+	// Listed is the number of jobs listed.
+	Listed int
+	// Oldest is the creation time of the oldest job of the last page. Jobs created before it may be missing.
+	Oldest time.Time
+}
+
+func (e *IncompleteListError) Error() string {
+	return fmt.Sprintf("gengo: only %d jobs could be listed, jobs created before %s may be missing",
+		e.Listed, e.Oldest.UTC().Format(time.RFC3339))
+}
+
+// ListJobs lists every job of GetJobs(), oldest first, paging with timestamp_after. The count of the request is
+// the size of each page, MaxJobsPerPage by default. If the jobs left cannot be reached, it returns those listed
+// and an *IncompleteListError.
+func (c *Client) ListJobs(req *GetJobsRequest) (*GetJobsResponse, error) {
+	pageSize := MaxJobsPerPage
+	if n, err := strconv.Atoi(req.Options.Get("count")); err == nil && n > 0 {
+		pageSize = n
+	}
+	var since int64
+	if after := req.Options.Get("timestamp_after"); after != "" {
+		var err error
+		if since, err = strconv.ParseInt(after, 10, 64); err != nil {
+			return nil, fmt.Errorf("gengo: invalid timestamp_after %q", after)
+		}
+	}
+	list := new(GetJobsResponse)
+	seen := map[Int]bool{}
+	cursor := since
+	for {
+		options := url.Values{}
+		for k, v := range req.Options {
+			options[k] = v
+		}
+		options.Set("count", strconv.Itoa(pageSize))
+		if cursor > 0 {
+			options.Set("timestamp_after", strconv.FormatInt(cursor, 10))
+		}
+		page, err := c.GetJobs(&GetJobsRequest{Options: options})
+		if err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, j := range page.Jobs {
+			if seen[j.ID] || since > 0 && time.Time(j.Ctime).Unix() <= since {
+				continue
+			}
+			seen[j.ID] = true
+			list.Jobs = append(list.Jobs, j)
+			added++
+		}
+		if len(page.Jobs) < pageSize {
+			break
+		}
+		first, last := time.Time(page.Jobs[0].Ctime), time.Time(page.Jobs[len(page.Jobs)-1].Ctime)
+		// A page listing the newest jobs first cannot be followed by older ones, and a page of jobs all created in
+		// the same second as the cursor cannot be moved past.
+		if first.After(last) || added == 0 {
+			oldest := first
+			if last.Before(oldest) {
+				oldest = last
+			}
+			sortJobs(list.Jobs)
+			return list, &IncompleteListError{Listed: len(list.Jobs), Oldest: oldest}
+		}
+		// Jobs created in the same second as the last one of the page may be on the next page.
+		cursor = last.Unix() - 1
+	}
+	sortJobs(list.Jobs)
+	return list, nil
+}
+
+// sortJobs sorts jobs by creation time, then ID.
+func sortJobs(jobs []GetJobResponse) {
+	sort.SliceStable(jobs, func(a, b int) bool {
+		ta, tb := time.Time(jobs[a].Ctime), time.Time(jobs[b].Ctime)
+		if !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return jobs[a].ID < jobs[b].ID
+	})
+}
+
 type GetJobsByIDRequest struct {
 	IDs []int
 }
@@ -119,14 +233,26 @@ type GetJobsByIDResponse struct {
 	Jobs []GetJobResponse `json:"jobs,omitempty"`
 }
 
+// GetJobsByID retrieves jobs by ID, in calls of up to getJobsByIDBatchSize jobs so that long lists of IDs fit in
+// the URL. Retrieving no jobs makes no call.
 func (c *Client) GetJobsByID(req *GetJobsByIDRequest) (*GetJobsByIDResponse, error) {
-	strIDs := make([]string, len(req.IDs), len(req.IDs))
-	for i := range req.IDs {
-		strIDs[i] = strconv.Itoa(req.IDs[i])
-	}
 	pjr := new(GetJobsByIDResponse)
-	err := c.get(fmt.Sprintf("%s/%s", jobsNamespace, strings.Join(strIDs, ",")), nil, pjr)
-	return pjr, err
+	for start := 0; start < len(req.IDs); start += getJobsByIDBatchSize {
+		end := start + getJobsByIDBatchSize
+		if end > len(req.IDs) {
+			end = len(req.IDs)
+		}
+		strIDs := make([]string, 0, end-start)
+		for _, id := range req.IDs[start:end] {
+			strIDs = append(strIDs, strconv.Itoa(id))
+		}
+		batch := new(GetJobsByIDResponse)
+		if err := c.get(fmt.Sprintf("%s/%s", jobsNamespace, strings.Join(strIDs, ",")), nil, batch); err != nil {
+			return pjr, err
+		}
+		pjr.Jobs = append(pjr.Jobs, batch.Jobs...)
+	}
+	return pjr, nil
 }
 
 type ReviseJobsRequest struct {
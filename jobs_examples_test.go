@@ -3,16 +3,14 @@ package gengo
 import (
 	"fmt"
 
-	"golang.org/x/text/language"
-
 	"github.com/trinchan/gengo/lang"
 )
 
 func ExampleClient_PostJobs() {
 	g := NewFromEnv()
 	jobs := []*JobRequest{
-		NewJobRequest("Text to translate", lang.NewPair(language.English, language.Japanese), TierStandard),
-		NewJobRequest("翻訳するテキスト", lang.NewPair(language.Japanese, language.English), TierStandard),
+		NewJobRequest("Text to translate", lang.NewPair(lang.English, lang.Japanese), TierStandard),
+		NewJobRequest("翻訳するテキスト", lang.NewPair(lang.Japanese, lang.English), TierStandard),
 	}
 	req := NewPostJobsRequest(jobs)
 	r, err := g.PostJobs(req)
@@ -21,7 +19,7 @@ func ExampleClient_PostJobs() {
 	}
 	fmt.Printf("Order ID: %d\n", r.OrderID)
 	fmt.Printf("New jobs posted: %d\n", r.Count)
-	fmt.Printf("Credits used: %0.2f %s\n", r.CreditsUsed, r.Currency)
+	fmt.Printf("Credits used: %s\n", r.CreditsUsed)
 	for _, job := range r.Jobs {
 		fmt.Printf("Duplicate job: %d\n", job.ID)
 	}
@@ -0,0 +1,70 @@
+package gengo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo/internal/gengotest"
+)
+
+// jobHistory returns n approved jobs created an hour apart from 2024-02-01, two of them per hour from the 10th.
+func jobHistory(n int) []gengotest.Job {
+	start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC).Unix()
+	history := make([]gengotest.Job, n)
+	for i := range history {
+		hour := i
+		if i >= 10 {
+			hour = 10 + (i-10)/2
+		}
+		history[i] = gengotest.Job{ID: i + 1, Ctime: start + int64(hour)*3600, Status: JobStatusApproved}
+	}
+	return history
+}
+
+func TestListJobs(t *testing.T) {
+	c, api := newFakeClient()
+	api.HandleFunc("GET /translate/jobs", gengotest.Jobs(jobHistory(25), true))
+	r, err := c.ListJobs(NewGetJobsRequest(WithStatus(JobStatusApproved), WithCount(4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Jobs) != 25 {
+		t.Fatalf("ListJobs() listed %d jobs, want 25", len(r.Jobs))
+	}
+	for i, j := range r.Jobs {
+		if int(j.ID) != i+1 {
+			t.Fatalf("job %d is %d, want the jobs oldest first", i, j.ID)
+		}
+	}
+	for _, req := range api.Requests() {
+		if q := req.URL.Query(); q.Get("count") != "4" || q.Get("status") != JobStatusApproved {
+			t.Errorf("unexpected query %s", req.URL.RawQuery)
+		}
+	}
+
+	since := time.Date(2024, 2, 1, 15, 0, 0, 0, time.UTC)
+	r, err = c.ListJobs(NewGetJobsRequest(WithTimestampAfter(Time(since)), WithCount(4)))
+	if err != nil || len(r.Jobs) != 3 || r.Jobs[0].ID != 23 {
+		t.Errorf("ListJobs() after %s = %+v, %v", since, r, err)
+	}
+}
+
+func TestListJobsNewestFirst(t *testing.T) {
+	c, api := newFakeClient()
+	api.HandleFunc("GET /translate/jobs", gengotest.Jobs(jobHistory(25), false))
+	r, err := c.ListJobs(NewGetJobsRequest())
+	if err != nil || len(r.Jobs) != 25 || api.Len() != 1 {
+		t.Fatalf("ListJobs() of a history shorter than a page = %d jobs, %v after %d requests", len(r.Jobs), err, api.Len())
+	}
+
+	// The jobs older than the newest page cannot be listed.
+	r, err = c.ListJobs(NewGetJobsRequest(WithCount(10)))
+	var incomplete *IncompleteListError
+	if !errors.As(err, &incomplete) || incomplete.Listed != 10 || len(r.Jobs) != 10 || r.Jobs[9].ID != 25 {
+		t.Fatalf("ListJobs() = %+v, %v", r, err)
+	}
+	if want := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC); !incomplete.Oldest.Equal(want) {
+		t.Errorf("Oldest = %s, want %s", incomplete.Oldest, want)
+	}
+}
@@ -0,0 +1,154 @@
+// Package l10n keeps the per-locale resource files of an application translated with Gengo. It submits the new and
+// changed strings of the source locale as jobs and writes approved translations back into the target files.
+package l10n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/l10n/resource"
+	"github.com/trinchan/gengo/lang"
+)
+
+// LocalePlaceholder is replaced by the locale in the paths of resource files.
+const LocalePlaceholder = "{locale}"
+
+// DefaultStateFile is the name of the state file, next to the project file, unless configured.
+const DefaultStateFile = ".gengo-l10n-state.json"
+
+// Config defines a localization project, usually read from a JSON project file.
+type Config struct {
+	// SourceLocale is the locale strings are written in, such as "en".
+	SourceLocale string `json:"source_locale"`
+	// Locales are the locales translated into, such as "ja" or "pt-BR". Gengo languages are found from them.
+	Locales []string `json:"locales"`
+	Files   []File   `json:"files"`
+	// Tier is the tier of the jobs, standard unless set.
+	Tier       gengo.Tier `json:"tier,omitempty"`
+	GlossaryID int        `json:"glossary_id,omitempty"`
+	// Comment is added to every job, before the comment of its string.
+	Comment string `json:"comment,omitempty"`
+	// State is the path of the state file.
+	State string `json:"state,omitempty"`
+}
+
+// File defines a set of resource files, one per locale.
+type File struct {
+	// Source is the path of the source locale's file. It may contain LocalePlaceholder.
+	Source string `json:"source"`
+	// Target is the path of each target locale's file, containing LocalePlaceholder. The default is Source.
+	Target string `json:"target,omitempty"`
+	// Format is the resource format, guessed from the extension of Source unless set.
+	Format string `json:"format,omitempty"`
+}
+
+// SourcePath returns the path of the file of the source locale.
+func (f *File) SourcePath(sourceLocale string) string {
+	return strings.ReplaceAll(f.Source, LocalePlaceholder, sourceLocale)
+}
+
+// TargetPath returns the path of the file of a target locale.
+func (f *File) TargetPath(locale string) string {
+	target := f.Target
+	if target == "" {
+		target = f.Source
+	}
+	return strings.ReplaceAll(target, LocalePlaceholder, locale)
+}
+
+// Project is a localization project, with paths relative to Dir.
+type Project struct {
+	Config
+	Dir string
+	// languages maps each locale to its Gengo language.
+	languages map[string]lang.Code
+	codecs    []resource.Codec
+}
+
+// LoadProject reads a project file. Paths in it are relative to the file's directory.
+func LoadProject(path string) (*Project, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("reading project %s: %v", path, err)
+	}
+	p, err := NewProject(c, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("project %s: %v", path, err)
+	}
+	return p, nil
+}
+
+// NewProject validates a config, looking up its languages and resource formats.
+func NewProject(c Config, dir string) (*Project, error) {
+	p := &Project{Config: c, Dir: dir, languages: map[string]lang.Code{}}
+	if p.Tier == "" {
+		p.Tier = gengo.TierStandard
+	}
+	if p.State == "" {
+		p.State = DefaultStateFile
+	}
+	if len(p.Locales) == 0 {
+		return nil, errors.New("no target locales")
+	}
+	if len(p.Files) == 0 {
+		return nil, errors.New("no files")
+	}
+	for _, locale := range append([]string{p.SourceLocale}, p.Locales...) {
+		code, err := lang.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf("locale %q: %v", locale, err)
+		}
+		p.languages[locale] = code
+	}
+	for i, f := range p.Files {
+		if f.Source == "" {
+			return nil, fmt.Errorf("file %d has no source", i+1)
+		}
+		if !strings.Contains(f.TargetPath(LocalePlaceholder), LocalePlaceholder) {
+			return nil, fmt.Errorf("the target of %s does not contain %s", f.Source, LocalePlaceholder)
+		}
+		var codec resource.Codec
+		var err error
+		if f.Format != "" {
+			codec, err = resource.Lookup(f.Format)
+		} else {
+			_, codec, err = resource.ForFile(f.Source)
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.codecs = append(p.codecs, codec)
+	}
+	return p, nil
+}
+
+// Language returns the Gengo language of a locale of the project.
+func (p *Project) Language(locale string) lang.Code {
+	return p.languages[locale]
+}
+
+// path returns a project path relative to the working directory.
+func (p *Project) path(rel string) string {
+	if filepath.IsAbs(rel) {
+		return rel
+	}
+	return filepath.Join(p.Dir, filepath.FromSlash(rel))
+}
+
+// read reads a file of the project, returning nil if it does not exist.
+func (p *Project) read(rel string) ([]byte, error) {
+	b, err := os.ReadFile(p.path(rel))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return b, err
+}
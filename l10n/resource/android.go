@@ -0,0 +1,254 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+func init() {
+	Register("android", Android{}, ".xml")
+}
+
+// androidSkeleton is the document translations are added to when the target file does not exist.
+const androidSkeleton = `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+</resources>
+`
+
+// Android is the codec of Android string resources, strings.xml. Strings are keyed by name, the items of plurals by
+// name and quantity, such as "songs[one]", and the items of string arrays by name and index, such as "planets[0]".
+// The comment right before an element is its comment, and a maxLength attribute sets its MaxChars. Resources with
+// translatable="false" are left out.
+type Android struct{}
+
+// androidResource is a translatable element of a strings.xml file and its units.
+type androidResource struct {
+	el *xmlElement
+	// items are the elements holding text by key: the element itself for strings.
+	items map[string]*xmlElement
+	keys  []string
+}
+
+func parseAndroid(data []byte) (*xmlElement, []androidResource, error) {
+	doc, err := parseXML(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := doc.root("resources")
+	if err != nil {
+		return nil, nil, err
+	}
+	var resources []androidResource
+	for _, el := range res.children {
+		name := el.attr("name")
+		if name == "" || el.attr("translatable") == "false" {
+			continue
+		}
+		r := androidResource{el: el, items: map[string]*xmlElement{}}
+		switch el.name {
+		case "string":
+			r.keys, r.items[name] = []string{name}, el
+		case "plurals", "string-array":
+			for i, item := range el.children {
+				if item.name != "item" {
+					continue
+				}
+				sub := item.attr("quantity")
+				if el.name == "string-array" {
+					sub = strconv.Itoa(i)
+				}
+				key := name + "[" + sub + "]"
+				r.keys, r.items[key] = append(r.keys, key), item
+			}
+		default:
+			continue
+		}
+		resources = append(resources, r)
+	}
+	return res, resources, nil
+}
+
+// Decode returns a unit per string, plural item and array item.
+func (c Android) Decode(data []byte) ([]Unit, error) {
+	return c.DecodeFor(data, "")
+}
+
+// DecodeFor returns a unit per string, plural item of the language of locale and array item. Each plural item
+// has the text of the source item of the same quantity, or else of "other".
+func (Android) DecodeFor(data []byte, locale string) ([]Unit, error) {
+	_, resources, err := parseAndroid(data)
+	if err != nil {
+		return nil, err
+	}
+	var units []Unit
+	for _, r := range resources {
+		if locale != "" {
+			r = r.localize(locale)
+		}
+		maxChars, _ := strconv.Atoi(r.el.attr("maxLength"))
+		for _, key := range r.keys {
+			item := r.items[key]
+			text, raw := item.content(data)
+			if raw {
+				text = xmlUnescapeText(text)
+			}
+			u := Unit{Key: key, Text: androidUnescape(text), Comment: r.el.comment, MaxChars: maxChars}
+			if r.el.name == "plurals" {
+				u.Comment = pluralComment(u.Comment, r.quantity(key))
+			}
+			units = append(units, u)
+		}
+	}
+	return units, nil
+}
+
+// Merge sets the translations in target. Resources missing from target are copied from source with their
+// untranslated items left out, and plurals get the quantities of the language of locale.
+func (Android) Merge(source, target []byte, locale string, translations map[string]string) ([]byte, error) {
+	_, srcResources, err := parseAndroid(source)
+	if err != nil {
+		return nil, fmt.Errorf("source: %v", err)
+	}
+	if target == nil {
+		target = []byte(androidSkeleton)
+	}
+	tgtRoot, tgtResources, err := parseAndroid(target)
+	if err != nil {
+		return nil, fmt.Errorf("target: %v", err)
+	}
+	existing := map[string]androidResource{}
+	for _, r := range tgtResources {
+		existing[r.el.attr("name")] = r
+	}
+	var edits []edit
+	for _, r := range srcResources {
+		tr, ok := existing[r.el.attr("name")]
+		if ok && tr.el.name != r.el.name {
+			return nil, fmt.Errorf("%s is a <%s> in source and a <%s> in target", r.el.attr("name"), r.el.name, tr.el.name)
+		}
+		plurals := r.el.name == "plurals"
+		if plurals {
+			r = r.localize(locale)
+		}
+		var copyEdits []edit
+		// items are the translated plural items, which are written anew as their quantities may not be in source.
+		var items []string
+		translated := false
+		for _, key := range r.keys {
+			t, ok := translations[key]
+			item := r.items[key]
+			if !ok {
+				if item != r.el && !plurals {
+					copyEdits = append(copyEdits, removeLines(source, item.start, item.end))
+				}
+				continue
+			}
+			translated = true
+			_, raw := item.content(source)
+			content := androidEscape(t, raw)
+			var itemXML string
+			if plurals {
+				itemXML = `<item quantity="` + r.quantity(key) + `">` + content + `</item>`
+				items = append(items, itemXML)
+			} else {
+				copyEdits = append(copyEdits, item.setContent(source, content))
+				itemXML = copyXML(source, item.start, item.end, []edit{item.setContent(source, content)})
+			}
+			if tr.el == nil {
+				continue
+			}
+			if ti := tr.items[key]; ti != nil {
+				edits = append(edits, ti.setContent(target, content))
+			} else {
+				edits = append(edits, appendChild(target, tr.el, itemXML, "    "))
+			}
+		}
+		if translated && tr.el == nil {
+			if plurals {
+				copyEdits = replaceChildren(source, r.el.find("item"), items)
+			}
+			edits = append(edits, appendChild(target, tgtRoot, copyXML(source, r.el.start, r.el.end, copyEdits), "    "))
+		}
+	}
+	return splice(target, 0, len(target), edits), nil
+}
+
+// localize returns the plurals of a resource for the language of locale: an item per plural category of the
+// language, taken from the source item of the same quantity, or else of "other".
+func (r androidResource) localize(locale string) androidResource {
+	if r.el.name != "plurals" || len(r.keys) == 0 {
+		return r
+	}
+	quantities := make([]string, len(r.keys))
+	for i, key := range r.keys {
+		quantities[i] = r.quantity(key)
+	}
+	l := androidResource{el: r.el, items: map[string]*xmlElement{}}
+	for _, c := range pluralCategories(locale) {
+		key := r.el.attr("name") + "[" + c + "]"
+		l.keys, l.items[key] = append(l.keys, key), r.items[r.keys[pluralSource(quantities, c)]]
+	}
+	return l
+}
+
+// quantity returns the quantity of the key of a plural item.
+func (r androidResource) quantity(key string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(key, r.el.attr("name")+"["), "]")
+}
+
+// androidUnescape returns the text of an Android string as displayed: without its enclosing double quotes and
+// backslash escapes.
+func androidUnescape(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if i+5 > len(s) {
+				b.WriteString(`\u`)
+				break
+			}
+			if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+				b.WriteRune(rune(r))
+				i += 4
+			} else {
+				b.WriteString(`\u`)
+			}
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// androidEscape returns a translation as Android string content. Markup, when raw, is kept as is, and the text
+// around it escaped.
+func androidEscape(s string, raw bool) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	text := func(t string) string {
+		return xmlEscape(r.Replace(t), false)
+	}
+	var escaped string
+	if raw {
+		escaped = mapText(s, text)
+	} else {
+		escaped = text(s)
+	}
+	if c, _ := utf8.DecodeRuneInString(escaped); c == '@' || c == '?' {
+		escaped = `\` + escaped
+	}
+	return escaped
+}
@@ -0,0 +1,161 @@
+package resource
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const enStringsXML = `<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="app_name" translatable="false">Acme</string>
+    <!-- Button of the login screen. -->
+    <string name="login" maxLength="12">Log in</string>
+    <string name="welcome">Don\'t forget <b>%1$s</b>!</string>
+    <string name="terms">Terms &amp; <i>conditions</i></string>
+    <plurals name="songs">
+        <item quantity="one">%d song</item>
+        <item quantity="other">%d songs</item>
+    </plurals>
+    <string-array name="planets">
+        <item>Mercury</item>
+        <item>Venus</item>
+    </string-array>
+</resources>
+`
+
+func TestAndroidDecode(t *testing.T) {
+	units, err := Android{}.Decode([]byte(enStringsXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "login", Text: "Log in", Comment: "Button of the login screen.", MaxChars: 12},
+		{Key: "welcome", Text: "Don't forget <b>%1$s</b>!"},
+		{Key: "terms", Text: "Terms & <i>conditions</i>"},
+		{Key: "songs[one]", Text: "%d song", Comment: `Plural form "one".`},
+		{Key: "songs[other]", Text: "%d songs", Comment: `Plural form "other".`},
+		{Key: "planets[0]", Text: "Mercury"},
+		{Key: "planets[1]", Text: "Venus"},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("Decode() = %+v\nwant %+v", units, want)
+	}
+}
+
+func TestAndroidMerge(t *testing.T) {
+	translations := map[string]string{
+		"login":        "Se connecter",
+		"welcome":      "N'oubliez pas <b>%1$s</b> !",
+		"terms":        "L'été & <i>conditions</i> < 3",
+		"songs[one]":   "%d chanson",
+		"songs[other]": "%d chansons",
+		"planets[1]":   "Vénus",
+	}
+	b, err := Android{}.Merge([]byte(enStringsXML), nil, "fr", translations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="login" maxLength="12">Se connecter</string>
+    <string name="welcome">N\'oubliez pas <b>%1$s</b> !</string>
+    <string name="terms">L\'été &amp; <i>conditions</i> &lt; 3</string>
+    <plurals name="songs">
+        <item quantity="one">%d chanson</item>
+        <item quantity="other">%d chansons</item>
+    </plurals>
+    <string-array name="planets">
+        <item>Vénus</item>
+    </string-array>
+</resources>
+`
+	if string(b) != want {
+		t.Errorf("Merge() =\n%s\nwant\n%s", b, want)
+	}
+	units, err := Android{}.Decode(b)
+	if err != nil {
+		t.Fatalf("Decode() of the merged file: %v", err)
+	}
+	// The strings decode to their translations. Array items are renumbered once the untranslated ones are left out.
+	for _, u := range units[:3] {
+		if u.Text != translations[u.Key] {
+			t.Errorf("Decode() of the merged %s = %q, want %q", u.Key, u.Text, translations[u.Key])
+		}
+	}
+
+	target := `<resources>
+    <!-- Kept. -->
+    <string name="login">Connexion</string>
+    <plurals name="songs">
+        <item quantity="other">%d chansons</item>
+    </plurals>
+</resources>`
+	b, err = Android{}.Merge([]byte(enStringsXML), []byte(target), "fr", map[string]string{"login": "Se connecter & go", "songs[one]": "%d chanson"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `<resources>
+    <!-- Kept. -->
+    <string name="login">Se connecter &amp; go</string>
+    <plurals name="songs">
+        <item quantity="other">%d chansons</item>
+        <item quantity="one">%d chanson</item>
+    </plurals>
+</resources>`
+	if string(b) != want {
+		t.Errorf("Merge() =\n%s\nwant\n%s", b, want)
+	}
+}
+
+func TestAndroidPlurals(t *testing.T) {
+	units, err := Android{}.DecodeFor([]byte(enStringsXML), "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "songs[one]", Text: "%d song", Comment: `Plural form "one".`},
+		{Key: "songs[few]", Text: "%d songs", Comment: `Plural form "few".`},
+		{Key: "songs[many]", Text: "%d songs", Comment: `Plural form "many".`},
+		{Key: "songs[other]", Text: "%d songs", Comment: `Plural form "other".`},
+	}
+	if !reflect.DeepEqual(units[3:7], want) {
+		t.Errorf("DecodeFor(ru) = %+v\nwant %+v", units[3:7], want)
+	}
+	if units, _ := (Android{}).DecodeFor([]byte(enStringsXML), "ja"); len(units) != 6 || units[3].Key != "songs[other]" {
+		t.Errorf("DecodeFor(ja) = %+v", units)
+	}
+
+	translations := map[string]string{"songs[one]": "%d песня", "songs[few]": "%d песни", "songs[many]": "%d песен", "songs[other]": "%d песни"}
+	b, err := Android{}.Merge([]byte(enStringsXML), nil, "ru", translations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantXML := `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <plurals name="songs">
+        <item quantity="one">%d песня</item>
+        <item quantity="few">%d песни</item>
+        <item quantity="many">%d песен</item>
+        <item quantity="other">%d песни</item>
+    </plurals>
+</resources>
+`
+	if string(b) != wantXML {
+		t.Errorf("Merge(ru) =\n%s\nwant\n%s", b, wantXML)
+	}
+
+	target := `<resources>
+    <plurals name="songs">
+        <item quantity="one">%d песня</item>
+        <item quantity="other">%d песни</item>
+    </plurals>
+</resources>`
+	b, err = Android{}.Merge([]byte(enStringsXML), []byte(target), "ru", map[string]string{"songs[many]": "%d песен"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "<item quantity=\"other\">%d песни</item>\n        <item quantity=\"many\">%d песен</item>\n    </plurals>") {
+		t.Errorf("Merge(ru) into a target =\n%s", b)
+	}
+}
@@ -0,0 +1,519 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+func init() {
+	Register("strings", AppleStrings{}, ".strings")
+	Register("stringsdict", AppleStringsDict{}, ".stringsdict")
+}
+
+// appleNoComment is the comment Xcode writes for strings without one.
+const appleNoComment = "No comment provided by engineer."
+
+// AppleStrings is the codec of iOS and macOS .strings files, in UTF-8 or UTF-16 with a byte order mark. Strings
+// are keyed by their key, and the comments right before them are their comment.
+type AppleStrings struct{}
+
+// stringsEntry is a "key" = "value"; entry of a .strings file.
+type stringsEntry struct {
+	key, value, comment string
+	// valueStart and valueEnd delimit the value as written, quotes included.
+	valueStart, valueEnd int
+}
+
+// Decode returns a unit per entry.
+func (AppleStrings) Decode(data []byte) ([]Unit, error) {
+	text, _ := decodeText(data)
+	entries, err := parseStrings(text)
+	if err != nil {
+		return nil, err
+	}
+	units := make([]Unit, len(entries))
+	for i, e := range entries {
+		units[i] = Unit{Key: e.key, Text: e.value, Comment: e.comment}
+		if e.comment == appleNoComment {
+			units[i].Comment = ""
+		}
+	}
+	return units, nil
+}
+
+// Merge sets the values of target entries, and adds the entries missing from target at its end with their
+// comments. New files have the encoding of source.
+func (AppleStrings) Merge(source, target []byte, _ string, translations map[string]string) ([]byte, error) {
+	srcText, enc := decodeText(source)
+	srcEntries, err := parseStrings(srcText)
+	if err != nil {
+		return nil, fmt.Errorf("source: %v", err)
+	}
+	text := ""
+	if target != nil {
+		text, enc = decodeText(target)
+	}
+	entries, err := parseStrings(text)
+	if err != nil {
+		return nil, fmt.Errorf("target: %v", err)
+	}
+	var edits []edit
+	existing := map[string]bool{}
+	for _, e := range entries {
+		existing[e.key] = true
+		if t, ok := translations[e.key]; ok {
+			edits = append(edits, edit{e.valueStart, e.valueEnd, stringsQuote(t)})
+		}
+	}
+	out := string(splice([]byte(text), 0, len(text), edits))
+	for _, e := range srcEntries {
+		t, ok := translations[e.key]
+		if existing[e.key] || !ok {
+			continue
+		}
+		if out != "" {
+			if !strings.HasSuffix(out, "\n") {
+				out += "\n"
+			}
+			out += "\n"
+		}
+		if e.comment != "" {
+			out += "/* " + e.comment + " */\n"
+		}
+		out += stringsQuote(e.key) + " = " + stringsQuote(t) + ";\n"
+	}
+	return encodeText(out, enc), nil
+}
+
+// parseStrings returns the entries of a .strings file.
+func parseStrings(s string) ([]stringsEntry, error) {
+	var entries []stringsEntry
+	var comments []string
+	i := 0
+	fail := func(format string, args ...interface{}) error {
+		return fmt.Errorf("line %d: %s", strings.Count(s[:i], "\n")+1, fmt.Sprintf(format, args...))
+	}
+	// skip skips whitespace and comments, collecting the comments.
+	skip := func() error {
+		for i < len(s) {
+			switch {
+			case strings.ContainsRune(" \t\r\n", rune(s[i])):
+				i++
+			case strings.HasPrefix(s[i:], "/*"):
+				end := strings.Index(s[i+2:], "*/")
+				if end < 0 {
+					return fail("unterminated comment")
+				}
+				comments = append(comments, strings.TrimSpace(s[i+2:i+2+end]))
+				i += end + 4
+			case strings.HasPrefix(s[i:], "//"):
+				end := strings.IndexByte(s[i:], '\n')
+				if end < 0 {
+					end = len(s) - i
+				}
+				comments = append(comments, strings.TrimSpace(s[i+2:i+end]))
+				i += end
+			default:
+				return nil
+			}
+		}
+		return nil
+	}
+	token := func() (string, error) {
+		if i >= len(s) {
+			return "", fail("unexpected end of file")
+		}
+		if s[i] != '"' {
+			start := i
+			for i < len(s) && (isBareStringChar(s[i])) {
+				i++
+			}
+			if i == start {
+				return "", fail("unexpected %q", s[i])
+			}
+			return s[start:i], nil
+		}
+		start := i
+		for i++; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' {
+				i++
+			}
+		}
+		if i >= len(s) {
+			return "", fail("unterminated string")
+		}
+		i++
+		return stringsUnquote(s[start+1 : i-1]), nil
+	}
+	expect := func(c byte) error {
+		if err := skip(); err != nil {
+			return err
+		}
+		if i >= len(s) || s[i] != c {
+			return fail("expected %q", c)
+		}
+		i++
+		return nil
+	}
+	for {
+		if err := skip(); err != nil {
+			return nil, err
+		}
+		if i >= len(s) {
+			return entries, nil
+		}
+		e := stringsEntry{comment: strings.Join(comments, "\n")}
+		comments = nil
+		var err error
+		if e.key, err = token(); err != nil {
+			return nil, err
+		}
+		if err := expect('='); err != nil {
+			return nil, err
+		}
+		if err := skip(); err != nil {
+			return nil, err
+		}
+		e.valueStart = i
+		if e.value, err = token(); err != nil {
+			return nil, err
+		}
+		e.valueEnd = i
+		if err := expect(';'); err != nil {
+			return nil, err
+		}
+		comments = nil
+		entries = append(entries, e)
+	}
+}
+
+func isBareStringChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_.$:/-", c) >= 0
+}
+
+func stringsUnquote(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'U', 'u':
+			if i+5 <= len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					b.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+var stringsEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func stringsQuote(s string) string {
+	return `"` + stringsEscaper.Replace(s) + `"`
+}
+
+// textEncoding is the encoding of a text file.
+type textEncoding int
+
+const (
+	encodingUTF8 textEncoding = iota
+	encodingUTF8BOM
+	encodingUTF16LE
+	encodingUTF16BE
+)
+
+// decodeText returns a text file as UTF-8, telling its encoding from its byte order mark.
+func decodeText(b []byte) (string, textEncoding) {
+	var order binary.ByteOrder
+	enc := encodingUTF8
+	switch {
+	case bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}):
+		return string(b[3:]), encodingUTF8BOM
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE}):
+		order, enc = binary.LittleEndian, encodingUTF16LE
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		order, enc = binary.BigEndian, encodingUTF16BE
+	default:
+		return string(b), enc
+	}
+	units := make([]uint16, (len(b)-2)/2)
+	for i := range units {
+		units[i] = order.Uint16(b[2+2*i:])
+	}
+	return string(utf16.Decode(units)), enc
+}
+
+// encodeText returns UTF-8 text in an encoding, with its byte order mark.
+func encodeText(s string, enc textEncoding) []byte {
+	var order binary.ByteOrder
+	switch enc {
+	case encodingUTF8BOM:
+		return append([]byte{0xEF, 0xBB, 0xBF}, s...)
+	case encodingUTF16LE:
+		order = binary.LittleEndian
+	case encodingUTF16BE:
+		order = binary.BigEndian
+	default:
+		return []byte(s)
+	}
+	units := utf16.Encode([]rune(s))
+	b := make([]byte, 2+2*len(units))
+	order.PutUint16(b, 0xFEFF)
+	for i, u := range units {
+		order.PutUint16(b[2+2*i:], u)
+	}
+	return b
+}
+
+// plistSkeleton is the document translations are added to when the target .stringsdict file does not exist.
+const plistSkeleton = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+</dict>
+</plist>
+`
+
+// AppleStringsDict is the codec of iOS and macOS .stringsdict files of plural rules. The plural forms of each
+// variable are keyed by the entry's key followed by the variable and the form in brackets, such as
+// "%d files[files.one]". The format of an entry, NSStringLocalizedFormatKey, is a unit keyed by the entry's key
+// when it has text besides its variables.
+type AppleStringsDict struct{}
+
+// plistPair is a key of a plist dictionary and its value.
+type plistPair struct {
+	name       string
+	key, value *xmlElement
+}
+
+func plistDict(d *xmlElement) []plistPair {
+	var pairs []plistPair
+	for i := 0; i+1 < len(d.children); i++ {
+		if d.children[i].name == "key" {
+			pairs = append(pairs, plistPair{name: strings.TrimSpace(d.children[i].text), key: d.children[i], value: d.children[i+1]})
+			i++
+		}
+	}
+	return pairs
+}
+
+// stringsDictEntry is an entry of a .stringsdict file.
+type stringsDictEntry struct {
+	plistPair
+	format    *xmlElement
+	variables []stringsDictVariable
+}
+
+type stringsDictVariable struct {
+	plistPair
+	forms []plistPair
+}
+
+func (e *stringsDictEntry) variable(name string) *stringsDictVariable {
+	for i := range e.variables {
+		if e.variables[i].name == name {
+			return &e.variables[i]
+		}
+	}
+	return nil
+}
+
+func (v *stringsDictVariable) form(name string) *plistPair {
+	for i := range v.forms {
+		if v.forms[i].name == name {
+			return &v.forms[i]
+		}
+	}
+	return nil
+}
+
+func parseStringsDict(data []byte) (*xmlElement, []stringsDictEntry, error) {
+	doc, err := parseXML(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	plist, err := doc.root("plist")
+	if err != nil {
+		return nil, nil, err
+	}
+	root := plist.child("dict")
+	if root == nil {
+		return nil, nil, fmt.Errorf("no <dict> in <plist>")
+	}
+	var entries []stringsDictEntry
+	for _, p := range plistDict(root) {
+		if p.value.name != "dict" {
+			continue
+		}
+		e := stringsDictEntry{plistPair: p}
+		for _, field := range plistDict(p.value) {
+			switch {
+			case field.name == "NSStringLocalizedFormatKey" && field.value.name == "string":
+				e.format = field.value
+			case field.value.name == "dict":
+				v := stringsDictVariable{plistPair: field}
+				for _, form := range plistDict(field.value) {
+					if pluralForms[form.name] && form.value.name == "string" {
+						v.forms = append(v.forms, form)
+					}
+				}
+				e.variables = append(e.variables, v)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return root, entries, nil
+}
+
+// stringsDictVariables matches the variables of a format, such as %#@files@ or %1$#@files@.
+var stringsDictVariables = regexp.MustCompile(`%(\d+\$)?#@[^@]+@`)
+
+// formatKey returns the unit key of an entry's format, or "" if the format has no text besides its variables.
+func (e *stringsDictEntry) formatKey() string {
+	if e.format == nil || strings.TrimSpace(stringsDictVariables.ReplaceAllString(e.format.text, "")) == "" {
+		return ""
+	}
+	return e.name
+}
+
+func formKey(entry, variable, form string) string {
+	return entry + "[" + variable + "." + form + "]"
+}
+
+// Decode returns a unit per plural form of each variable, and per format with text.
+func (c AppleStringsDict) Decode(data []byte) ([]Unit, error) {
+	return c.DecodeFor(data, "")
+}
+
+// DecodeFor returns a unit per plural form of the language of locale of each variable, and per format with text.
+// Each form has the text of the source form of the same category, or else of "other".
+func (AppleStringsDict) DecodeFor(data []byte, locale string) ([]Unit, error) {
+	_, entries, err := parseStringsDict(data)
+	if err != nil {
+		return nil, err
+	}
+	var units []Unit
+	for _, e := range entries {
+		if key := e.formatKey(); key != "" {
+			units = append(units, Unit{Key: key, Text: e.format.text, Comment: e.key.comment})
+		}
+		for _, v := range e.variables {
+			if locale != "" {
+				v = v.localize(locale)
+			}
+			for _, f := range v.forms {
+				units = append(units, Unit{Key: formKey(e.name, v.name, f.name), Text: f.value.text, Comment: pluralComment(e.key.comment, f.name)})
+			}
+		}
+	}
+	return units, nil
+}
+
+// Merge sets the translated forms in target. Entries missing from target are copied from source with their
+// untranslated forms left out, and variables get the plural forms of the language of locale.
+func (AppleStringsDict) Merge(source, target []byte, locale string, translations map[string]string) ([]byte, error) {
+	_, srcEntries, err := parseStringsDict(source)
+	if err != nil {
+		return nil, fmt.Errorf("source: %v", err)
+	}
+	if target == nil {
+		target = []byte(plistSkeleton)
+	}
+	root, entries, err := parseStringsDict(target)
+	if err != nil {
+		return nil, fmt.Errorf("target: %v", err)
+	}
+	existing := map[string]*stringsDictEntry{}
+	for i := range entries {
+		existing[entries[i].name] = &entries[i]
+	}
+	var edits []edit
+	for _, e := range srcEntries {
+		te := existing[e.name]
+		var copyEdits []edit
+		translated := false
+		if t, ok := translations[e.formatKey()]; ok && e.formatKey() != "" {
+			translated = true
+			copyEdits = append(copyEdits, e.format.setContent(source, xmlEscape(t, false)))
+			if te != nil && te.format != nil {
+				edits = append(edits, te.format.setContent(target, xmlEscape(t, false)))
+			}
+		}
+		for _, v := range e.variables {
+			var tv *stringsDictVariable
+			if te != nil {
+				tv = te.variable(v.name)
+			}
+			// forms are the translated forms, which are written anew as their categories may not be in source.
+			var forms []string
+			sourceForms := v.forms
+			for _, f := range v.localize(locale).forms {
+				t, ok := translations[formKey(e.name, v.name, f.name)]
+				if !ok {
+					continue
+				}
+				translated = true
+				content := xmlEscape(t, false)
+				forms = append(forms, "<key>"+f.name+"</key>", "<string>"+content+"</string>")
+				if tv == nil {
+					continue
+				}
+				if tf := tv.form(f.name); tf != nil {
+					edits = append(edits, tf.value.setContent(target, content))
+				} else {
+					edits = append(edits, appendChild(target, tv.value, "<key>"+f.name+"</key>\n<string>"+content+"</string>", "\t"))
+				}
+			}
+			var elements []*xmlElement
+			for _, f := range sourceForms {
+				elements = append(elements, f.key, f.value)
+			}
+			copyEdits = append(copyEdits, replaceChildren(source, elements, forms)...)
+		}
+		if translated && te == nil {
+			edits = append(edits, appendChild(target, root, copyXML(source, e.key.start, e.value.end, copyEdits), "\t"))
+		}
+	}
+	return splice(target, 0, len(target), edits), nil
+}
+
+// localize returns the variable with a plural form per plural category of the language of locale, each being the
+// source form of the same category, or else of "other".
+func (v stringsDictVariable) localize(locale string) stringsDictVariable {
+	if len(v.forms) == 0 {
+		return v
+	}
+	names := make([]string, len(v.forms))
+	for i, f := range v.forms {
+		names[i] = f.name
+	}
+	l := v
+	l.forms = nil
+	for _, c := range pluralCategories(locale) {
+		f := v.forms[pluralSource(names, c)]
+		f.name = c
+		l.forms = append(l.forms, f)
+	}
+	return l
+}
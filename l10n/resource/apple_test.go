@@ -0,0 +1,178 @@
+package resource
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const enStrings = `/* Title of the home screen. */
+"home.title" = "Welcome";
+
+/* No comment provided by engineer. */
+"Quote \"%@\"" = "Quote \"%@\"\n";
+// Button
+login = "Log in";
+`
+
+func TestAppleStrings(t *testing.T) {
+	units, err := AppleStrings{}.Decode([]byte(enStrings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "home.title", Text: "Welcome", Comment: "Title of the home screen."},
+		{Key: `Quote "%@"`, Text: "Quote \"%@\"\n"},
+		{Key: "login", Text: "Log in", Comment: "Button"},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("Decode() = %+v\nwant %+v", units, want)
+	}
+
+	target := encodeText("/* Translated. */\n\"home.title\" = \"Bienvenu\";\n", encodingUTF16LE)
+	b, err := AppleStrings{}.Merge([]byte(enStrings), target, "fr", map[string]string{"home.title": "Bienvenue", "login": "Connexion", `Quote "%@"`: "Citer « %@ »"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, enc := decodeText(b)
+	wantText := `/* Translated. */
+"home.title" = "Bienvenue";
+
+/* No comment provided by engineer. */
+"Quote \"%@\"" = "Citer « %@ »";
+
+/* Button */
+"login" = "Connexion";
+`
+	if text != wantText || enc != encodingUTF16LE {
+		t.Errorf("Merge() = %v\n%s\nwant UTF-16LE\n%s", enc, text, wantText)
+	}
+	if _, err := (AppleStrings{}).Decode([]byte(`"a" = "b"`)); err == nil {
+		t.Error("Decode() without a semicolon succeeded")
+	}
+}
+
+const enStringsDict = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>%d files</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@files@</string>
+		<key>files</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d file</string>
+			<key>other</key>
+			<string>%d files</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+
+func TestAppleStringsDict(t *testing.T) {
+	units, err := AppleStringsDict{}.Decode([]byte(enStringsDict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "%d files[files.one]", Text: "%d file", Comment: `Plural form "one".`},
+		{Key: "%d files[files.other]", Text: "%d files", Comment: `Plural form "other".`},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("Decode() = %+v\nwant %+v", units, want)
+	}
+
+	b, err := AppleStringsDict{}.Merge([]byte(enStringsDict), nil, "ja", map[string]string{"%d files[files.other]": "%d 個のファイル"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPlist := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>%d files</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@files@</string>
+		<key>files</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>other</key>
+			<string>%d 個のファイル</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+	if string(b) != wantPlist {
+		t.Errorf("Merge() =\n%s\nwant\n%s", b, wantPlist)
+	}
+
+	b, err = AppleStringsDict{}.Merge([]byte(enStringsDict), b, "ja", map[string]string{"%d files[files.one]": "%d ファイル"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	units, err = AppleStringsDict{}.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Japanese has no form "one".
+	if len(units) != 1 || units[0].Key != "%d files[files.other]" || units[0].Text != "%d 個のファイル" {
+		t.Errorf("Decode() after Merge() = %+v", units)
+	}
+}
+
+func TestAppleStringsDictPlurals(t *testing.T) {
+	units, err := AppleStringsDict{}.DecodeFor([]byte(enStringsDict), "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "%d files[files.one]", Text: "%d file", Comment: `Plural form "one".`},
+		{Key: "%d files[files.few]", Text: "%d files", Comment: `Plural form "few".`},
+		{Key: "%d files[files.many]", Text: "%d files", Comment: `Plural form "many".`},
+		{Key: "%d files[files.other]", Text: "%d files", Comment: `Plural form "other".`},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("DecodeFor(ru) = %+v\nwant %+v", units, want)
+	}
+
+	translations := map[string]string{
+		"%d files[files.one]":   "%d файл",
+		"%d files[files.few]":   "%d файла",
+		"%d files[files.many]":  "%d файлов",
+		"%d files[files.other]": "%d файла",
+	}
+	b, err := AppleStringsDict{}.Merge([]byte(enStringsDict), nil, "ru", map[string]string{"%d files[files.one]": "%d файл", "%d files[files.few]": "%d файла"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "\t\t\t<string>d</string>\n\t\t\t<key>one</key>\n\t\t\t<string>%d файл</string>\n\t\t\t<key>few</key>\n\t\t\t<string>%d файла</string>\n\t\t</dict>") {
+		t.Errorf("Merge(ru) =\n%s", b)
+	}
+	if b, err = (AppleStringsDict{}).Merge([]byte(enStringsDict), b, "ru", translations); err != nil {
+		t.Fatal(err)
+	}
+	units, err = AppleStringsDict{}.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(units) != 4 {
+		t.Fatalf("Decode() after Merge(ru) = %+v", units)
+	}
+	for _, u := range units {
+		if u.Text != translations[u.Key] {
+			t.Errorf("Decode() of the merged %s = %q, want %q", u.Key, u.Text, translations[u.Key])
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeySeparator joins the keys of nested JSON objects into unit keys, as in i18next.
+const KeySeparator = "."
+
+func init() {
+	Register("json", JSON{}, ".json")
+	Register("go-i18n", GoI18n{})
+}
+
+// JSON is the codec of nested JSON objects of strings, such as i18next resources. Values other than strings
+// are kept but not translated.
+type JSON struct{}
+
+// Decode returns a unit per string, keyed by its path of object keys joined by KeySeparator. Object keys containing
+// KeySeparator must not make two strings share a key.
+func (JSON) Decode(data []byte) ([]Unit, error) {
+	root, err := parseJSONObject(data)
+	if err != nil {
+		return nil, err
+	}
+	var units []Unit
+	var walk func(o *jsonObject, prefix string)
+	walk = func(o *jsonObject, prefix string) {
+		for _, k := range o.keys {
+			v, key := o.values[k], joinKey(prefix, k)
+			switch {
+			case v.object != nil:
+				walk(v.object, key)
+			case v.str != nil:
+				units = append(units, Unit{Key: key, Text: *v.str})
+			}
+		}
+	}
+	walk(root, "")
+	return units, checkKeys(units)
+}
+
+// Merge sets the translated strings in target, creating the objects leading to them as needed.
+func (JSON) Merge(source, target []byte, _ string, translations map[string]string) ([]byte, error) {
+	src, err := parseJSONObject(source)
+	if err != nil {
+		return nil, fmt.Errorf("source: %v", err)
+	}
+	return mergeJSONFile(src, source, target, translations)
+}
+
+// mergeJSONFile sets the translations of the strings of src, parsed from source, in target.
+func mergeJSONFile(src *jsonObject, source, target []byte, translations map[string]string) ([]byte, error) {
+	var err error
+	layout := source
+	tgt := newJSONObject()
+	if target != nil {
+		if tgt, err = parseJSONObject(target); err != nil {
+			return nil, fmt.Errorf("target: %v", err)
+		}
+		layout = target
+	}
+	mergeJSON(src, tgt, "", translations)
+	var buf bytes.Buffer
+	writeJSON(&buf, &jsonValue{object: tgt}, jsonIndent(layout), 0)
+	if len(bytes.TrimSpace(layout)) == 0 || bytes.HasSuffix(layout, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// GoI18n is the codec of go-i18n message files in JSON. Messages are strings, or objects with a description and
+// plural forms. Each plural form is a unit keyed by the message ID and the form, such as "PersonCats.one", with the
+// description as comment. DecodeFor and Merge give plural messages the plural forms of the target language, each
+// from the source form of the same CLDR category, or else from "other".
+type GoI18n struct{}
+
+// Decode returns a unit per message, or per plural form of plural messages.
+func (c GoI18n) Decode(data []byte) ([]Unit, error) {
+	return c.DecodeFor(data, "")
+}
+
+// DecodeFor returns a unit per message, or per plural form of the language of locale of plural messages.
+func (GoI18n) DecodeFor(data []byte, locale string) ([]Unit, error) {
+	root, err := parseJSONObject(data)
+	if err != nil {
+		return nil, err
+	}
+	if locale != "" {
+		localizeGoI18n(root, locale)
+	}
+	var units []Unit
+	var walk func(o *jsonObject, prefix string)
+	walk = func(o *jsonObject, prefix string) {
+		for _, k := range o.keys {
+			v, key := o.values[k], joinKey(prefix, k)
+			switch {
+			case v.object != nil && isGoI18nMessage(v.object):
+				description := ""
+				if d := v.object.values["description"]; d != nil && d.str != nil {
+					description = *d.str
+				}
+				forms := goI18nForms(v.object)
+				for _, f := range forms {
+					comment := description
+					if len(forms) > 1 || f != "other" {
+						comment = pluralComment(comment, f)
+					}
+					units = append(units, Unit{Key: joinKey(key, f), Text: *v.object.values[f].str, Comment: comment})
+				}
+			case v.object != nil:
+				walk(v.object, key)
+			case v.str != nil:
+				units = append(units, Unit{Key: key, Text: *v.str})
+			}
+		}
+	}
+	walk(root, "")
+	return units, checkKeys(units)
+}
+
+// Merge sets the translated messages in target, leaving out their descriptions.
+func (GoI18n) Merge(source, target []byte, locale string, translations map[string]string) ([]byte, error) {
+	src, err := parseJSONObject(source)
+	if err != nil {
+		return nil, fmt.Errorf("source: %v", err)
+	}
+	localizeGoI18n(src, locale)
+	return mergeJSONFile(src, source, target, translations)
+}
+
+// goI18nFields are the fields of go-i18n message objects.
+var goI18nFields = map[string]bool{"id": true, "hash": true, "description": true, "leftdelim": true, "rightdelim": true}
+
+// isGoI18nMessage tells whether an object is a go-i18n message rather than a group of messages.
+func isGoI18nMessage(o *jsonObject) bool {
+	if other := o.values["other"]; other == nil || other.str == nil {
+		return false
+	}
+	for _, k := range o.keys {
+		if !goI18nFields[k] && !pluralForms[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// goI18nForms returns the plural forms of a message, in file order.
+func goI18nForms(msg *jsonObject) []string {
+	var forms []string
+	for _, f := range msg.keys {
+		if pluralForms[f] && msg.values[f].str != nil {
+			forms = append(forms, f)
+		}
+	}
+	return forms
+}
+
+// localizeGoI18n replaces the plural forms of the plural messages of o with those of the language of locale. A
+// message with only "other" is not plural.
+func localizeGoI18n(o *jsonObject, locale string) {
+	for _, k := range o.keys {
+		v := o.values[k]
+		switch {
+		case v.object != nil && isGoI18nMessage(v.object):
+			forms := goI18nForms(v.object)
+			if len(forms) == 1 {
+				continue
+			}
+			msg := newJSONObject()
+			for _, f := range v.object.keys {
+				if !pluralForms[f] {
+					msg.set(f, v.object.values[f])
+				}
+			}
+			for _, c := range pluralCategories(locale) {
+				msg.set(c, v.object.values[forms[pluralSource(forms, c)]])
+			}
+			v.object = msg
+		case v.object != nil:
+			localizeGoI18n(v.object, locale)
+		}
+	}
+}
+
+// mergeJSON sets the translations of the strings of src in tgt and reports whether any was set.
+func mergeJSON(src, tgt *jsonObject, prefix string, translations map[string]string) bool {
+	set := false
+	for _, k := range src.keys {
+		v, key := src.values[k], joinKey(prefix, k)
+		switch {
+		case v.object != nil:
+			if existing, ok := tgt.values[k]; ok && existing.object != nil {
+				set = mergeJSON(v.object, existing.object, key, translations) || set
+				continue
+			}
+			child := newJSONObject()
+			if mergeJSON(v.object, child, key, translations) {
+				tgt.set(k, &jsonValue{object: child})
+				set = true
+			}
+		case v.str != nil:
+			if t, ok := translations[key]; ok {
+				tgt.set(k, &jsonValue{str: &t})
+				set = true
+			}
+		}
+	}
+	return set
+}
+
+// checkKeys returns an error if two strings have the same unit key, such as "a.b" and "b" in the object "a",
+// whose translations could not be told apart.
+func checkKeys(units []Unit) error {
+	seen := make(map[string]bool, len(units))
+	for _, u := range units {
+		if seen[u.Key] {
+			return fmt.Errorf("more than one string has the key %q", u.Key)
+		}
+		seen[u.Key] = true
+	}
+	return nil
+}
+
+func joinKey(prefix, k string) string {
+	if prefix == "" {
+		return k
+	}
+	return prefix + KeySeparator + k
+}
+
+// jsonValue is a JSON value which keeps the order of object keys.
+type jsonValue struct {
+	object *jsonObject
+	array  []*jsonValue
+	str    *string
+	// raw is a number, boolean or null as written.
+	raw string
+}
+
+type jsonObject struct {
+	keys   []string
+	values map[string]*jsonValue
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: map[string]*jsonValue{}}
+}
+
+// set replaces the value of a key in place, or adds the key at the end.
+func (o *jsonObject) set(k string, v *jsonValue) {
+	if _, ok := o.values[k]; !ok {
+		o.keys = append(o.keys, k)
+	}
+	o.values[k] = v
+}
+
+func parseJSONObject(data []byte) (*jsonObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := readJSON(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the top-level object")
+	}
+	if v.object == nil {
+		return nil, errors.New("the top-level value is not an object")
+	}
+	return v.object, nil
+}
+
+func readJSON(dec *json.Decoder) (*jsonValue, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			o := newJSONObject()
+			for dec.More() {
+				k, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v, err := readJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				o.set(k.(string), v)
+			}
+			_, err := dec.Token()
+			return &jsonValue{object: o}, err
+		}
+		v := &jsonValue{array: []*jsonValue{}}
+		for dec.More() {
+			e, err := readJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			v.array = append(v.array, e)
+		}
+		_, err := dec.Token()
+		return v, err
+	case string:
+		return &jsonValue{str: &t}, nil
+	case json.Number:
+		return &jsonValue{raw: t.String()}, nil
+	case bool:
+		return &jsonValue{raw: fmt.Sprint(t)}, nil
+	}
+	return &jsonValue{raw: "null"}, nil
+}
+
+// jsonIndent returns the indentation of the first indented line of data, or two spaces.
+func jsonIndent(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n")[1:] {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != line && trimmed != "" {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
+
+func writeJSON(buf *bytes.Buffer, v *jsonValue, indent string, depth int) {
+	inner := strings.Repeat(indent, depth+1)
+	switch {
+	case v.object != nil:
+		if len(v.object.keys) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+		buf.WriteString("{\n")
+		for i, k := range v.object.keys {
+			buf.WriteString(inner)
+			writeJSONString(buf, k)
+			buf.WriteString(": ")
+			writeJSON(buf, v.object.values[k], indent, depth+1)
+			if i < len(v.object.keys)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(strings.Repeat(indent, depth) + "}")
+	case v.array != nil:
+		if len(v.array) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteString("[\n")
+		for i, e := range v.array {
+			buf.WriteString(inner)
+			writeJSON(buf, e, indent, depth+1)
+			if i < len(v.array)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(strings.Repeat(indent, depth) + "]")
+	case v.str != nil:
+		writeJSONString(buf, *v.str)
+	default:
+		buf.WriteString(v.raw)
+	}
+}
+
+// writeJSONString writes a JSON string without escaping HTML characters, which are common in translations.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,160 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+)
+
+const enJSON = `{
+    "title": "Welcome",
+    "menu": {
+        "open": "Open <b>file</b>",
+        "close": "Close"
+    },
+    "count": 3,
+    "tags": ["a", "b"]
+}
+`
+
+func TestJSONDecode(t *testing.T) {
+	units, err := JSON{}.Decode([]byte(enJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{{Key: "title", Text: "Welcome"}, {Key: "menu.open", Text: "Open <b>file</b>"}, {Key: "menu.close", Text: "Close"}}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("Decode() = %+v, want %+v", units, want)
+	}
+	if _, err := (JSON{}).Decode([]byte(`["a"]`)); err == nil {
+		t.Error("Decode() of an array succeeded")
+	}
+	if _, err := (JSON{}).Decode([]byte(`{"menu.open": "Open", "menu": {"open": "Open file"}}`)); err == nil {
+		t.Error("Decode() of a flat key colliding with a nested one succeeded")
+	}
+	if units, err := (JSON{}).Decode([]byte(`{"menu.open": "Open", "menu": {"close": "Close"}}`)); err != nil || len(units) != 2 {
+		t.Errorf("Decode() of a flat key = %+v, %v", units, err)
+	}
+}
+
+func TestJSONMerge(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		translations map[string]string
+		want         string
+	}{
+		{"new file", "", map[string]string{"menu.close": "閉じる", "title": "ようこそ"}, `{
+    "title": "ようこそ",
+    "menu": {
+        "close": "閉じる"
+    }
+}
+`},
+		{"existing file", `{
+  "extra": true,
+  "menu": {"close": "閉"},
+  "title": "古い"
+}`, map[string]string{"menu.open": "<b>ファイル</b>を開く", "title": "ようこそ"}, `{
+  "extra": true,
+  "menu": {
+    "close": "閉",
+    "open": "<b>ファイル</b>を開く"
+  },
+  "title": "ようこそ"
+}`},
+	}
+	for _, tt := range tests {
+		var target []byte
+		if tt.target != "" {
+			target = []byte(tt.target)
+		}
+		b, err := JSON{}.Merge([]byte(enJSON), target, "ja", tt.translations)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%s: Merge() =\n%s\nwant\n%s", tt.name, b, tt.want)
+		}
+	}
+}
+
+func TestForFile(t *testing.T) {
+	format, c, err := ForFile("locales/en/app.JSON")
+	if err != nil || format != "json" || c == nil {
+		t.Errorf("ForFile() = %q, %v, %v", format, c, err)
+	}
+	if _, _, err := ForFile("app.txt"); err == nil {
+		t.Error("ForFile() of an unknown extension succeeded")
+	}
+}
+
+func TestGoI18n(t *testing.T) {
+	data := `{
+  "Hello": "Hello!",
+  "PersonCats": {
+    "description": "The number of cats a person has",
+    "one": "{{.Name}} has {{.Count}} cat.",
+    "other": "{{.Name}} has {{.Count}} cats."
+  },
+  "Bye": {"hash": "sha1-x", "other": "Bye"}
+}`
+	units, err := GoI18n{}.Decode([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "Hello", Text: "Hello!"},
+		{Key: "PersonCats.one", Text: "{{.Name}} has {{.Count}} cat.", Comment: "The number of cats a person has\nPlural form \"one\"."},
+		{Key: "PersonCats.other", Text: "{{.Name}} has {{.Count}} cats.", Comment: "The number of cats a person has\nPlural form \"other\"."},
+		{Key: "Bye.other", Text: "Bye"},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("Decode() = %+v\nwant %+v", units, want)
+	}
+	b, err := GoI18n{}.Merge([]byte(data), nil, "fr", map[string]string{"PersonCats.other": "{{.Name}} a {{.Count}} chats."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"PersonCats\": {\n    \"other\": \"{{.Name}} a {{.Count}} chats.\"\n  }\n}"; string(b) != want {
+		t.Errorf("Merge() =\n%s\nwant\n%s", b, want)
+	}
+}
+
+func TestGoI18nPlurals(t *testing.T) {
+	data := `{
+  "PersonCats": {
+    "description": "The number of cats a person has",
+    "one": "{{.Count}} cat",
+    "other": "{{.Count}} cats"
+  },
+  "Bye": {"other": "Bye"}
+}`
+	units, err := GoI18n{}.DecodeFor([]byte(data), "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, u := range units {
+		keys = append(keys, u.Key+"="+u.Text)
+	}
+	want := []string{"PersonCats.one={{.Count}} cat", "PersonCats.few={{.Count}} cats", "PersonCats.many={{.Count}} cats", "PersonCats.other={{.Count}} cats", "Bye.other=Bye"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("DecodeFor(ru) = %q, want %q", keys, want)
+	}
+	if units, _ := (GoI18n{}).DecodeFor([]byte(data), "ja"); len(units) != 2 || units[0].Key != "PersonCats.other" {
+		t.Errorf("DecodeFor(ja) = %+v", units)
+	}
+
+	b, err := GoI18n{}.Merge([]byte(data), nil, "ru", map[string]string{
+		"PersonCats.one":  "{{.Count}} кошка",
+		"PersonCats.few":  "{{.Count}} кошки",
+		"PersonCats.many": "{{.Count}} кошек",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := "{\n  \"PersonCats\": {\n    \"one\": \"{{.Count}} кошка\",\n    \"few\": \"{{.Count}} кошки\",\n    \"many\": \"{{.Count}} кошек\"\n  }\n}"
+	if string(b) != wantJSON {
+		t.Errorf("Merge(ru) =\n%s\nwant\n%s", b, wantJSON)
+	}
+}
@@ -0,0 +1,544 @@
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("po", PO{}, ".po", ".pot")
+}
+
+// POContextSeparator joins the msgctxt and msgid of entries with a context into their key, as in MO files.
+const POContextSeparator = "\x04"
+
+// PO is the codec of gettext PO and POT files. Entries are keyed by msgid, prefixed by their msgctxt and
+// POContextSeparator if any, and their extracted comments (#.) are their comment. Plural entries are a unit per
+// msgstr, keyed with its index such as "[2]": Decode returns the msgid as "[0]" and the msgid_plural as "[1]", and
+// DecodeFor a unit per plural form of the target language, commented with its CLDR category.
+// The header, obsolete entries and fuzzy translations are left out.
+type PO struct{}
+
+// poEntry is an entry of a PO file, with its lines.
+type poEntry struct {
+	// start and end are the lines of the entry.
+	start, end int
+	comments   []string
+	context    string
+	hasContext bool
+	id, plural string
+	hasID      bool
+	hasPlural  bool
+	msgstr     []string
+	// strStart and strEnd are the lines of the msgstr strings.
+	strStart, strEnd int
+	// flagLine is the line of the #, flags, or -1.
+	flagLine int
+	fuzzy    bool
+	obsolete bool
+}
+
+func (e *poEntry) key() string {
+	if e.hasContext {
+		return e.context + POContextSeparator + e.id
+	}
+	return e.id
+}
+
+func (e *poEntry) isHeader() bool {
+	return e.id == "" && !e.hasContext
+}
+
+// extracted returns the extracted comments of the entry.
+func (e *poEntry) extracted() string {
+	var lines []string
+	for _, c := range e.comments {
+		if strings.HasPrefix(c, "#.") {
+			lines = append(lines, strings.TrimSpace(c[2:]))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parsePO returns the lines of a PO file and its entries.
+func parsePO(data []byte) ([]string, []*poEntry, error) {
+	lines := strings.Split(string(data), "\n")
+	var entries []*poEntry
+	var e *poEntry
+	var field *string
+	finish := func(end int) {
+		if e != nil {
+			e.end = end
+			if e.hasID {
+				entries = append(entries, e)
+			}
+		}
+		e, field = nil, nil
+	}
+	for i, line := range lines {
+		t := strings.TrimSpace(line)
+		switch {
+		case t == "":
+			finish(i)
+		case strings.HasPrefix(t, "#"):
+			if e != nil && e.strStart > 0 {
+				finish(i)
+			}
+			if e == nil {
+				e = &poEntry{start: i, flagLine: -1}
+			}
+			switch {
+			case strings.HasPrefix(t, "#~"):
+				e.obsolete = true
+			case strings.HasPrefix(t, "#,"):
+				e.flagLine = i
+				for _, flag := range strings.Split(t[2:], ",") {
+					e.fuzzy = e.fuzzy || strings.TrimSpace(flag) == "fuzzy"
+				}
+			}
+			e.comments = append(e.comments, t)
+			field = nil
+		case strings.HasPrefix(t, `"`):
+			if field == nil {
+				return nil, nil, fmt.Errorf("line %d: string without keyword", i+1)
+			}
+			s, err := poUnquote(t)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			*field += s
+			if e.strStart > 0 {
+				e.strEnd = i + 1
+			}
+		default:
+			keyword, rest, _ := strings.Cut(t, " ")
+			s, err := poUnquote(strings.TrimSpace(rest))
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			if e != nil && e.strStart > 0 && !strings.HasPrefix(keyword, "msgstr") {
+				finish(i)
+			}
+			if e == nil {
+				e = &poEntry{start: i, flagLine: -1}
+			}
+			switch {
+			case keyword == "msgctxt":
+				e.context, e.hasContext, field = s, true, &e.context
+			case keyword == "msgid":
+				e.id, e.hasID, field = s, true, &e.id
+			case keyword == "msgid_plural":
+				e.plural, e.hasPlural, field = s, true, &e.plural
+			case keyword == "msgstr" || strings.HasPrefix(keyword, "msgstr["):
+				n := 0
+				if keyword != "msgstr" {
+					if n, err = strconv.Atoi(strings.TrimSuffix(keyword[len("msgstr["):], "]")); err != nil || n < 0 {
+						return nil, nil, fmt.Errorf("line %d: invalid keyword %s", i+1, keyword)
+					}
+				}
+				for len(e.msgstr) <= n {
+					e.msgstr = append(e.msgstr, "")
+				}
+				e.msgstr[n] = s
+				field = &e.msgstr[n]
+				if e.strStart == 0 {
+					e.strStart = i
+				}
+				e.strEnd = i + 1
+			default:
+				return nil, nil, fmt.Errorf("line %d: unknown keyword %s", i+1, keyword)
+			}
+		}
+	}
+	finish(len(lines))
+	return lines, entries, nil
+}
+
+// decode returns the units of a file: with their msgstr as text if target is set, or else with their msgid and,
+// for a locale, with the plural forms of its language.
+func (PO) decode(data []byte, target bool, locale string) ([]Unit, error) {
+	_, entries, err := parsePO(data)
+	if err != nil {
+		return nil, err
+	}
+	// The plural forms of the source language, which are those of English unless translating.
+	categories := []string{"one", "other"}
+	if locale != "" {
+		categories = poPluralOf(locale).categories
+	}
+	var units []Unit
+	for _, e := range entries {
+		if e.isHeader() {
+			if target && len(e.msgstr) > 0 {
+				categories = poCategories(poHeader(e.msgstr[0], "Language"), poNPlurals(e.msgstr[0]))
+			}
+			continue
+		}
+		if e.obsolete {
+			continue
+		}
+		comment := e.extracted()
+		strs := e.msgstr
+		if e.fuzzy {
+			strs = nil
+		}
+		str := func(i int) string {
+			if i < len(strs) {
+				return strs[i]
+			}
+			return ""
+		}
+		if !e.hasPlural {
+			u := Unit{Key: e.key(), Text: e.id, Comment: comment, Context: e.context}
+			if target {
+				u.Text = str(0)
+			}
+			units = append(units, u)
+			continue
+		}
+		for i, category := range categories {
+			u := Unit{Key: fmt.Sprintf("%s[%d]", e.key(), i), Text: e.plural, Comment: pluralComment(comment, category), Context: e.context}
+			switch {
+			case target:
+				u.Text = str(i)
+			case category == "one":
+				u.Text = e.id
+			}
+			units = append(units, u)
+		}
+	}
+	return units, nil
+}
+
+// Decode returns a unit per entry, or two per plural entry, with their msgid as text.
+func (p PO) Decode(data []byte) ([]Unit, error) {
+	return p.decode(data, false, "")
+}
+
+// DecodeFor returns the units to translate into a locale: those of Decode, but with a unit per plural form of the
+// locale's language, whose text is the msgid for the form "one" and the msgid_plural for the others.
+func (p PO) DecodeFor(data []byte, locale string) ([]Unit, error) {
+	return p.decode(data, false, locale)
+}
+
+// DecodeTarget returns the units of a file with their msgstr as text, a unit per msgstr of plural entries.
+func (p PO) DecodeTarget(data []byte) ([]Unit, error) {
+	return p.decode(data, true, "")
+}
+
+// Merge sets the msgstr of target entries, which become unfuzzy. When target is nil, it is created from source,
+// which is usually a POT file, with its Language and Plural-Forms set from locale. Entries missing from target
+// are added at its end.
+func (PO) Merge(source, target []byte, locale string, translations map[string]string) ([]byte, error) {
+	_, srcEntries, err := parsePO(source)
+	if err != nil {
+		return nil, fmt.Errorf("source: %v", err)
+	}
+	created := target == nil
+	if created {
+		target = source
+	}
+	lines, entries, err := parsePO(target)
+	if err != nil {
+		return nil, fmt.Errorf("target: %v", err)
+	}
+	type lineEdit struct {
+		start, end int
+		lines      []string
+	}
+	var edits []lineEdit
+	nplurals := 2
+	existing := map[string]bool{}
+	for _, e := range entries {
+		if e.obsolete {
+			continue
+		}
+		existing[e.key()] = true
+		if !e.isHeader() || len(e.msgstr) == 0 {
+			continue
+		}
+		header := e.msgstr[0]
+		if locale != "" {
+			header = poSetHeader(header, "Language", strings.ReplaceAll(locale, "-", "_"), created)
+			header = poSetHeader(header, "Plural-Forms", poPluralForms(locale), created)
+		}
+		nplurals = poNPlurals(header)
+		if header != e.msgstr[0] {
+			edits = append(edits, lineEdit{e.strStart, e.strEnd, poFormat("msgstr", header)})
+		}
+		if created && e.fuzzy {
+			edits = append(edits, lineEdit{e.flagLine, e.flagLine + 1, poUnfuzzy(lines[e.flagLine])})
+		}
+	}
+	// msgstrs returns the msgstr of an entry with the translations set, or nil if none.
+	msgstrs := func(e *poEntry) []string {
+		if !e.hasPlural {
+			if t, ok := translations[e.key()]; ok {
+				return poFormat("msgstr", t)
+			}
+			return nil
+		}
+		strs := make([]string, nplurals)
+		copy(strs, e.msgstr)
+		translated := false
+		for i := range strs {
+			if t, ok := translations[fmt.Sprintf("%s[%d]", e.key(), i)]; ok {
+				strs[i], translated = t, true
+			}
+		}
+		if !translated {
+			return nil
+		}
+		var formatted []string
+		for i, s := range strs {
+			formatted = append(formatted, poFormat(fmt.Sprintf("msgstr[%d]", i), s)...)
+		}
+		return formatted
+	}
+	for _, e := range entries {
+		if e.obsolete || e.isHeader() {
+			continue
+		}
+		strs := msgstrs(e)
+		if strs == nil {
+			continue
+		}
+		if e.strStart == 0 {
+			edits = append(edits, lineEdit{e.end, e.end, strs})
+		} else {
+			edits = append(edits, lineEdit{e.strStart, e.strEnd, strs})
+		}
+		if e.fuzzy {
+			edits = append(edits, lineEdit{e.flagLine, e.flagLine + 1, poUnfuzzy(lines[e.flagLine])})
+		}
+	}
+	var added []string
+	for _, e := range srcEntries {
+		if e.obsolete || e.isHeader() || existing[e.key()] {
+			continue
+		}
+		strs := msgstrs(e)
+		if strs == nil {
+			continue
+		}
+		added = append(added, "")
+		for _, c := range e.comments {
+			if strings.HasPrefix(c, "#.") || strings.HasPrefix(c, "#:") {
+				added = append(added, c)
+			}
+		}
+		if e.hasContext {
+			added = append(added, poFormat("msgctxt", e.context)...)
+		}
+		added = append(added, poFormat("msgid", e.id)...)
+		if e.hasPlural {
+			added = append(added, poFormat("msgid_plural", e.plural)...)
+		}
+		added = append(added, strs...)
+	}
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var out []string
+	pos := 0
+	for _, ed := range edits {
+		out = append(out, lines[pos:ed.start]...)
+		out = append(out, ed.lines...)
+		pos = ed.end
+	}
+	out = append(out, lines[pos:]...)
+	if len(added) > 0 {
+		// Added entries go before the trailing newline, or the empty line it leaves.
+		trailing := len(out) > 0 && out[len(out)-1] == ""
+		if trailing {
+			out = out[:len(out)-1]
+		}
+		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+			added = added[1:]
+		}
+		out = append(out, added...)
+		if trailing {
+			out = append(out, "")
+		}
+	}
+	return []byte(strings.Join(out, "\n")), nil
+}
+
+// poUnfuzzy returns a flags line without the fuzzy flag, or no line if it was the only flag.
+func poUnfuzzy(line string) []string {
+	var flags []string
+	for _, flag := range strings.Split(strings.TrimPrefix(strings.TrimSpace(line), "#,"), ",") {
+		if flag = strings.TrimSpace(flag); flag != "" && flag != "fuzzy" {
+			flags = append(flags, flag)
+		}
+	}
+	if len(flags) == 0 {
+		return nil
+	}
+	return []string{"#, " + strings.Join(flags, ", ")}
+}
+
+// poSetHeader sets a field of a PO header if it is missing, empty or a POT placeholder, or if force is set.
+func poSetHeader(header, field, value string, force bool) string {
+	lines := strings.SplitAfter(header, "\n")
+	for i, line := range lines {
+		name, current, ok := strings.Cut(line, ":")
+		if !ok || name != field {
+			continue
+		}
+		current = strings.TrimSpace(current)
+		if force || current == "" || strings.Contains(current, "INTEGER") {
+			lines[i] = field + ": " + value + "\n"
+		}
+		return strings.Join(lines, "")
+	}
+	if header != "" && !strings.HasSuffix(header, "\n") {
+		header += "\n"
+	}
+	return header + field + ": " + value + "\n"
+}
+
+var nPluralsPattern = regexp.MustCompile(`nplurals\s*=\s*(\d+)`)
+
+// poNPlurals returns the number of plural forms of a PO header, 2 unless set.
+func poNPlurals(header string) int {
+	if m := nPluralsPattern.FindStringSubmatch(header); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 2
+}
+
+// poPlural is the plural rule of a language: its Plural-Forms and the CLDR category of each of its forms.
+type poPlural struct {
+	forms      string
+	categories []string
+}
+
+var (
+	poOneForm     = poPlural{"nplurals=1; plural=0;", []string{"other"}}
+	poEastSlavic  = poPlural{"nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);", []string{"one", "few", "many"}}
+	poWestSlavic  = poPlural{"nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;", []string{"one", "few", "other"}}
+	poFrench      = poPlural{"nplurals=2; plural=(n > 1);", []string{"one", "other"}}
+	poDefaultRule = poPlural{"nplurals=2; plural=(n != 1);", []string{"one", "other"}}
+)
+
+// poPlurals are the plural rules of the languages whose rules differ from English.
+var poPlurals = map[string]poPlural{
+	"ja":    poOneForm,
+	"ko":    poOneForm,
+	"zh":    poOneForm,
+	"th":    poOneForm,
+	"vi":    poOneForm,
+	"id":    poOneForm,
+	"ms":    poOneForm,
+	"fr":    poFrench,
+	"pt-BR": poFrench,
+	"ru":    poEastSlavic,
+	"uk":    poEastSlavic,
+	"pl":    {"nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);", []string{"one", "few", "many"}},
+	"cs":    poWestSlavic,
+	"sk":    poWestSlavic,
+	"ar": {"nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);",
+		[]string{"zero", "one", "two", "few", "many", "other"}},
+}
+
+// poPluralOf returns the plural rule of a locale.
+func poPluralOf(locale string) poPlural {
+	locale = strings.ReplaceAll(locale, "_", "-")
+	if rule, ok := poPlurals[locale]; ok {
+		return rule
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	if rule, ok := poPlurals[language]; ok {
+		return rule
+	}
+	return poDefaultRule
+}
+
+// poPluralForms returns the Plural-Forms of a locale.
+func poPluralForms(locale string) string {
+	return poPluralOf(locale).forms
+}
+
+// poCategories returns the CLDR category of each of the n plural forms of a locale. Files whose number of forms
+// differs from the rule of their language are assumed to have a form "one" followed by forms "other".
+func poCategories(locale string, n int) []string {
+	if categories := poPluralOf(locale).categories; len(categories) == n {
+		return categories
+	}
+	categories := make([]string, n)
+	for i := range categories {
+		categories[i] = "other"
+	}
+	if n > 1 {
+		categories[0] = "one"
+	}
+	return categories
+}
+
+// poHeader returns the value of a field of a PO header.
+func poHeader(header, field string) string {
+	for _, line := range strings.Split(header, "\n") {
+		if name, value, ok := strings.Cut(line, ":"); ok && name == field {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// poFormat returns the lines of a keyword and its string, one line per line of text if it has several.
+func poFormat(keyword, s string) []string {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		return []string{keyword + " " + poQuote(s)}
+	}
+	lines := []string{keyword + ` ""`}
+	for _, part := range strings.SplitAfter(s, "\n") {
+		if part != "" {
+			lines = append(lines, poQuote(part))
+		}
+	}
+	return lines
+}
+
+var poEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func poQuote(s string) string {
+	return `"` + poEscaper.Replace(s) + `"`
+}
+
+func poUnquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	s = s[1 : len(s)-1]
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
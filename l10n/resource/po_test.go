@@ -0,0 +1,176 @@
+package resource
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const enPOT = `# Messages of the app.
+msgid ""
+msgstr ""
+"Project-Id-Version: app 1.0\n"
+"Language: \n"
+"Plural-Forms: nplurals=INTEGER; plural=EXPRESSION;\n"
+
+#. Shown on the home screen.
+#: home.go:12
+msgid "Welcome"
+msgstr ""
+
+msgctxt "verb"
+msgid "Open"
+msgstr ""
+
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+msgid ""
+"Line one\n"
+"Line two"
+msgstr ""
+`
+
+func TestPODecode(t *testing.T) {
+	units, err := PO{}.Decode([]byte(enPOT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "Welcome", Text: "Welcome", Comment: "Shown on the home screen."},
+		{Key: "verb\x04Open", Text: "Open", Context: "verb"},
+		{Key: "%d file[0]", Text: "%d file", Comment: `Plural form "one".`},
+		{Key: "%d file[1]", Text: "%d files", Comment: `Plural form "other".`},
+		{Key: "Line one\nLine two", Text: "Line one\nLine two"},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("Decode() = %q\nwant %q", units, want)
+	}
+	if _, err := (PO{}).Decode([]byte("msgid \"a\nmsgstr \"\"")); err == nil {
+		t.Error("Decode() of an unterminated string succeeded")
+	}
+}
+
+func TestPOMerge(t *testing.T) {
+	translations := map[string]string{
+		"Welcome":            "ようこそ",
+		"%d file[0]":         "%d 個のファイル",
+		"Line one\nLine two": "一行目\n二行目",
+	}
+	b, err := PO{}.Merge([]byte(enPOT), nil, "ja", translations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Messages of the app.
+msgid ""
+msgstr ""
+"Project-Id-Version: app 1.0\n"
+"Language: ja\n"
+"Plural-Forms: nplurals=1; plural=0;\n"
+
+#. Shown on the home screen.
+#: home.go:12
+msgid "Welcome"
+msgstr "ようこそ"
+
+msgctxt "verb"
+msgid "Open"
+msgstr ""
+
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d 個のファイル"
+
+msgid ""
+"Line one\n"
+"Line two"
+msgstr ""
+"一行目\n"
+"二行目"
+`
+	if string(b) != want {
+		t.Errorf("Merge() =\n%s\nwant\n%s", b, want)
+	}
+	units, err := PO{}.DecodeTarget(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if units[0].Text != "ようこそ" || units[1].Text != "" || units[2].Text != "%d 個のファイル" || len(units) != 4 {
+		t.Errorf("DecodeTarget() = %q", units)
+	}
+
+	target := `msgid ""
+msgstr ""
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+#, fuzzy
+msgid "Welcome"
+msgstr "Bienvenu"
+
+#~ msgid "Old"
+#~ msgstr "Vieux"
+`
+	b, err = PO{}.Merge([]byte(enPOT), []byte(target), "fr", map[string]string{"Welcome": "Bienvenue", "verb\x04Open": "Ouvrir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `msgid ""
+msgstr ""
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+msgid "Welcome"
+msgstr "Bienvenue"
+
+#~ msgid "Old"
+#~ msgstr "Vieux"
+
+msgctxt "verb"
+msgid "Open"
+msgstr "Ouvrir"
+`
+	if string(b) != want {
+		t.Errorf("Merge() =\n%s\nwant\n%s", b, want)
+	}
+}
+
+func TestPOPlurals(t *testing.T) {
+	units, err := PO{}.DecodeFor([]byte(enPOT), "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "%d file[0]", Text: "%d file", Comment: `Plural form "one".`},
+		{Key: "%d file[1]", Text: "%d files", Comment: `Plural form "few".`},
+		{Key: "%d file[2]", Text: "%d files", Comment: `Plural form "many".`},
+	}
+	if !reflect.DeepEqual(units[2:5], want) {
+		t.Errorf("DecodeFor(ru) = %q\nwant %q", units[2:5], want)
+	}
+	if units, _ := (PO{}).DecodeFor([]byte(enPOT), "ar"); len(units) != 9 || units[2].Comment != `Plural form "zero".` || units[3].Text != "%d file" {
+		t.Errorf("DecodeFor(ar) = %q", units)
+	}
+	if units, _ := (PO{}).DecodeFor([]byte(enPOT), "ja"); len(units) != 4 || units[2].Text != "%d files" {
+		t.Errorf("DecodeFor(ja) = %q", units)
+	}
+
+	b, err := PO{}.Merge([]byte(enPOT), nil, "ru", map[string]string{"%d file[0]": "%d файл", "%d file[1]": "%d файла", "%d file[2]": "%d файлов"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "msgstr[0] \"%d файл\"\nmsgstr[1] \"%d файла\"\nmsgstr[2] \"%d файлов\"\n") {
+		t.Errorf("Merge(ru) =\n%s", b)
+	}
+	units, err = PO{}.DecodeTarget(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if units[2].Comment != `Plural form "one".` || units[4].Text != "%d файлов" || units[4].Comment != `Plural form "many".` {
+		t.Errorf("DecodeTarget() = %q", units)
+	}
+}
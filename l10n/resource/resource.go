@@ -0,0 +1,143 @@
+// Package resource reads the translatable strings of localization resource files and writes translations back
+// into them, keeping the files' order and comments.
+package resource
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Unit is a translatable string of a resource file.
+type Unit struct {
+	// Key identifies the string within its file.
+	Key  string
+	Text string
+	// Comment is a note for translators from the file, such as a description of where the string is shown.
+	Comment string
+	// Context disambiguates strings with the same text, such as gettext's msgctxt.
+	Context string
+	// MaxChars is the longest translation allowed, or zero.
+	MaxChars int
+}
+
+// Codec reads and writes a resource file format.
+type Codec interface {
+	// Decode returns the translatable units of a file, in file order.
+	Decode(data []byte) ([]Unit, error)
+	// Merge returns target with the translations of the keys of source set. Existing entries of target keep their
+	// place, comments and formatting, and entries missing from target are added in the order of source.
+	// target is nil when the target file does not exist yet. locale is the locale of target, such as "pt-BR".
+	Merge(source, target []byte, locale string, translations map[string]string) ([]byte, error)
+}
+
+// TargetDecoder is implemented by codecs of bilingual formats, such as gettext PO and XLIFF, whose files hold the
+// source text of each string next to its translation. Decode returns the source text of their units.
+type TargetDecoder interface {
+	// DecodeTarget returns the units of a file with their translation as text, empty if not translated.
+	DecodeTarget(data []byte) ([]Unit, error)
+}
+
+// LocaleDecoder is implemented by codecs whose units depend on the target locale: those of formats with plural
+// forms, which are the forms of the target language.
+type LocaleDecoder interface {
+	// DecodeFor returns the units of a source file to translate into a locale.
+	DecodeFor(data []byte, locale string) ([]Unit, error)
+}
+
+// pluralComment adds the plural form of a unit to its comment.
+func pluralComment(comment, form string) string {
+	note := fmt.Sprintf("Plural form %q.", form)
+	if comment == "" {
+		return note
+	}
+	return comment + "\n" + note
+}
+
+// pluralForms are the CLDR plural categories, as used by go-i18n, Android and iOS.
+var pluralForms = map[string]bool{"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true}
+
+// pluralCategories returns the CLDR plural categories of a locale, as used by go-i18n, Android and iOS: those of the
+// plural forms of its language in gettext, and "other", which CLDR gives every language even when no whole number
+// selects it.
+func pluralCategories(locale string) []string {
+	categories := poPluralOf(locale).categories
+	for _, c := range categories {
+		if c == "other" {
+			return categories
+		}
+	}
+	return append(categories[:len(categories):len(categories)], "other")
+}
+
+// pluralSource returns the index of the source form to translate a plural category of a target language from: the
+// form of the same category, or else "other", or else the last form.
+func pluralSource(forms []string, category string) int {
+	other := len(forms) - 1
+	for i, f := range forms {
+		if f == category {
+			return i
+		}
+		if f == "other" {
+			other = i
+		}
+	}
+	return other
+}
+
+var (
+	mu         sync.RWMutex
+	codecs     = map[string]Codec{}
+	extensions = map[string]string{}
+)
+
+// Register makes a codec available by format name and for files with the extensions, such as ".json".
+func Register(format string, c Codec, exts ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	codecs[format] = c
+	for _, ext := range exts {
+		extensions[strings.ToLower(ext)] = format
+	}
+}
+
+// Lookup returns the codec of a format.
+func Lookup(format string) (Codec, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	c, ok := codecs[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource format %q, expected one of %s", format, strings.Join(formatsLocked(), ", "))
+	}
+	return c, nil
+}
+
+// ForFile returns the format and codec of a file from its extension.
+func ForFile(path string) (string, Codec, error) {
+	mu.RLock()
+	format, ok := extensions[strings.ToLower(filepath.Ext(path))]
+	mu.RUnlock()
+	if !ok {
+		return "", nil, fmt.Errorf("cannot tell the resource format of %s", path)
+	}
+	c, err := Lookup(format)
+	return format, c, err
+}
+
+// Formats lists the registered formats.
+func Formats() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return formatsLocked()
+}
+
+func formatsLocked() []string {
+	formats := make([]string, 0, len(codecs))
+	for f := range codecs {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return formats
+}
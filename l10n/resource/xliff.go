@@ -0,0 +1,210 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("xliff", XLIFF{}, ".xlf", ".xliff")
+}
+
+// XLIFF is the codec of XLIFF 1.2 and 2.0 files. Units are keyed by the id of their trans-unit (1.2) or unit (2.0),
+// followed by the index of the segment in brackets for units of several segments, and prefixed by the original
+// or id of their file and a slash when there are several files. Notes are their comment, and the maxwidth of
+// trans-units counted in characters their MaxChars. Units with translate="no" are left out.
+//
+// Sources with inline codes, such as <x id="INTERPOLATION"/>, are sent as XML to keep the codes in translations.
+type XLIFF struct{}
+
+// xliffSegment is a source and its target, nil until translated.
+type xliffSegment struct {
+	key string
+	// unitKey is the key of the segment's unit.
+	unitKey        string
+	source, target *xmlElement
+	// unit is the trans-unit or unit element.
+	unit     *xmlElement
+	comment  string
+	maxChars int
+}
+
+// xliffDocument is a parsed XLIFF file.
+type xliffDocument struct {
+	root     *xmlElement
+	v2       bool
+	files    []*xmlElement
+	segments []xliffSegment
+}
+
+func parseXLIFF(data []byte) (*xliffDocument, error) {
+	doc, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+	root, err := doc.root("xliff")
+	if err != nil {
+		return nil, err
+	}
+	d := &xliffDocument{root: root, v2: strings.HasPrefix(root.attr("version"), "2"), files: root.find("file")}
+	for _, file := range d.files {
+		prefix := ""
+		if len(d.files) > 1 {
+			prefix = file.attr("original")
+			if d.v2 {
+				prefix = file.attr("id")
+			}
+			prefix += "/"
+		}
+		if !d.v2 {
+			for _, tu := range file.find("trans-unit") {
+				if tu.attr("translate") == "no" || tu.child("source") == nil {
+					continue
+				}
+				s := xliffSegment{key: prefix + tu.attr("id"), unitKey: prefix + tu.attr("id"), source: tu.child("source"), target: tu.child("target"), unit: tu}
+				var notes []string
+				for _, n := range tu.children {
+					if n.name == "note" {
+						notes = append(notes, strings.TrimSpace(n.text))
+					}
+				}
+				s.comment = strings.Join(notes, "\n")
+				if tu.attr("size-unit") == "char" {
+					s.maxChars, _ = strconv.Atoi(tu.attr("maxwidth"))
+				}
+				d.segments = append(d.segments, s)
+			}
+			continue
+		}
+		for _, unit := range file.find("unit") {
+			if unit.attr("translate") == "no" {
+				continue
+			}
+			var notes []string
+			if ns := unit.child("notes"); ns != nil {
+				for _, n := range ns.children {
+					notes = append(notes, strings.TrimSpace(n.text))
+				}
+			}
+			segments := unit.find("segment")
+			for i, seg := range segments {
+				if seg.child("source") == nil {
+					continue
+				}
+				unitKey := prefix + unit.attr("id")
+				key := unitKey
+				if len(segments) > 1 {
+					key += "[" + strconv.Itoa(i) + "]"
+				}
+				d.segments = append(d.segments, xliffSegment{key: key, unitKey: unitKey, source: seg.child("source"), target: seg.child("target"),
+					unit: unit, comment: strings.Join(notes, "\n")})
+			}
+		}
+	}
+	return d, nil
+}
+
+func (XLIFF) decode(data []byte, target bool) ([]Unit, error) {
+	d, err := parseXLIFF(data)
+	if err != nil {
+		return nil, err
+	}
+	units := make([]Unit, 0, len(d.segments))
+	for _, s := range d.segments {
+		u := Unit{Key: s.key, Comment: s.comment, MaxChars: s.maxChars}
+		switch {
+		case !target:
+			u.Text, _ = s.source.content(data)
+		case s.target != nil:
+			u.Text, _ = s.target.content(data)
+		}
+		units = append(units, u)
+	}
+	return units, nil
+}
+
+// Decode returns a unit per segment with its source text.
+func (x XLIFF) Decode(data []byte) ([]Unit, error) {
+	return x.decode(data, false)
+}
+
+// DecodeTarget returns a unit per segment with its target text.
+func (x XLIFF) DecodeTarget(data []byte) ([]Unit, error) {
+	return x.decode(data, true)
+}
+
+// Merge sets the targets of target, which is a copy of source when nil, and sets its target language if missing.
+// Units missing from target are copied from source into its first file.
+func (XLIFF) Merge(source, target []byte, locale string, translations map[string]string) ([]byte, error) {
+	src, err := parseXLIFF(source)
+	if err != nil {
+		return nil, fmt.Errorf("source: %v", err)
+	}
+	if target == nil {
+		target = source
+	}
+	tgt, err := parseXLIFF(target)
+	if err != nil {
+		return nil, fmt.Errorf("target: %v", err)
+	}
+	var edits []edit
+	if locale != "" {
+		if tgt.v2 && tgt.root.attr("trgLang") == "" {
+			edits = append(edits, tgt.root.setAttr(target, "trgLang", locale))
+		}
+		for _, file := range tgt.files {
+			if !tgt.v2 && file.attr("target-language") == "" {
+				edits = append(edits, file.setAttr(target, "target-language", locale))
+			}
+		}
+	}
+	existing := map[string]bool{}
+	for _, s := range tgt.segments {
+		existing[s.unitKey] = true
+		if t, ok := translations[s.key]; ok {
+			edits = append(edits, tgt.setTarget(target, s, t))
+		}
+	}
+	// Units missing from target are copied whole, with the targets of their segments set.
+	copied := map[*xmlElement][]edit{}
+	var units []*xmlElement
+	for _, s := range src.segments {
+		t, ok := translations[s.key]
+		if existing[s.unitKey] || !ok {
+			continue
+		}
+		if copied[s.unit] == nil {
+			units = append(units, s.unit)
+		}
+		copied[s.unit] = append(copied[s.unit], src.setTarget(source, s, t))
+	}
+	if len(units) > 0 {
+		if len(tgt.files) == 0 {
+			return nil, fmt.Errorf("target: no <file>")
+		}
+		parent := tgt.files[0]
+		if body := parent.child("body"); !tgt.v2 && body != nil {
+			parent = body
+		}
+		for _, unit := range units {
+			edits = append(edits, appendChild(target, parent, copyXML(source, unit.start, unit.end, copied[unit]), "  "))
+		}
+	}
+	return splice(target, 0, len(target), edits), nil
+}
+
+// setTarget returns the edit setting the target of a segment, adding the target element after the source if needed.
+func (d *xliffDocument) setTarget(data []byte, s xliffSegment, translation string) edit {
+	if _, raw := s.source.content(data); !raw {
+		translation = xmlEscape(translation, false)
+	}
+	if s.target != nil {
+		return s.target.setContent(data, translation)
+	}
+	open := "<target>"
+	if !d.v2 {
+		open = `<target state="translated">`
+	}
+	return edit{s.source.end, s.source.end, "\n" + indentOf(data, s.source.start) + open + translation + "</target>"}
+}
@@ -0,0 +1,154 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+)
+
+const enXLIFF12 = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file source-language="en" datatype="plaintext" original="app">
+    <body>
+      <trans-unit id="greeting" maxwidth="20" size-unit="char">
+        <source>Hello &amp; welcome</source>
+        <note>Title of the home screen.</note>
+      </trans-unit>
+      <trans-unit id="count">
+        <source>You have <x id="INTERPOLATION"/> messages</source>
+      </trans-unit>
+      <trans-unit id="brand" translate="no">
+        <source>Acme</source>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>
+`
+
+func TestXLIFF12(t *testing.T) {
+	units, err := XLIFF{}.Decode([]byte(enXLIFF12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "greeting", Text: "Hello & welcome", Comment: "Title of the home screen.", MaxChars: 20},
+		{Key: "count", Text: `You have <x id="INTERPOLATION"/> messages`},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("Decode() = %+v\nwant %+v", units, want)
+	}
+	b, err := XLIFF{}.Merge([]byte(enXLIFF12), nil, "ja", map[string]string{
+		"greeting": "こんにちは & ようこそ",
+		"count":    `<x id="INTERPOLATION"/> 件のメッセージ`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantXLIFF := `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file source-language="en" datatype="plaintext" original="app" target-language="ja">
+    <body>
+      <trans-unit id="greeting" maxwidth="20" size-unit="char">
+        <source>Hello &amp; welcome</source>
+        <target state="translated">こんにちは &amp; ようこそ</target>
+        <note>Title of the home screen.</note>
+      </trans-unit>
+      <trans-unit id="count">
+        <source>You have <x id="INTERPOLATION"/> messages</source>
+        <target state="translated"><x id="INTERPOLATION"/> 件のメッセージ</target>
+      </trans-unit>
+      <trans-unit id="brand" translate="no">
+        <source>Acme</source>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>
+`
+	if string(b) != wantXLIFF {
+		t.Errorf("Merge() =\n%s\nwant\n%s", b, wantXLIFF)
+	}
+	units, err = XLIFF{}.DecodeTarget(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if units[0].Text != "こんにちは & ようこそ" {
+		t.Errorf("DecodeTarget() = %+v", units)
+	}
+}
+
+const enXLIFF20 = `<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en">
+  <file id="f1">
+    <unit id="intro">
+      <notes>
+        <note>Onboarding.</note>
+      </notes>
+      <segment>
+        <source>First.</source>
+      </segment>
+      <segment>
+        <source>Second.</source>
+      </segment>
+    </unit>
+    <unit id="bye">
+      <segment>
+        <source>Bye</source>
+      </segment>
+    </unit>
+  </file>
+</xliff>
+`
+
+func TestXLIFF20(t *testing.T) {
+	units, err := XLIFF{}.Decode([]byte(enXLIFF20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{Key: "intro[0]", Text: "First.", Comment: "Onboarding."},
+		{Key: "intro[1]", Text: "Second.", Comment: "Onboarding."},
+		{Key: "bye", Text: "Bye"},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("Decode() = %+v\nwant %+v", units, want)
+	}
+	target := `<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en" trgLang="fr">
+  <file id="f1">
+    <unit id="intro">
+      <segment>
+        <source>First.</source>
+        <target>Premier</target>
+      </segment>
+      <segment>
+        <source>Second.</source>
+      </segment>
+    </unit>
+  </file>
+</xliff>
+`
+	b, err := XLIFF{}.Merge([]byte(enXLIFF20), []byte(target), "fr", map[string]string{"intro[0]": "Premier.", "bye": "Au revoir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantXLIFF := `<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en" trgLang="fr">
+  <file id="f1">
+    <unit id="intro">
+      <segment>
+        <source>First.</source>
+        <target>Premier.</target>
+      </segment>
+      <segment>
+        <source>Second.</source>
+      </segment>
+    </unit>
+    <unit id="bye">
+      <segment>
+        <source>Bye</source>
+        <target>Au revoir</target>
+      </segment>
+    </unit>
+  </file>
+</xliff>
+`
+	if string(b) != wantXLIFF {
+		t.Errorf("Merge() =\n%s\nwant\n%s", b, wantXLIFF)
+	}
+}
@@ -0,0 +1,275 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// xmlElement is an element of an XML document along with its byte offsets, so that documents can be edited in
+// place instead of being re-encoded, which would lose their formatting and comments.
+type xmlElement struct {
+	// name is the name as written, such as "xliff:g".
+	name     string
+	attrs    []xml.Attr
+	parent   *xmlElement
+	children []*xmlElement
+	// text is the character data directly inside the element.
+	text string
+	// comment is the comment right before the element, if any.
+	comment string
+	// start and end delimit the element, and inner and innerEnd its content.
+	start, inner, innerEnd, end int
+}
+
+// parseXML returns the document node of data, whose children are the top-level elements.
+func parseXML(data []byte) (*xmlElement, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	doc := &xmlElement{end: len(data), innerEnd: len(data)}
+	cur, comment := doc, ""
+	for {
+		off := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			if t.Name.Space != "" {
+				name = t.Name.Space + ":" + name
+			}
+			el := &xmlElement{name: name, attrs: t.Attr, parent: cur, comment: comment, start: off, inner: int(dec.InputOffset())}
+			cur.children = append(cur.children, el)
+			cur, comment = el, ""
+		case xml.EndElement:
+			if cur == doc {
+				return nil, fmt.Errorf("unexpected </%s>", t.Name.Local)
+			}
+			cur.innerEnd, cur.end = off, int(dec.InputOffset())
+			cur, comment = cur.parent, ""
+		case xml.CharData:
+			cur.text += string(t)
+			if strings.TrimSpace(string(t)) != "" {
+				comment = ""
+			}
+		case xml.Comment:
+			comment = strings.TrimSpace(string(t))
+		}
+	}
+	if cur != doc {
+		return nil, fmt.Errorf("<%s> is not closed", cur.name)
+	}
+	return doc, nil
+}
+
+// attr returns the value of an attribute by local name, whatever its prefix.
+func (e *xmlElement) attr(name string) string {
+	for _, a := range e.attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child returns the first child element with the name, or nil.
+func (e *xmlElement) child(name string) *xmlElement {
+	for _, c := range e.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// find returns the descendants with the name, in document order, without looking inside them.
+func (e *xmlElement) find(name string) []*xmlElement {
+	var found []*xmlElement
+	for _, c := range e.children {
+		if c.name == name {
+			found = append(found, c)
+		} else {
+			found = append(found, c.find(name)...)
+		}
+	}
+	return found
+}
+
+// content returns the text of an element. Elements with markup inside, such as XLIFF inline codes or HTML tags of
+// Android strings, have their inner XML returned as is, and raw set.
+func (e *xmlElement) content(data []byte) (text string, raw bool) {
+	if len(e.children) > 0 {
+		return string(data[e.inner:e.innerEnd]), true
+	}
+	return e.text, false
+}
+
+// selfClosing tells whether the element is written as <name/>.
+func (e *xmlElement) selfClosing() bool {
+	return e.inner == e.end
+}
+
+// setContent returns the edit replacing the content of an element.
+func (e *xmlElement) setContent(data []byte, content string) edit {
+	if e.selfClosing() {
+		open := strings.TrimRight(strings.TrimSuffix(string(data[e.start:e.end]), ">"), " /")
+		return edit{e.start, e.end, open + ">" + content + "</" + e.name + ">"}
+	}
+	return edit{e.inner, e.innerEnd, content}
+}
+
+// setAttr returns the edit adding an attribute to the start tag of an element.
+func (e *xmlElement) setAttr(data []byte, name, value string) edit {
+	pos := e.inner - 1
+	if e.selfClosing() {
+		pos = bytes.LastIndexByte(data[e.start:e.end], '/') + e.start
+	}
+	return edit{pos, pos, fmt.Sprintf(" %s=\"%s\"", name, xmlEscape(value, true))}
+}
+
+// edit replaces the bytes from start to end with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// splice returns data from start to end with the edits applied. Insertions at the same offset keep their order.
+func splice(data []byte, start, end int, edits []edit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var buf bytes.Buffer
+	pos := start
+	for _, e := range edits {
+		if e.start < pos || e.end > end {
+			continue
+		}
+		buf.Write(data[pos:e.start])
+		buf.WriteString(e.text)
+		pos = e.end
+	}
+	buf.Write(data[pos:end])
+	return buf.Bytes()
+}
+
+// removeLines returns the edit removing the bytes from start to end along with their indentation and line break.
+func removeLines(data []byte, start, end int) edit {
+	i := start
+	for i > 0 && (data[i-1] == ' ' || data[i-1] == '\t') {
+		i--
+	}
+	if i > 0 && data[i-1] == '\n' {
+		start = i - 1
+		if start > 0 && data[start-1] == '\r' {
+			start--
+		}
+	}
+	return edit{start, end, ""}
+}
+
+// indentOf returns the indentation of the line of offset pos, if only whitespace is before it.
+func indentOf(data []byte, pos int) string {
+	i := pos
+	for i > 0 && (data[i-1] == ' ' || data[i-1] == '\t') {
+		i--
+	}
+	if i > 0 && data[i-1] != '\n' {
+		return ""
+	}
+	return string(data[i:pos])
+}
+
+// appendChild returns the edit adding text, which may span several lines, as the last child of parent. Its
+// indentation is the one of the last child, or the one of parent plus unit.
+func appendChild(data []byte, parent *xmlElement, text, unit string) edit {
+	if n := len(parent.children); n > 0 {
+		last := parent.children[n-1]
+		indent := indentOf(data, last.start)
+		return edit{last.end, last.end, "\n" + indent + indentLines(text, indent)}
+	}
+	indent := indentOf(data, parent.start)
+	child := indent + unit + indentLines(text, indent+unit)
+	// Children go on the lines before the end tag, or before the end tag on the line of the start tag.
+	if closing := parent.innerEnd - len(indentOf(data, parent.innerEnd)); closing > parent.inner && data[closing-1] == '\n' {
+		return edit{closing, closing, child + "\n"}
+	}
+	return edit{parent.innerEnd, parent.innerEnd, "\n" + child + "\n" + indent}
+}
+
+// replaceChildren returns the edits replacing the elements, children of one element, by the lines, which are added
+// after the last element with its indentation.
+func replaceChildren(data []byte, elements []*xmlElement, lines []string) []edit {
+	if len(elements) == 0 {
+		return nil
+	}
+	var edits []edit
+	for _, el := range elements {
+		edits = append(edits, removeLines(data, el.start, el.end))
+	}
+	last := elements[len(elements)-1]
+	indent := indentOf(data, last.start)
+	for _, line := range lines {
+		edits = append(edits, edit{last.end, last.end, "\n" + indent + line})
+	}
+	return edits
+}
+
+// copyXML returns data from start to end with the edits applied, unindented by the indentation of start, so that
+// elements of one document can be added to another with appendChild.
+func copyXML(data []byte, start, end int, edits []edit) string {
+	return strings.ReplaceAll(string(splice(data, start, end, edits)), "\n"+indentOf(data, start), "\n")
+}
+
+// indentLines indents the lines of s after the first, as the first is written after an indentation.
+func indentLines(s, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
+// xmlEscape escapes the characters with a meaning in XML text, and quotes in attributes.
+// xmlTag matches the start, end and empty-element tags of markup.
+var xmlTag = regexp.MustCompile(`</?[A-Za-z_][\w.:-]*(\s[^<>]*)?/?>`)
+
+// mapText returns inner XML with f applied to the text between its tags, which are kept as is.
+func mapText(s string, f func(text string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range xmlTag.FindAllStringIndex(s, -1) {
+		b.WriteString(f(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(f(s[last:]))
+	return b.String()
+}
+
+// xmlUnescapeText returns inner XML with the entity and character references of its text resolved.
+func xmlUnescapeText(s string) string {
+	return mapText(s, html.UnescapeString)
+}
+
+func xmlEscape(s string, attr bool) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	if attr {
+		r = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	}
+	return r.Replace(s)
+}
+
+// root returns the top-level element of a document, checking its name.
+func (e *xmlElement) root(name string) (*xmlElement, error) {
+	if len(e.children) == 0 {
+		return nil, errors.New("no root element")
+	}
+	if c := e.children[0]; c.name != name {
+		return nil, fmt.Errorf("the root element is <%s>, expected <%s>", c.name, name)
+	}
+	return e.children[0], nil
+}
@@ -0,0 +1,101 @@
+package l10n
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/trinchan/gengo/l10n/resource"
+)
+
+// Entry is the state of a string in a target locale.
+type Entry struct {
+	// Hash is the hash of the source text the target file's translation was made from.
+	Hash string `json:"hash,omitempty"`
+	// Pending is the job translating the string, until its translation is written back.
+	Pending *Pending `json:"pending,omitempty"`
+}
+
+// Pending is a submitted job whose translation was not written back yet.
+type Pending struct {
+	Hash    string `json:"hash"`
+	OrderID int    `json:"order_id"`
+	JobID   int    `json:"job_id,omitempty"`
+}
+
+// State records what was translated and submitted, by source file, locale and key.
+type State struct {
+	Files map[string]map[string]map[string]*Entry `json:"files"`
+}
+
+// NewState creates an empty state.
+func NewState() *State {
+	return &State{Files: map[string]map[string]map[string]*Entry{}}
+}
+
+// ReadState reads a state file, returning an empty state if it does not exist.
+func ReadState(path string) (*State, error) {
+	s := NewState()
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("reading state %s: %v", path, err)
+	}
+	if s.Files == nil {
+		s.Files = map[string]map[string]map[string]*Entry{}
+	}
+	return s, nil
+}
+
+// Write writes the state through a temporary file, so that an interrupted write does not lose it.
+func (s *State) Write(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := os.WriteFile(tmp, append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Entry returns the entry of a key, or nil.
+func (s *State) Entry(file, locale, key string) *Entry {
+	return s.Files[file][locale][key]
+}
+
+// entry returns the entry of a key, creating it if needed.
+func (s *State) entry(file, locale, key string) *Entry {
+	locales, ok := s.Files[file]
+	if !ok {
+		locales = map[string]map[string]*Entry{}
+		s.Files[file] = locales
+	}
+	keys, ok := locales[locale]
+	if !ok {
+		keys = map[string]*Entry{}
+		locales[locale] = keys
+	}
+	e, ok := keys[key]
+	if !ok {
+		e = new(Entry)
+		keys[key] = e
+	}
+	return e
+}
+
+// Hash returns the content hash of a unit's source text and context, which tells when a string changed.
+func Hash(u resource.Unit) string {
+	h := sha256.Sum256([]byte(u.Context + "\x04" + u.Text))
+	return hex.EncodeToString(h[:8])
+}
@@ -0,0 +1,376 @@
+package l10n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/l10n/resource"
+	"github.com/trinchan/gengo/lang"
+)
+
+// Reasons a string needs translating.
+const (
+	// ReasonNew marks strings missing from the target file.
+	ReasonNew = "new"
+	// ReasonChanged marks strings whose source text changed since they were translated.
+	ReasonChanged = "changed"
+)
+
+// Change is a string of a target locale which needs translating.
+type Change struct {
+	// File is the configured source path of the string's file.
+	File   string        `json:"file"`
+	Locale string        `json:"locale"`
+	Unit   resource.Unit `json:"unit"`
+	Hash   string        `json:"hash"`
+	Reason string        `json:"reason"`
+}
+
+// jobData is the custom data of the jobs of a project, which identifies their string along with their slug.
+type jobData struct {
+	File string `json:"file"`
+	Hash string `json:"hash"`
+}
+
+// Syncer submits the strings of a project and writes their translations back.
+type Syncer struct {
+	Client  *gengo.Client
+	Project *Project
+	State   *State
+}
+
+// NewSyncer creates a new Syncer for the project, reading its state file.
+func NewSyncer(c *gengo.Client, p *Project) (*Syncer, error) {
+	s, err := ReadState(p.path(p.State))
+	if err != nil {
+		return nil, err
+	}
+	return &Syncer{Client: c, Project: p, State: s}, nil
+}
+
+// Status counts the strings of a file in a locale by their state.
+type Status struct {
+	File       string `json:"file"`
+	Locale     string `json:"locale"`
+	Total      int    `json:"total"`
+	Translated int    `json:"translated"`
+	New        int    `json:"new"`
+	Changed    int    `json:"changed"`
+	Pending    int    `json:"pending"`
+}
+
+// Status reports the state of every file in every locale.
+func (s *Syncer) Status() ([]Status, error) {
+	var statuses []Status
+	err := s.scan(func(st *Status, _ *Change) {
+		if st != nil {
+			statuses = append(statuses, *st)
+		}
+	})
+	return statuses, err
+}
+
+// Plan lists the strings which need translating: those missing from their target file, and those whose source
+// text changed since they were translated. Strings with a job for their current text are left out.
+// Strings found translated in target files without a state are assumed up to date, which is recorded in State.
+func (s *Syncer) Plan() ([]Change, error) {
+	var changes []Change
+	err := s.scan(func(_ *Status, c *Change) {
+		if c != nil {
+			changes = append(changes, *c)
+		}
+	})
+	return changes, err
+}
+
+// scan classifies every string of every file and locale, calling fn with each change, and with the status of
+// each file and locale once its strings were classified.
+func (s *Syncer) scan(fn func(st *Status, c *Change)) error {
+	p := s.Project
+	for i, f := range p.Files {
+		codec := p.codecs[i]
+		src, err := p.read(f.SourcePath(p.SourceLocale))
+		if err != nil {
+			return err
+		}
+		if src == nil {
+			return fmt.Errorf("source file %s does not exist", f.SourcePath(p.SourceLocale))
+		}
+		units, err := codec.Decode(src)
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", f.SourcePath(p.SourceLocale), err)
+		}
+		ld, perLocale := codec.(resource.LocaleDecoder)
+		for _, locale := range p.Locales {
+			if perLocale {
+				if units, err = ld.DecodeFor(src, locale); err != nil {
+					return fmt.Errorf("reading %s: %v", f.SourcePath(p.SourceLocale), err)
+				}
+			}
+			translated, err := s.translated(codec, f.TargetPath(locale))
+			if err != nil {
+				return err
+			}
+			st := &Status{File: f.Source, Locale: locale}
+			for _, u := range units {
+				if strings.TrimSpace(u.Text) == "" {
+					continue
+				}
+				st.Total++
+				hash := Hash(u)
+				e := s.State.Entry(f.Source, locale, u.Key)
+				c := &Change{File: f.Source, Locale: locale, Unit: u, Hash: hash}
+				switch {
+				case e != nil && e.Pending != nil && e.Pending.Hash == hash:
+					st.Pending++
+					continue
+				case !translated[u.Key]:
+					c.Reason = ReasonNew
+					st.New++
+				case e == nil || e.Hash == "":
+					s.State.entry(f.Source, locale, u.Key).Hash = hash
+					st.Translated++
+					continue
+				case e.Hash != hash:
+					c.Reason = ReasonChanged
+					st.Changed++
+				default:
+					st.Translated++
+					continue
+				}
+				fn(nil, c)
+			}
+			fn(st, nil)
+		}
+	}
+	return nil
+}
+
+// translated returns the keys with a translation in a target file.
+func (s *Syncer) translated(codec resource.Codec, path string) (map[string]bool, error) {
+	keys := map[string]bool{}
+	b, err := s.Project.read(path)
+	if err != nil || b == nil {
+		return keys, err
+	}
+	decode := codec.Decode
+	if td, ok := codec.(resource.TargetDecoder); ok {
+		decode = td.DecodeTarget
+	}
+	units, err := decode(b)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", path, err)
+	}
+	for _, u := range units {
+		if u.Text != "" {
+			keys[u.Key] = true
+		}
+	}
+	return keys, nil
+}
+
+// Jobs creates the jobs translating the changes. Each job's slug is its key and its custom data identifies its
+// file and the hash of its source text.
+func (s *Syncer) Jobs(changes []Change) ([]*gengo.JobRequest, error) {
+	p := s.Project
+	jobs := make([]*gengo.JobRequest, len(changes))
+	for i, c := range changes {
+		data, err := json.Marshal(jobData{File: c.File, Hash: c.Hash})
+		if err != nil {
+			return nil, err
+		}
+		options := []gengo.JobOption{gengo.WithSlug(c.Unit.Key), gengo.WithCustomData(string(data))}
+		if comment := s.comment(c.Unit); comment != "" {
+			options = append(options, gengo.WithComment(comment))
+		}
+		if c.Unit.MaxChars > 0 {
+			options = append(options, gengo.WithMaxChars(c.Unit.MaxChars))
+		}
+		if p.GlossaryID != 0 {
+			options = append(options, gengo.WithGlossaryID(p.GlossaryID))
+		}
+		pair := lang.NewPair(p.Language(p.SourceLocale), p.Language(c.Locale))
+		jobs[i] = gengo.NewJobRequest(c.Unit.Text, pair, p.Tier, options...)
+	}
+	return jobs, nil
+}
+
+// comment joins the project's comment with the string's comment and context.
+func (s *Syncer) comment(u resource.Unit) string {
+	var parts []string
+	for _, part := range []string{s.Project.Comment, u.Comment} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if u.Context != "" {
+		parts = append(parts, "Context: "+u.Context)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// Submit posts the jobs of the changes as one order and records them as pending in the state file.
+func (s *Syncer) Submit(changes []Change) (*gengo.PostJobsResponse, error) {
+	jobs, err := s.Jobs(changes)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.Client.PostJobs(gengo.NewPostJobsRequest(jobs))
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		s.State.entry(c.File, c.Locale, c.Unit.Key).Pending = &Pending{Hash: c.Hash, OrderID: r.OrderID}
+	}
+	return r, s.save()
+}
+
+// Push plans the changes and submits them, if there are any.
+func (s *Syncer) Push() ([]Change, *gengo.PostJobsResponse, error) {
+	changes, err := s.Plan()
+	if err != nil || len(changes) == 0 {
+		return changes, nil, err
+	}
+	r, err := s.Submit(changes)
+	return changes, r, err
+}
+
+// Translation is a translation written back into a target file.
+type Translation struct {
+	File   string `json:"file"`
+	Locale string `json:"locale"`
+	Key    string `json:"key"`
+	JobID  int    `json:"job_id"`
+}
+
+// PullResult describes what Pull did.
+type PullResult struct {
+	Written []Translation `json:"written"`
+	// Canceled lists the strings whose jobs were canceled, which the next Push submits again.
+	Canceled []Translation `json:"canceled,omitempty"`
+	// Pending counts the strings whose jobs are not approved yet.
+	Pending int `json:"pending"`
+}
+
+// Pull writes the translations of the approved jobs of pending strings into their target files.
+func (s *Syncer) Pull() (*PullResult, error) {
+	p := s.Project
+	orders := map[int]bool{}
+	pending := 0
+	for _, locales := range s.State.Files {
+		for _, keys := range locales {
+			for _, e := range keys {
+				if e.Pending != nil {
+					orders[e.Pending.OrderID] = true
+					pending++
+				}
+			}
+		}
+	}
+	// approved holds the translations to write by file and locale.
+	type fileLocale struct{ file, locale string }
+	approved := map[fileLocale]map[string]string{}
+	done := map[fileLocale][]Translation{}
+	result := &PullResult{}
+	for _, orderID := range sortedIDs(orders) {
+		r, err := s.Client.OrderJobs(gengo.NewOrderJobsRequest(orderID))
+		if err != nil {
+			return nil, fmt.Errorf("retrieving the jobs of order %d: %v", orderID, err)
+		}
+		for _, job := range r.Jobs {
+			var data jobData
+			if json.Unmarshal([]byte(job.CustomData), &data) != nil || data.File == "" {
+				continue
+			}
+			for _, locale := range p.Locales {
+				if p.Language(locale) != job.Target {
+					continue
+				}
+				e := s.State.Entry(data.File, locale, job.Slug)
+				if e == nil || e.Pending == nil || e.Pending.OrderID != orderID || e.Pending.Hash != data.Hash {
+					continue
+				}
+				e.Pending.JobID = int(job.ID)
+				t := Translation{File: data.File, Locale: locale, Key: job.Slug, JobID: int(job.ID)}
+				switch job.Status {
+				case gengo.JobStatusApproved:
+					fl := fileLocale{data.File, locale}
+					if approved[fl] == nil {
+						approved[fl] = map[string]string{}
+					}
+					approved[fl][job.Slug] = job.BodyTgt
+					done[fl] = append(done[fl], t)
+				case gengo.JobStatusCanceled:
+					e.Pending = nil
+					result.Canceled = append(result.Canceled, t)
+					pending--
+				}
+			}
+		}
+	}
+	for i, f := range p.Files {
+		for _, locale := range p.Locales {
+			fl := fileLocale{f.Source, locale}
+			if len(approved[fl]) == 0 {
+				continue
+			}
+			if err := s.write(i, locale, approved[fl]); err != nil {
+				s.save()
+				return result, err
+			}
+			for _, t := range done[fl] {
+				e := s.State.Entry(t.File, t.Locale, t.Key)
+				e.Hash, e.Pending = e.Pending.Hash, nil
+				pending--
+			}
+			result.Written = append(result.Written, done[fl]...)
+		}
+	}
+	result.Pending = pending
+	return result, s.save()
+}
+
+// write merges translations into the target file of a locale.
+func (s *Syncer) write(file int, locale string, translations map[string]string) error {
+	p := s.Project
+	f := p.Files[file]
+	src, err := p.read(f.SourcePath(p.SourceLocale))
+	if err != nil {
+		return err
+	}
+	if src == nil {
+		return fmt.Errorf("source file %s does not exist", f.SourcePath(p.SourceLocale))
+	}
+	target := f.TargetPath(locale)
+	tgt, err := p.read(target)
+	if err != nil {
+		return err
+	}
+	b, err := p.codecs[file].Merge(src, tgt, locale, translations)
+	if err != nil {
+		return fmt.Errorf("writing %s: %v", target, err)
+	}
+	path := p.path(target)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+func (s *Syncer) save() error {
+	return s.State.Write(s.Project.path(s.Project.State))
+}
+
+func sortedIDs(ids map[int]bool) []int {
+	sorted := make([]int, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Ints(sorted)
+	return sorted
+}
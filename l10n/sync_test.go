@@ -0,0 +1,185 @@
+package l10n
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/internal/gengotest"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestLoadProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "gengo-l10n.json", `{"source_locale":"en","locales":["ja"],"files":[{"source":"locales/{locale}.json"}]}`)
+	p, err := LoadProject(filepath.Join(dir, "gengo-l10n.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Tier != gengo.TierStandard || p.State != DefaultStateFile || p.Language("ja") != "ja" {
+		t.Errorf("unexpected project %+v", p)
+	}
+	for _, c := range []Config{
+		{SourceLocale: "en", Files: []File{{Source: "en.json"}}},
+		{SourceLocale: "en", Locales: []string{"xx-nope"}, Files: []File{{Source: "{locale}.json"}}},
+		{SourceLocale: "en", Locales: []string{"ja"}, Files: []File{{Source: "en.json"}}},
+		{SourceLocale: "en", Locales: []string{"ja"}, Files: []File{{Source: "{locale}.txt"}}},
+	} {
+		if _, err := NewProject(c, dir); err == nil {
+			t.Errorf("NewProject(%+v) succeeded", c)
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "locales/en.json", `{
+  "title": "Welcome",
+  "menu": {
+    "open": "Open",
+    "close": "Close"
+  }
+}
+`)
+	writeFile(t, dir, "locales/ja.json", `{
+  "title": "ようこそ"
+}
+`)
+	p, err := NewProject(Config{
+		SourceLocale: "en",
+		Locales:      []string{"ja"},
+		Files:        []File{{Source: "locales/{locale}.json"}},
+		GlossaryID:   9,
+		Comment:      "Menu of a mobile app.",
+	}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := `[
+		{"job_id":"11","slug":"menu.open","status":"approved","lc_src":"en","lc_tgt":"ja","body_tgt":"開く","custom_data":"{\"file\":\"locales/{locale}.json\",\"hash\":\"HASH_OPEN\"}"},
+		{"job_id":"12","slug":"menu.close","status":"reviewable","lc_src":"en","lc_tgt":"ja","body_tgt":"閉","custom_data":"{\"file\":\"locales/{locale}.json\",\"hash\":\"HASH_CLOSE\"}"}]`
+	c := gengo.New("public", "private", gengo.SandboxBaseURL)
+	api := gengotest.New(map[string]string{
+		"POST /translate/jobs":   `{"order_id":7,"job_count":2,"credits_used":"0.40","currency":"USD"}`,
+		"GET /translate/order/7": `{"order":{"order_id":"7","jobs_approved":["11"],"jobs_reviewable":["12"]}}`,
+	})
+	api.HandleFunc("GET /translate/jobs/12,11", func(*http.Request) string { return `{"jobs":` + jobs + `}` })
+	c.SetRoundTripper(api)
+
+	s, err := NewSyncer(c, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, r, err := s.Push()
+	if err != nil {
+		t.Fatal(err)
+	}
+	posted := api.Form(0).Get("data")
+	if len(changes) != 2 || changes[0].Unit.Key != "menu.open" || changes[0].Reason != ReasonNew || changes[1].Unit.Key != "menu.close" || r.OrderID != 7 {
+		t.Fatalf("Push() = %+v, %+v", changes, r)
+	}
+	for _, want := range []string{`"slug":"menu.open"`, `"glossary_id":9`, `"comment":"Menu of a mobile app."`, `\"hash\":\"` + changes[0].Hash} {
+		if !strings.Contains(posted, want) {
+			t.Errorf("posted jobs %s do not contain %s", posted, want)
+		}
+	}
+	if e := s.State.Entry("locales/{locale}.json", "ja", "title"); e == nil || e.Hash == "" {
+		t.Errorf("existing translation was not recorded: %+v", e)
+	}
+
+	// Strings with a pending job are not submitted again.
+	s, err = NewSyncer(c, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes, err := s.Plan(); err != nil || len(changes) != 0 {
+		t.Errorf("Plan() after Push() = %+v, %v", changes, err)
+	}
+
+	jobs = strings.NewReplacer("HASH_OPEN", changes[0].Hash, "HASH_CLOSE", changes[1].Hash).Replace(jobs)
+	pr, err := s.Pull()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.Written) != 1 || pr.Written[0].Key != "menu.open" || pr.Written[0].JobID != 11 || pr.Pending != 1 {
+		t.Errorf("Pull() = %+v", pr)
+	}
+	want := `{
+  "title": "ようこそ",
+  "menu": {
+    "open": "開く"
+  }
+}
+`
+	if got := readFile(t, dir, "locales/ja.json"); got != want {
+		t.Errorf("ja.json =\n%s\nwant\n%s", got, want)
+	}
+
+	// A changed source string is submitted again.
+	writeFile(t, dir, "locales/en.json", `{"title": "Welcome!", "menu": {"open": "Open", "close": "Close"}}`)
+	s, err = NewSyncer(c, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err = s.Plan()
+	if err != nil || len(changes) != 1 || changes[0].Unit.Key != "title" || changes[0].Reason != ReasonChanged {
+		t.Errorf("Plan() after a change = %+v, %v", changes, err)
+	}
+	statuses, err := s.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 || statuses[0] != (Status{File: "locales/{locale}.json", Locale: "ja", Total: 3, Translated: 1, Changed: 1, Pending: 1}) {
+		t.Errorf("Status() = %+v", statuses)
+	}
+}
+
+func TestSyncPOPlurals(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "po/en.po", `msgid "Open"
+msgstr ""
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+`)
+	p, err := NewProject(Config{SourceLocale: "en", Locales: []string{"ja", "ru"}, Files: []File{{Source: "po/{locale}.po"}}}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSyncer(gengo.New("public", "private", gengo.SandboxBaseURL), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := s.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].Total != 2 || statuses[1].Total != 4 {
+		t.Errorf("Status() = %+v, want a string per plural form of ja and ru", statuses)
+	}
+}
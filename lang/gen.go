@@ -0,0 +1,81 @@
+//go:build ignore
+
+// gen.go regenerates tables.go from the Gengo Languages() endpoint.
+//
+// Run it with GENGO_PUBLIC_KEY and GENGO_PRIVATE_KEY set:
+//
+//	go generate ./lang
+//
+// It reports languages Gengo added or removed since the table was last generated.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+
+	"golang.org/x/text/language"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+)
+
+// tags maps Gengo codes which are not the BCP 47 tag of the language to the BCP 47 tag.
+var tags = map[lang.Code]string{
+	lang.BritishEnglish:       "en-GB",
+	lang.LatinAmericanSpanish: "es-419",
+	lang.FrenchCanadian:       "fr-CA",
+	lang.EuropeanPortuguese:   "pt-PT",
+	lang.BrazilianPortuguese:  "pt-BR",
+	lang.SimplifiedChinese:    "zh-Hans",
+	lang.TraditionalChinese:   "zh-Hant",
+}
+
+func main() {
+	g := gengo.NewFromEnv()
+	r, err := g.Languages()
+	if err != nil {
+		log.Fatalf("retrieving languages: %v", err)
+	}
+	sort.Slice(r.Languages, func(i, j int) bool { return r.Languages[i].Code < r.Languages[j].Code })
+
+	known := map[lang.Code]bool{}
+	for _, c := range lang.Codes() {
+		known[c] = true
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\npackage lang\n\nimport \"golang.org/x/text/language\"\n\nvar languages = []Language{\n")
+	for _, l := range r.Languages {
+		if !known[l.Code] {
+			log.Printf("added %s (%s): consider adding a constant to language.go", l.Code, l.Name)
+		}
+		delete(known, l.Code)
+		tag, ok := tags[l.Code]
+		if !ok {
+			tag = string(l.Code)
+		}
+		if _, err := language.Parse(tag); err != nil {
+			log.Fatalf("%s has no valid BCP 47 tag, add it to tags in gen.go: %v", l.Code, err)
+		}
+		unit := "UnitWord"
+		if l.UnitType == lang.UnitCharacter {
+			unit = "UnitCharacter"
+		}
+		fmt.Fprintf(buf, "\t{Code: %q, Name: %q, LocalizedName: %q, UnitType: %s, Tag: language.MustParse(%q)},\n",
+			l.Code, l.Name, l.LocalizedName, unit, tag)
+	}
+	buf.WriteString("}\n")
+	for c := range known {
+		log.Printf("removed %s: Gengo no longer supports it", c)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting tables.go: %v", err)
+	}
+	if err := os.WriteFile("tables.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,5 +1,11 @@
 package lang
 
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
 // Code is a language code used by Gengo.
 type Code string
 
@@ -29,9 +35,9 @@ const (
 	// FrenchCanadian language code
 	FrenchCanadian = Code("fr-ca")
 	// German language code
-	German = Code("el")
+	German = Code("de")
 	// Greek language code
-	Greek = Code("gr")
+	Greek = Code("el")
 	// Hebrew language code
 	Hebrew = Code("he")
 	// Hungarian language code
@@ -90,3 +96,30 @@ type Pair struct {
 func NewPair(source, target Code) Pair {
 	return Pair{Source: source, Target: target}
 }
+
+// NewPairFromTags creates a new language pair from the closest Gengo languages to the source and target tags
+func NewPairFromTags(source, target language.Tag) (Pair, error) {
+	s, err := FromTag(source)
+	if err != nil {
+		return Pair{}, err
+	}
+	t, err := FromTag(target)
+	if err != nil {
+		return Pair{}, err
+	}
+	return NewPair(s, t), nil
+}
+
+// Validate checks that both languages of the pair are known to Gengo and differ
+func (p Pair) Validate() error {
+	if !p.Source.Valid() {
+		return fmt.Errorf("unknown source language %q", p.Source)
+	}
+	if !p.Target.Valid() {
+		return fmt.Errorf("unknown target language %q", p.Target)
+	}
+	if p.Source == p.Target {
+		return fmt.Errorf("source and target language are both %q", p.Source)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package lang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+//go:generate go run gen.go
+
+// UnitType is the unit Gengo counts and prices a language's text in.
+type UnitType string
+
+const (
+	// UnitWord languages are counted in words.
+	UnitWord = UnitType("word")
+	// UnitCharacter languages, which do not separate words with spaces, are counted in characters.
+	UnitCharacter = UnitType("character")
+)
+
+// Language describes a language supported by Gengo.
+type Language struct {
+	Code          Code
+	Name          string
+	LocalizedName string
+	UnitType      UnitType
+	Tag           language.Tag
+}
+
+// baseAliases maps the base languages of tags which Gengo treats as the same language.
+var baseAliases = map[string]Code{
+	"nb": Norwegian,
+	"nn": Norwegian,
+}
+
+var (
+	byCode  = map[Code]*Language{}
+	byTag   = map[language.Tag]Code{}
+	matcher language.Matcher
+)
+
+func init() {
+	tags := make([]language.Tag, len(languages))
+	for i := range languages {
+		l := &languages[i]
+		byCode[l.Code] = l
+		byTag[l.Tag] = l.Code
+		tags[i] = l.Tag
+	}
+	matcher = language.NewMatcher(tags)
+}
+
+// Lookup returns the language with the given code.
+func Lookup(c Code) (Language, bool) {
+	l, ok := byCode[c]
+	if !ok {
+		return Language{}, false
+	}
+	return *l, true
+}
+
+// Languages returns every language supported by Gengo, sorted by code.
+func Languages() []Language {
+	ls := make([]Language, len(languages))
+	copy(ls, languages)
+	sort.Slice(ls, func(i, j int) bool { return ls[i].Code < ls[j].Code })
+	return ls
+}
+
+// Codes returns the code of every language supported by Gengo, sorted.
+func Codes() []Code {
+	ls := Languages()
+	codes := make([]Code, len(ls))
+	for i, l := range ls {
+		codes[i] = l.Code
+	}
+	return codes
+}
+
+// Valid reports whether the code is a language supported by Gengo.
+func (c Code) Valid() bool {
+	_, ok := byCode[c]
+	return ok
+}
+
+// Name returns the English name of the language, or the code itself if it is unknown.
+func (c Code) Name() string {
+	if l, ok := byCode[c]; ok {
+		return l.Name
+	}
+	return string(c)
+}
+
+// LocalizedName returns the name of the language in that language, or the code itself if it is unknown.
+func (c Code) LocalizedName() string {
+	if l, ok := byCode[c]; ok {
+		return l.LocalizedName
+	}
+	return string(c)
+}
+
+// UnitType returns the unit the language is counted in. Unknown languages are counted in words.
+func (c Code) UnitType() UnitType {
+	if l, ok := byCode[c]; ok {
+		return l.UnitType
+	}
+	return UnitWord
+}
+
+// Tag returns the BCP 47 tag of the language, or language.Und if it is unknown.
+func (c Code) Tag() language.Tag {
+	if l, ok := byCode[c]; ok {
+		return l.Tag
+	}
+	return language.Und
+}
+
+// FromTag returns the Gengo language which best matches the BCP 47 tag,
+// so that en-US maps to English and zh-TW to TraditionalChinese.
+func FromTag(t language.Tag) (Code, error) {
+	if c, ok := byTag[t]; ok {
+		return c, nil
+	}
+	base, _ := t.Base()
+	if c, ok := baseAliases[base.String()]; ok {
+		return c, nil
+	}
+	_, i, confidence := matcher.Match(t)
+	matched, _ := languages[i].Tag.Base()
+	if confidence < language.Exact && (confidence < language.High || base != matched) {
+		return "", fmt.Errorf("no Gengo language matches %s", t)
+	}
+	return languages[i].Code, nil
+}
+
+// Parse returns the Gengo language for a Gengo language code or BCP 47 tag, ignoring case.
+func Parse(s string) (Code, error) {
+	s = strings.TrimSpace(s)
+	if c := Code(strings.ToLower(s)); c.Valid() {
+		return c, nil
+	}
+	t, err := language.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("unknown language %q", s)
+	}
+	return FromTag(t)
+}
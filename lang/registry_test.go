@@ -0,0 +1,92 @@
+package lang
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestRegistry(t *testing.T) {
+	for _, l := range Languages() {
+		if !l.Code.Valid() || l.Name == "" || l.LocalizedName == "" {
+			t.Errorf("%s is incomplete: %+v", l.Code, l)
+		}
+		if l.UnitType != UnitWord && l.UnitType != UnitCharacter {
+			t.Errorf("%s has unknown unit type %q", l.Code, l.UnitType)
+		}
+		c, err := FromTag(l.Code.Tag())
+		if err != nil || c != l.Code {
+			t.Errorf("FromTag(%s) = %s, %v, want %s", l.Code.Tag(), c, err, l.Code)
+		}
+	}
+}
+
+func TestConstants(t *testing.T) {
+	tests := []struct {
+		code Code
+		name string
+		unit UnitType
+	}{
+		{German, "German", UnitWord},
+		{Greek, "Greek", UnitWord},
+		{Japanese, "Japanese", UnitCharacter},
+		{LatinAmericanSpanish, "Spanish (Latin America)", UnitWord},
+		{TraditionalChinese, "Chinese (Traditional)", UnitCharacter},
+	}
+	for _, tt := range tests {
+		if tt.code.Name() != tt.name || tt.code.UnitType() != tt.unit {
+			t.Errorf("%s = %s (%s), want %s (%s)", tt.code, tt.code.Name(), tt.code.UnitType(), tt.name, tt.unit)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		code Code
+		ok   bool
+	}{
+		{"ja", Japanese, true},
+		{"PT-BR", BrazilianPortuguese, true},
+		{"en-US", English, true},
+		{"en-GB", BritishEnglish, true},
+		{"es-MX", LatinAmericanSpanish, true},
+		{"zh-CN", SimplifiedChinese, true},
+		{"zh-TW", TraditionalChinese, true},
+		{"zh-Hant-HK", TraditionalChinese, true},
+		{"de-AT", German, true},
+		{"nb-NO", Norwegian, true},
+		{"fil", Tagalog, true},
+		{"iw", Hebrew, true},
+		{"gr", "", false},
+		{"xx-invalid-tag", "", false},
+		{"sw", "", false},
+	}
+	for _, tt := range tests {
+		c, err := Parse(tt.in)
+		if (err == nil) != tt.ok || c != tt.code {
+			t.Errorf("Parse(%q) = %q, %v, want %q", tt.in, c, err, tt.code)
+		}
+	}
+}
+
+func TestPair(t *testing.T) {
+	p, err := NewPairFromTags(language.AmericanEnglish, language.Japanese)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != NewPair(English, Japanese) {
+		t.Errorf("NewPairFromTags() = %v", p)
+	}
+	if err := p.Validate(); err != nil {
+		t.Error(err)
+	}
+	for _, p := range []Pair{NewPair(English, English), NewPair("gr", English), NewPair(English, "")} {
+		if p.Validate() == nil {
+			t.Errorf("%v should be invalid", p)
+		}
+	}
+	if Code("xx").UnitType() != UnitWord || Code("xx").Tag() != language.Und {
+		t.Error("unknown codes should be counted in words and have an undefined tag")
+	}
+}
@@ -0,0 +1,38 @@
+package lang_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+)
+
+// TestTableMatchesGengo checks the generated table against the live Languages() endpoint.
+// It only runs when GENGO_PUBLIC_KEY and GENGO_PRIVATE_KEY are set; run go generate ./lang to fix it.
+func TestTableMatchesGengo(t *testing.T) {
+	if os.Getenv("GENGO_PUBLIC_KEY") == "" || os.Getenv("GENGO_PRIVATE_KEY") == "" {
+		t.Skip("GENGO_PUBLIC_KEY and GENGO_PRIVATE_KEY are not set")
+	}
+	r, err := gengo.NewFromEnv().Languages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := map[lang.Code]gengo.Language{}
+	for _, l := range r.Languages {
+		remote[l.Code] = l
+		if !l.Code.Valid() {
+			t.Errorf("Gengo supports %s (%s), which is missing from the table", l.Code, l.Name)
+		}
+	}
+	for _, l := range lang.Languages() {
+		rl, ok := remote[l.Code]
+		if !ok {
+			t.Errorf("%s is no longer supported by Gengo", l.Code)
+			continue
+		}
+		if rl.UnitType != l.UnitType {
+			t.Errorf("%s is counted in %s, the table says %s", l.Code, rl.UnitType, l.UnitType)
+		}
+	}
+}
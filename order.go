@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+
+	"github.com/trinchan/gengo/currency"
 )
 
 const (
@@ -17,17 +19,103 @@ type OrderGetResponse struct {
 
 // Order defines an order for the OrderGetResponse.
 type Order struct {
-	JobsQueued     Int     `json:"jobs_queued"`
-	JobsReviewable []Int   `json:"jobs_reviewable"`
-	JobsAvailable  []Int   `json:"jobs_available"`
-	JobsPending    []Int   `json:"jobs_pending"`
-	JobsApproved   []Int   `json:"jobs_approved"`
-	JobsRevising   []Int   `json:"jobs_revising"`
-	OrderID        Int     `json:"order_id"`
-	Credits        Float64 `json:"total_credits"`
-	Units          Int     `json:"total_units"`
-	Count          Int     `json:"total_jobs"`
-	Currency       string  `json:"currency"`
+	JobsQueued     Int            `json:"jobs_queued"`
+	JobsReviewable []Int          `json:"jobs_reviewable"`
+	JobsAvailable  []Int          `json:"jobs_available"`
+	JobsPending    []Int          `json:"jobs_pending"`
+	JobsApproved   []Int          `json:"jobs_approved"`
+	JobsRevising   []Int          `json:"jobs_revising"`
+	JobsHeld       []Int          `json:"jobs_held"`
+	JobsCancelled  []Int          `json:"jobs_cancelled"`
+	OrderID        Int            `json:"order_id"`
+	Credits        currency.Money `json:"total_credits"`
+	Units          Int            `json:"total_units"`
+	Count          Int            `json:"total_jobs"`
+	Currency       currency.Code  `json:"currency"`
+}
+
+// UnmarshalJSON implements the Unmarshaler interface so that Credits carries the order's currency.
+func (o *Order) UnmarshalJSON(d []byte) error {
+	type order Order
+	x := new(order)
+	if err := json.Unmarshal(d, x); err != nil {
+		return err
+	}
+	*o = Order(*x)
+	o.Credits = o.Credits.In(o.Currency)
+	return nil
+}
+
+// OrderStatus describes the aggregate state of the jobs in an order.
+type OrderStatus string
+
+const (
+	// OrderStatusEmpty marks an order without any jobs.
+	OrderStatusEmpty = OrderStatus("empty")
+	// OrderStatusQueued marks an order with jobs still being processed by Gengo.
+	OrderStatusQueued = OrderStatus("queued")
+	// OrderStatusHeld marks an order with jobs put on hold by Gengo.
+	OrderStatusHeld = OrderStatus("held")
+	// OrderStatusAvailable marks an order with jobs waiting for a translator.
+	OrderStatusAvailable = OrderStatus("available")
+	// OrderStatusPending marks an order with jobs being translated.
+	OrderStatusPending = OrderStatus("pending")
+	// OrderStatusRevising marks an order with jobs being revised.
+	OrderStatusRevising = OrderStatus("revising")
+	// OrderStatusReviewable marks an order with jobs waiting for review.
+	OrderStatusReviewable = OrderStatus("reviewable")
+	// OrderStatusApproved marks an order in which every job still active is approved.
+	OrderStatusApproved = OrderStatus("approved")
+	// OrderStatusCanceled marks an order in which every job was canceled.
+	OrderStatusCanceled = OrderStatus("canceled")
+)
+
+// Status reports the state of the least advanced job in the order, ignoring canceled jobs unless there are no others.
+func (o *Order) Status() OrderStatus {
+	switch {
+	case o.JobsQueued > 0:
+		return OrderStatusQueued
+	case len(o.JobsHeld) > 0:
+		return OrderStatusHeld
+	case len(o.JobsAvailable) > 0:
+		return OrderStatusAvailable
+	case len(o.JobsPending) > 0:
+		return OrderStatusPending
+	case len(o.JobsRevising) > 0:
+		return OrderStatusRevising
+	case len(o.JobsReviewable) > 0:
+		return OrderStatusReviewable
+	case len(o.JobsApproved) > 0:
+		return OrderStatusApproved
+	case len(o.JobsCancelled) > 0:
+		return OrderStatusCanceled
+	}
+	return OrderStatusEmpty
+}
+
+// Progress returns the percentage of the jobs in the order which have been approved, leaving out canceled jobs.
+func (o *Order) Progress() float64 {
+	total := int(o.Count)
+	if total == 0 {
+		total = len(o.AllJobIDs()) + int(o.JobsQueued)
+	}
+	total -= len(o.JobsCancelled)
+	if total <= 0 {
+		return 0
+	}
+	return float64(len(o.JobsApproved)) / float64(total) * 100
+}
+
+// AllJobIDs returns the ids of every job in the order which has left the queue, including held and canceled jobs.
+func (o *Order) AllJobIDs() []int {
+	var ids []int
+	for _, group := range [][]Int{o.JobsAvailable, o.JobsPending, o.JobsRevising, o.JobsReviewable, o.JobsApproved,
+		o.JobsHeld, o.JobsCancelled} {
+		for _, id := range group {
+			ids = append(ids, int(id))
+		}
+	}
+	return ids
 }
 
 // OrderGetRequest defines the request parameters for the OrderGet() endpoint.
@@ -128,3 +216,97 @@ func (c *Client) AddOrderComment(req *AddOrderCommentRequest) error {
 	err = c.post(orderNamespace+fmt.Sprintf("/%d/comment", req.OrderID), bytes.NewReader(b), nil)
 	return err
 }
+
+// OrderJobsRequest defines the request parameters for the OrderJobs() helper.
+type OrderJobsRequest struct {
+	OrderID int
+}
+
+// NewOrderJobsRequest creates a new OrderJobsRequest with the given id.
+func NewOrderJobsRequest(orderID int) *OrderJobsRequest {
+	ojr := &OrderJobsRequest{
+		OrderID: orderID,
+	}
+	return ojr
+}
+
+// OrderJobs retrieves every job in an order which has left the queue.
+func (c *Client) OrderJobs(req *OrderJobsRequest) (*GetJobsByIDResponse, error) {
+	ogr, err := c.GetOrder(NewOrderGetRequest(req.OrderID))
+	if err != nil {
+		return nil, err
+	}
+	return c.GetJobsByID(NewGetJobsByIDRequest(ogr.Order.AllJobIDs()...))
+}
+
+// OrderActionResponse defines the response from the ApproveOrder() and ArchiveOrder() helpers.
+type OrderActionResponse struct {
+	JobIDs []int
+}
+
+// ApproveOrderRequest defines the request parameters for the ApproveOrder() helper.
+type ApproveOrderRequest struct {
+	OrderID int
+	Options []ApproveJobOption
+}
+
+// NewApproveOrderRequest creates a new ApproveOrderRequest with the given id.
+// The options are applied to every approved job.
+func NewApproveOrderRequest(orderID int, options ...ApproveJobOption) *ApproveOrderRequest {
+	aor := &ApproveOrderRequest{
+		OrderID: orderID,
+		Options: options,
+	}
+	return aor
+}
+
+// ApproveOrder approves every reviewable job in an order.
+func (c *Client) ApproveOrder(req *ApproveOrderRequest) (*OrderActionResponse, error) {
+	ogr, err := c.GetOrder(NewOrderGetRequest(req.OrderID))
+	if err != nil {
+		return nil, err
+	}
+	oar := new(OrderActionResponse)
+	jobs := make([]*ApproveJobRequest, 0, len(ogr.Order.JobsReviewable))
+	for _, id := range ogr.Order.JobsReviewable {
+		jobs = append(jobs, NewApproveJobRequest(int(id), req.Options...))
+		oar.JobIDs = append(oar.JobIDs, int(id))
+	}
+	if len(jobs) == 0 {
+		return oar, nil
+	}
+	err = c.ApproveJobs(NewApproveJobsRequest(jobs...))
+	return oar, err
+}
+
+// ArchiveOrderRequest defines the request parameters for the ArchiveOrder() helper.
+type ArchiveOrderRequest struct {
+	OrderID int
+}
+
+// NewArchiveOrderRequest creates a new ArchiveOrderRequest with the given id.
+func NewArchiveOrderRequest(orderID int) *ArchiveOrderRequest {
+	aor := &ArchiveOrderRequest{
+		OrderID: orderID,
+	}
+	return aor
+}
+
+// ArchiveOrder archives every approved job in an order.
+func (c *Client) ArchiveOrder(req *ArchiveOrderRequest) (*OrderActionResponse, error) {
+	ogr, err := c.GetOrder(NewOrderGetRequest(req.OrderID))
+	if err != nil {
+		return nil, err
+	}
+	oar := new(OrderActionResponse)
+	jobs := make([]*ArchiveJobRequest, 0, len(ogr.Order.JobsApproved))
+	for _, id := range ogr.Order.JobsApproved {
+		jobs = append(jobs, NewArchiveJobRequest(int(id)))
+		oar.JobIDs = append(oar.JobIDs, int(id))
+	}
+	if len(jobs) == 0 {
+		return oar, nil
+	}
+	err = c.ArchiveJobs(NewArchiveJobsRequest(jobs...))
+	return oar, err
+}
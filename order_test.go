@@ -0,0 +1,123 @@
+package gengo
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestOrderStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    Order
+		status   OrderStatus
+		progress float64
+	}{
+		{"empty", Order{}, OrderStatusEmpty, 0},
+		{"queued", Order{JobsQueued: 2, Count: 2}, OrderStatusQueued, 0},
+		{"mixed", Order{JobsPending: []Int{1}, JobsReviewable: []Int{2}, JobsApproved: []Int{3, 4}, Count: 4}, OrderStatusPending, 50},
+		{"reviewable", Order{JobsReviewable: []Int{2}, JobsApproved: []Int{3}}, OrderStatusReviewable, 50},
+		{"approved", Order{JobsApproved: []Int{3, 4}, Count: 2}, OrderStatusApproved, 100},
+		{"held", Order{JobsHeld: []Int{1}, JobsApproved: []Int{2}, Count: 2}, OrderStatusHeld, 50},
+		{"partly canceled", Order{JobsApproved: []Int{1}, JobsCancelled: []Int{2}, Count: 2}, OrderStatusApproved, 100},
+		{"canceled", Order{JobsCancelled: []Int{1, 2}, Count: 2}, OrderStatusCanceled, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s := tt.order.Status(); s != tt.status {
+				t.Errorf("Status() = %s, want %s", s, tt.status)
+			}
+			if p := tt.order.Progress(); p != tt.progress {
+				t.Errorf("Progress() = %v, want %v", p, tt.progress)
+			}
+		})
+	}
+}
+
+func TestOrderAllJobIDs(t *testing.T) {
+	o := Order{
+		JobsAvailable:  []Int{1},
+		JobsPending:    []Int{2},
+		JobsRevising:   []Int{3},
+		JobsReviewable: []Int{4},
+		JobsApproved:   []Int{5, 6},
+		JobsHeld:       []Int{7},
+		JobsCancelled:  []Int{8},
+	}
+	if ids := o.AllJobIDs(); !reflect.DeepEqual(ids, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("AllJobIDs() = %v", ids)
+	}
+}
+
+const testOrder = `{"order":{"order_id":"7","jobs_reviewable":["11","12"],"jobs_approved":["13"],"jobs_pending":[],"total_jobs":"3"}}`
+
+func TestApproveOrder(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("GET /translate/order/7", testOrder)
+	api.Handle("PUT /translate/jobs", `{}`)
+	r, err := c.ApproveOrder(NewApproveOrderRequest(7, WithRating(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.JobIDs, []int{11, 12}) {
+		t.Errorf("approved %v, want [11 12]", r.JobIDs)
+	}
+	var sent ApproveJobsRequest
+	api.Data(t, 1, &sent)
+	if sent.Action != "approve" || len(sent.Jobs) != 2 || *sent.Jobs[1].Rating != 5 {
+		t.Errorf("unexpected approve request %+v", sent)
+	}
+}
+
+func TestArchiveOrderWithoutApprovedJobs(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("GET /translate/order/8", `{"order":{"order_id":"8","jobs_pending":["1"]}}`)
+	r, err := c.ArchiveOrder(NewArchiveOrderRequest(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.JobIDs) != 0 || api.Len() != 1 {
+		t.Errorf("expected no archive call, got %v after %d requests", r.JobIDs, api.Len())
+	}
+}
+
+func TestOrderJobs(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("GET /translate/order/7", testOrder)
+	api.Handle("GET /translate/jobs/11,12,13", `{"jobs":[{"job_id":"11"},{"job_id":"12"},{"job_id":"13"}]}`)
+	r, err := c.OrderJobs(NewOrderJobsRequest(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Jobs) != 3 || r.Jobs[2].ID != 13 {
+		t.Errorf("unexpected jobs %+v", r.Jobs)
+	}
+}
+
+func TestOrderJobsBatches(t *testing.T) {
+	c, api := newFakeClient()
+	var ids, first, second []string
+	for id := 1; id <= 60; id++ {
+		ids = append(ids, fmt.Sprintf("%q", strconv.Itoa(id)))
+		if id <= 50 {
+			first = append(first, strconv.Itoa(id))
+		} else {
+			second = append(second, strconv.Itoa(id))
+		}
+	}
+	api.Handle("GET /translate/order/7", `{"order":{"order_id":"7","jobs_pending":[`+strings.Join(ids, ",")+`]}}`)
+	api.Handle("GET /translate/jobs/"+strings.Join(first, ","), `{"jobs":[{"job_id":"1"}]}`)
+	api.Handle("GET /translate/jobs/"+strings.Join(second, ","), `{"jobs":[{"job_id":"51"}]}`)
+	r, err := c.OrderJobs(NewOrderJobsRequest(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Jobs) != 2 || r.Jobs[1].ID != 51 || api.Len() != 3 {
+		t.Errorf("OrderJobs() = %+v after %d requests", r.Jobs, api.Len())
+	}
+	if _, err := c.GetJobsByID(NewGetJobsByIDRequest()); err != nil || api.Len() != 3 {
+		t.Errorf("GetJobsByID() of no jobs = %v after %d requests", err, api.Len())
+	}
+}
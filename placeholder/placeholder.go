@@ -0,0 +1,419 @@
+// Package placeholder protects the placeholders and markup of text jobs from translators. Before submission, they
+// are wrapped in [[[ ]]], which Gengo shows as untranslatable and leaves out of unit counts. Once translated, the
+// markers are removed and the translation is checked to have the placeholders of its source.
+package placeholder
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/trinchan/gengo"
+)
+
+// Open and Close are the markers of untranslatable text in Gengo jobs.
+const (
+	Open  = "[[["
+	Close = "]]]"
+)
+
+// Kind is a kind of placeholder.
+type Kind string
+
+const (
+	// Printf is a printf style placeholder, such as %s, %1$d or %@.
+	Printf Kind = "printf"
+	// Named is a named placeholder, such as {name}, {0}, {{count}}, %{name} or ${name}.
+	Named Kind = "named"
+	// ICU is the header of an ICU plural or select argument, such as "{count, plural,", or the # of its messages.
+	// The selectors of the messages are not placeholders, as translations have those of their language.
+	ICU Kind = "icu"
+	// Markup is an HTML or XML tag or entity, such as <b> or &amp;.
+	Markup Kind = "markup"
+)
+
+// Token is a placeholder or piece of markup of a text, from byte offset Start to End.
+type Token struct {
+	Text       string
+	Kind       Kind
+	Start, End int
+}
+
+var (
+	doubleBrace = regexp.MustCompile(`^\{\{[^{}]*\}\}`)
+	printf      = regexp.MustCompile(`^%(?:\d+\$)?[-+#0]*\d*(?:\.\d+)?(?:hh|h|ll|l|q|z|t|j)?[sdiufFeEgGxXoScCpa@%]`)
+	percentName = regexp.MustCompile(`^%\{[\w.]+\}`)
+	dollarName  = regexp.MustCompile(`^\$\{[\w.]+\}`)
+	tag         = regexp.MustCompile(`^</?[A-Za-z][\w:.-]*(?:\s[^<>]*)?/?>`)
+	entity      = regexp.MustCompile(`^&(?:[A-Za-z]+|#\d+|#x[0-9A-Fa-f]+);`)
+	identifier  = regexp.MustCompile(`^\w+`)
+	selector    = regexp.MustCompile(`^(?:=\d+|\w+)`)
+	offset      = regexp.MustCompile(`^offset:\s*\d+`)
+)
+
+// scanner finds the tokens of a text.
+type scanner struct {
+	s      string
+	tokens []Token
+	// cur is the message being scanned.
+	cur *message
+}
+
+// message is a message of a text, or of an ICU argument, with its placeholders besides the ICU arguments.
+type message struct {
+	tokens []Token
+	args   []*argument
+}
+
+// argument is an ICU plural or select argument, identified by its name and type such as "{count, plural}".
+type argument struct {
+	key      string
+	branches []branch
+}
+
+type branch struct {
+	selector string
+	message  *message
+}
+
+// branch returns the message of the argument translating the one of a selector: the message of the same
+// selector, or else of "other", or nil.
+func (a *argument) branch(selector string) *message {
+	var other *message
+	for _, b := range a.branches {
+		if b.selector == selector {
+			return b.message
+		}
+		if b.selector == "other" {
+			other = b.message
+		}
+	}
+	return other
+}
+
+// Scan returns the placeholders and markup of s in order. ICU plural and select arguments are split into their
+// header, which is returned, and their messages, which are scanned. Text already between Open and Close is skipped.
+func Scan(s string) []Token {
+	return scan(s).tokens
+}
+
+func scan(s string) *scanner {
+	sc := &scanner{s: s, cur: &message{}}
+	sc.message(0, len(s), false)
+	return sc
+}
+
+func (sc *scanner) emit(start, end int, kind Kind) {
+	t := Token{Text: sc.s[start:end], Kind: kind, Start: start, End: end}
+	sc.tokens = append(sc.tokens, t)
+	sc.cur.tokens = append(sc.cur.tokens, t)
+}
+
+// message scans s[i:end]. In the messages of plural arguments, # is the number.
+func (sc *scanner) message(i, end int, plural bool) {
+	for i < end {
+		if next := sc.token(i, end, plural); next > i {
+			i = next
+		} else {
+			i++
+		}
+	}
+}
+
+// token scans the tokens at offset i and returns the offset after them, or i if there are none.
+func (sc *scanner) token(i, end int, plural bool) int {
+	s := sc.s[i:end]
+	match := func(re *regexp.Regexp, kind Kind) int {
+		if m := re.FindString(s); m != "" {
+			sc.emit(i, i+len(m), kind)
+			return i + len(m)
+		}
+		return i
+	}
+	switch s[0] {
+	case '[':
+		if strings.HasPrefix(s, Open) {
+			if j := strings.Index(s[len(Open):], Close); j >= 0 {
+				return i + len(Open) + j + len(Close)
+			}
+		}
+	case '{':
+		if strings.HasPrefix(s, "{{") {
+			return match(doubleBrace, Named)
+		}
+		return sc.icu(i, end)
+	case '%':
+		if next := match(percentName, Named); next > i {
+			return next
+		}
+		return match(printf, Printf)
+	case '$':
+		return match(dollarName, Named)
+	case '<':
+		return match(tag, Markup)
+	case '&':
+		return match(entity, Markup)
+	case '#':
+		if plural {
+			sc.emit(i, i+1, ICU)
+			return i + 1
+		}
+	}
+	return i
+}
+
+// icu scans the ICU argument at offset i, such as {name}, {n, number} or {count, plural, one {# item} other {...}}.
+func (sc *scanner) icu(i, end int) int {
+	n, parent, args := len(sc.tokens), sc.cur, len(sc.cur.args)
+	fail := func() int {
+		sc.tokens, sc.cur = sc.tokens[:n], parent
+		parent.args = parent.args[:args]
+		return i
+	}
+	s := sc.s[:end]
+	space := func(j int) int {
+		for j < len(s) && strings.ContainsRune(" \t\r\n", rune(s[j])) {
+			j++
+		}
+		return j
+	}
+	word := func(j int, re *regexp.Regexp) (string, int) {
+		m := re.FindString(s[j:])
+		return m, j + len(m)
+	}
+	name, j := word(space(i+1), identifier)
+	if name == "" {
+		return fail()
+	}
+	j = space(j)
+	if j < len(s) && s[j] == '}' {
+		sc.emit(i, j+1, Named)
+		return j + 1
+	}
+	if j >= len(s) || s[j] != ',' {
+		return fail()
+	}
+	typ, j := word(space(j+1), identifier)
+	j = space(j)
+	switch typ {
+	case "":
+		return fail()
+	case "plural", "select", "selectordinal":
+	default:
+		// Arguments such as {n, number, ::currency/USD} are kept whole.
+		if close := sc.matchBrace(i, end); close > 0 {
+			sc.emit(i, close+1, Named)
+			return close + 1
+		}
+		return fail()
+	}
+	if j >= len(s) || s[j] != ',' {
+		return fail()
+	}
+	j++
+	if m, next := word(space(j), offset); m != "" {
+		j = next
+	}
+	sc.emit(i, j, ICU)
+	arg := &argument{key: "{" + name + ", " + typ + "}"}
+	parent.tokens = parent.tokens[:len(parent.tokens)-1]
+	parent.args = append(parent.args, arg)
+	for {
+		j = space(j)
+		if j >= len(s) {
+			return fail()
+		}
+		if s[j] == '}' {
+			return j + 1
+		}
+		sel, next := word(j, selector)
+		if sel == "" {
+			return fail()
+		}
+		j = space(next)
+		if j >= len(s) || s[j] != '{' {
+			return fail()
+		}
+		close := sc.matchBrace(j, end)
+		if close < 0 {
+			return fail()
+		}
+		sc.cur = &message{}
+		sc.message(j+1, close, typ != "select")
+		arg.branches = append(arg.branches, branch{sel, sc.cur})
+		sc.cur, j = parent, close+1
+	}
+}
+
+// matchBrace returns the offset of the brace closing the one at offset open, or -1.
+func (sc *scanner) matchBrace(open, end int) int {
+	depth := 0
+	for j := open; j < end; j++ {
+		switch sc.s[j] {
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// Mask wraps the placeholders and markup of s in Open and Close. Adjacent ones share the same markers.
+func Mask(s string) string {
+	tokens := Scan(s)
+	var b strings.Builder
+	pos := 0
+	for i := 0; i < len(tokens); {
+		start, end := tokens[i].Start, tokens[i].End
+		for i++; i < len(tokens) && tokens[i].Start == end; i++ {
+			end = tokens[i].End
+		}
+		b.WriteString(s[pos:start])
+		b.WriteString(Open + s[start:end] + Close)
+		pos = end
+	}
+	b.WriteString(s[pos:])
+	return b.String()
+}
+
+var masked = regexp.MustCompile(`(?s)` + regexp.QuoteMeta(Open) + `(.*?)` + regexp.QuoteMeta(Close))
+
+// Unmask removes the markers of s. Markers left unpaired, such as by a translator deleting one, are kept.
+func Unmask(s string) string {
+	return masked.ReplaceAllString(s, "$1")
+}
+
+// Mismatch is a placeholder which a translation has less or more often than its source.
+type Mismatch struct {
+	Text string `json:"text"`
+	Kind Kind   `json:"kind"`
+	// Missing is how many more times the source has the placeholder, negative if the translation has it more.
+	Missing int `json:"missing"`
+}
+
+// Error is the QA error of a translation whose placeholders do not match its source.
+type Error struct {
+	Mismatches []Mismatch `json:"mismatches,omitempty"`
+	// BrokenMarkers is set when the translation has markers which its source does not, such as a lone [[[.
+	BrokenMarkers bool `json:"broken_markers,omitempty"`
+}
+
+func (e *Error) Error() string {
+	var missing, extra, problems []string
+	for _, m := range e.Mismatches {
+		if m.Missing > 0 {
+			missing = append(missing, m.Text)
+		} else {
+			extra = append(extra, m.Text)
+		}
+	}
+	if len(missing) > 0 {
+		problems = append(problems, "missing placeholders "+strings.Join(missing, ", "))
+	}
+	if len(extra) > 0 {
+		problems = append(problems, "unexpected placeholders "+strings.Join(extra, ", "))
+	}
+	if e.BrokenMarkers {
+		problems = append(problems, fmt.Sprintf("broken %s %s markers", Open, Close))
+	}
+	return strings.Join(problems, "; ")
+}
+
+// Validate checks that a translation has the placeholders and markup of its source as often, in any order. Both
+// must be unmasked. ICU plural and select arguments are matched by name and type, and each of their messages in
+// the translation checked against the message of the same selector in the source, or else of "other", so that
+// translations may have the plural forms of their language. It returns an *Error if not.
+func Validate(source, target string) error {
+	e := &Error{}
+	compare(scan(source).cur, scan(target).cur, e)
+	sort.Slice(e.Mismatches, func(i, j int) bool {
+		if a, b := e.Mismatches[i], e.Mismatches[j]; a.Text != b.Text {
+			return a.Text < b.Text
+		}
+		return e.Mismatches[i].Missing > e.Mismatches[j].Missing
+	})
+	for _, marker := range []string{Open, Close} {
+		if strings.Count(target, marker) > strings.Count(source, marker) {
+			e.BrokenMarkers = true
+		}
+	}
+	if len(e.Mismatches) == 0 && !e.BrokenMarkers {
+		return nil
+	}
+	return e
+}
+
+// compare adds the mismatches of the placeholders of a message of a translation and of its source to e. Mismatches
+// of the same placeholder and sign are added up.
+func compare(source, target *message, e *Error) {
+	counts := map[string]int{}
+	kinds := map[string]Kind{}
+	for _, t := range source.tokens {
+		counts[t.Text]++
+		kinds[t.Text] = t.Kind
+	}
+	for _, t := range target.tokens {
+		counts[t.Text]--
+		kinds[t.Text] = t.Kind
+	}
+	unmatched := map[string][]*argument{}
+	for _, a := range source.args {
+		counts[a.key]++
+		kinds[a.key] = ICU
+		unmatched[a.key] = append(unmatched[a.key], a)
+	}
+	for _, a := range target.args {
+		counts[a.key]--
+		kinds[a.key] = ICU
+		if len(unmatched[a.key]) == 0 {
+			continue
+		}
+		src := unmatched[a.key][0]
+		unmatched[a.key] = unmatched[a.key][1:]
+		for _, b := range a.branches {
+			if m := src.branch(b.selector); m != nil {
+				compare(m, b.message, e)
+			}
+		}
+	}
+	for text, n := range counts {
+		if n == 0 {
+			continue
+		}
+		found := false
+		for i := range e.Mismatches {
+			if m := &e.Mismatches[i]; m.Text == text && (m.Missing > 0) == (n > 0) {
+				m.Missing += n
+				found = true
+			}
+		}
+		if !found {
+			e.Mismatches = append(e.Mismatches, Mismatch{Text: text, Kind: kinds[text], Missing: n})
+		}
+	}
+}
+
+// Protect masks the text of a job, as an option of gengo.NewJobRequest.
+func Protect() gengo.JobOption {
+	return func(jr *gengo.JobRequest) {
+		if jr.BodySrc != nil {
+			masked := Mask(*jr.BodySrc)
+			jr.BodySrc = &masked
+		}
+	}
+}
+
+// Restore unmasks the source and translation of a job submitted with Protect, in place, and validates the
+// translation. The returned *Error does not undo the restoration.
+func Restore(job *gengo.GetJobResponse) error {
+	job.BodySrc = Unmask(job.BodySrc)
+	if job.BodyTgt == "" {
+		return nil
+	}
+	job.BodyTgt = Unmask(job.BodyTgt)
+	return Validate(job.BodySrc, job.BodyTgt)
+}
@@ -0,0 +1,111 @@
+package placeholder_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+	"github.com/trinchan/gengo/placeholder"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello {name}!", "Hello [[[{name}]]]!"},
+		{"%d of %1$s at 100% %.2f%%", "[[[%d]]] of [[[%1$s]]] at 100% [[[%.2f%%]]]"},
+		{"You have {{count}} new %{kind} and ${total}", "You have [[[{{count}}]]] new [[[%{kind}]]] and [[[${total}]]]"},
+		{"Click <a href=\"/x\">here</a> &amp; go", "Click [[[<a href=\"/x\">]]]here[[[</a>]]] [[[&amp;]]] go"},
+		{"<b>{name}</b>", "[[[<b>{name}</b>]]]"},
+		{
+			"{count, plural, =0 {No items} one {# item} other {# items for {name}}}",
+			"[[[{count, plural,]]] =0 {No items} one {[[[#]]] item} other {[[[#]]] items for [[[{name}]]]}}",
+		},
+		{"{gender, select, female {She} other {They}} # left", "[[[{gender, select,]]] female {She} other {They}} # left"},
+		{"Paid {n, number, ::currency/USD}", "Paid [[[{n, number, ::currency/USD}]]]"},
+		{"Keep [[[ACME {x}]]] and { not a placeholder", "Keep [[[ACME {x}]]] and { not a placeholder"},
+		{"50 % off, a < b", "50 % off, a < b"},
+	}
+	for _, test := range tests {
+		got := placeholder.Mask(test.in)
+		if got != test.want {
+			t.Errorf("Mask(%q) = %q, want %q", test.in, got, test.want)
+		}
+		if unmasked := placeholder.Unmask(got); unmasked != test.in && !strings.Contains(test.in, placeholder.Open) {
+			t.Errorf("Unmask(%q) = %q, want %q", got, unmasked, test.in)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	source := "Hi {name}, you have <b>%d</b> messages"
+	if err := placeholder.Validate(source, "<b>%d</b> messages pour {name}"); err != nil {
+		t.Errorf("Validate() of reordered placeholders = %v", err)
+	}
+	err := placeholder.Validate(source, "Salut {nom}, <b>%d</b> messages [[[")
+	var e *placeholder.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Validate() = %v, want *Error", err)
+	}
+	want := []placeholder.Mismatch{
+		{Text: "{name}", Kind: placeholder.Named, Missing: 1},
+		{Text: "{nom}", Kind: placeholder.Named, Missing: -1},
+	}
+	if !reflect.DeepEqual(e.Mismatches, want) || !e.BrokenMarkers {
+		t.Errorf("Validate() = %+v, want %+v with broken markers", e, want)
+	}
+	if got, want := err.Error(), "missing placeholders {name}; unexpected placeholders {nom}; broken [[[ ]]] markers"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestValidatePlurals(t *testing.T) {
+	source := "{count, plural, one {# item for {name}} other {# items for {name}}}"
+	for _, target := range []string{
+		"{count, plural, one {# предмет для {name}} few {# предмета для {name}} many {# предметов для {name}} other {# предмета для {name}}}",
+		"{count,plural,other{{name}に#個}}",
+	} {
+		if err := placeholder.Validate(source, target); err != nil {
+			t.Errorf("Validate(%q) = %v", target, err)
+		}
+	}
+	err := placeholder.Validate(source, "{count, plural, one {# предмет для {name}} few {предмета} other {# предмета для {name}}}")
+	var e *placeholder.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Validate() = %v, want *Error", err)
+	}
+	want := []placeholder.Mismatch{
+		{Text: "#", Kind: placeholder.ICU, Missing: 1},
+		{Text: "{name}", Kind: placeholder.Named, Missing: 1},
+	}
+	if !reflect.DeepEqual(e.Mismatches, want) {
+		t.Errorf("Validate() of a form without its placeholders = %+v, want %+v", e.Mismatches, want)
+	}
+	if err := placeholder.Validate(source, "{n, plural, other {# предмета для {name}}}"); err == nil {
+		t.Error("Validate() of a renamed plural argument succeeded")
+	}
+	if err := placeholder.Validate(source, "{count, select, other {# предмета для {name}}}"); err == nil {
+		t.Error("Validate() of a plural argument turned into a select succeeded")
+	}
+}
+
+func TestProtectRestore(t *testing.T) {
+	jr := gengo.NewJobRequest("Hello {name}", lang.NewPair(lang.English, lang.Japanese), gengo.TierStandard, placeholder.Protect())
+	if got, want := *jr.BodySrc, "Hello [[[{name}]]]"; got != want {
+		t.Fatalf("BodySrc = %q, want %q", got, want)
+	}
+	job := &gengo.GetJobResponse{BodySrc: *jr.BodySrc, BodyTgt: "こんにちは、[[[{name}]]]"}
+	if err := placeholder.Restore(job); err != nil {
+		t.Errorf("Restore() = %v", err)
+	}
+	if job.BodySrc != "Hello {name}" || job.BodyTgt != "こんにちは、{name}" {
+		t.Errorf("Restore() = %q, %q", job.BodySrc, job.BodyTgt)
+	}
+	job = &gengo.GetJobResponse{BodySrc: *jr.BodySrc, BodyTgt: "こんにちは"}
+	if err := placeholder.Restore(job); err == nil {
+		t.Error("Restore() of a translation without its placeholder succeeded")
+	}
+}
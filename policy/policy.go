@@ -0,0 +1,250 @@
+// Package policy approves or requests revisions of reviewable Gengo jobs based on a set of rules.
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/trinchan/gengo"
+)
+
+// Action is the outcome of evaluating a job.
+type Action string
+
+const (
+	// ActionApprove approves the job.
+	ActionApprove = Action("approve")
+	// ActionRevise requests a revision of the job.
+	ActionRevise = Action("revise")
+	// ActionSkip leaves the job for a person to review.
+	ActionSkip = Action("skip")
+)
+
+// Violation describes a rule a job failed.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Decision describes what the engine did, or would do in a dry run, with a job.
+type Decision struct {
+	JobID      int                   `json:"job_id"`
+	Action     Action                `json:"action"`
+	Passed     []string              `json:"passed,omitempty"`
+	Violations []Violation           `json:"violations,omitempty"`
+	Comment    string                `json:"comment,omitempty"`
+	Rating     int                   `json:"rating,omitempty"`
+	Job        *gengo.GetJobResponse `json:"-"`
+}
+
+// Report lists the decisions made for every evaluated job.
+type Report struct {
+	DryRun    bool       `json:"dry_run"`
+	Decisions []Decision `json:"decisions"`
+}
+
+// Count returns the number of decisions with the given action.
+func (r *Report) Count(a Action) int {
+	n := 0
+	for _, d := range r.Decisions {
+		if d.Action == a {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteTo writes the report as a table.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	buf := new(bytes.Buffer)
+	if r.DryRun {
+		buf.WriteString("DRY RUN: no jobs were changed\n")
+	}
+	tw := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "JOB\tACTION\tDETAILS")
+	for _, d := range r.Decisions {
+		details := make([]string, 0, len(d.Violations))
+		for _, v := range d.Violations {
+			details = append(details, v.Rule+": "+v.Message)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", d.JobID, d.Action, strings.Join(details, "; "))
+	}
+	tw.Flush()
+	fmt.Fprintf(buf, "%d approved, %d revised, %d skipped\n", r.Count(ActionApprove), r.Count(ActionRevise), r.Count(ActionSkip))
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+var (
+	defaultApprovalTemplate = template.Must(template.New("approval").Parse(
+		`Approved automatically after passing {{len .Passed}} checks. Thank you!`))
+	defaultRevisionTemplate = template.Must(template.New("revision").Parse(
+		`Please revise the translation:{{range .Violations}}
+- {{.Message}}{{end}}`))
+)
+
+// Engine evaluates reviewable jobs against a set of rules.
+type Engine struct {
+	Client           *gengo.Client
+	Rules            []Rule
+	Rating           int
+	ApprovalTemplate *template.Template
+	RevisionTemplate *template.Template
+	DryRun           bool
+}
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// WithDryRun evaluates jobs without approving or revising them.
+func WithDryRun() Option {
+	return func(e *Engine) {
+		e.DryRun = true
+	}
+}
+
+// WithRating sets the rating given to approved jobs. The default is 5.
+func WithRating(i int) Option {
+	return func(e *Engine) {
+		e.Rating = i
+	}
+}
+
+// WithApprovalTemplate sets the template for the comment left for the translator when approving.
+// The template is executed with the job's Decision.
+func WithApprovalTemplate(t *template.Template) Option {
+	return func(e *Engine) {
+		e.ApprovalTemplate = t
+	}
+}
+
+// WithRevisionTemplate sets the template for the comment sent with revision requests.
+// The template is executed with the job's Decision.
+func WithRevisionTemplate(t *template.Template) Option {
+	return func(e *Engine) {
+		e.RevisionTemplate = t
+	}
+}
+
+// New creates a new Engine checking jobs with the given rules.
+func New(c *gengo.Client, rules []Rule, options ...Option) *Engine {
+	e := &Engine{
+		Client:           c,
+		Rules:            rules,
+		Rating:           5,
+		ApprovalTemplate: defaultApprovalTemplate,
+		RevisionTemplate: defaultRevisionTemplate,
+	}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// Evaluate checks a job against every rule and decides what to do with it.
+// A job is skipped if any rule skips it, revised if any rule fails and approved otherwise.
+func (e *Engine) Evaluate(job *gengo.GetJobResponse) (Decision, error) {
+	d := Decision{JobID: int(job.ID), Action: ActionApprove, Job: job}
+	for _, rule := range e.Rules {
+		err := rule.Check(job)
+		switch {
+		case err == nil:
+			d.Passed = append(d.Passed, rule.Name())
+			continue
+		case errors.Is(err, ErrSkip):
+			d.Action = ActionSkip
+		case d.Action == ActionApprove:
+			d.Action = ActionRevise
+		}
+		d.Violations = append(d.Violations, Violation{Rule: rule.Name(), Message: err.Error()})
+	}
+	var t *template.Template
+	switch d.Action {
+	case ActionApprove:
+		t = e.ApprovalTemplate
+		d.Rating = e.Rating
+	case ActionRevise:
+		t = e.RevisionTemplate
+	}
+	if t != nil {
+		buf := new(bytes.Buffer)
+		if err := t.Execute(buf, d); err != nil {
+			return d, fmt.Errorf("executing %s template for job %d: %v", t.Name(), d.JobID, err)
+		}
+		d.Comment = buf.String()
+	}
+	return d, nil
+}
+
+// Run evaluates every reviewable job and, unless running dry, approves or revises them. If not every reviewable
+// job could be listed, it applies the policy to those which were and returns its report with the
+// *gengo.IncompleteListError.
+func (e *Engine) Run() (*Report, error) {
+	jobs, listErr := e.reviewableJobs()
+	if _, incomplete := listErr.(*gengo.IncompleteListError); listErr != nil && !incomplete {
+		return nil, listErr
+	}
+	r, err := e.Apply(jobs)
+	if err != nil {
+		return r, err
+	}
+	return r, listErr
+}
+
+// Apply evaluates the given jobs and, unless running dry, approves or revises them.
+func (e *Engine) Apply(jobs []gengo.GetJobResponse) (*Report, error) {
+	r := &Report{DryRun: e.DryRun}
+	var approvals []*gengo.ApproveJobRequest
+	var revisions []*gengo.ReviseJobRequest
+	for i := range jobs {
+		if jobs[i].Status != "" && jobs[i].Status != gengo.JobStatusReviewable {
+			continue
+		}
+		d, err := e.Evaluate(&jobs[i])
+		if err != nil {
+			return nil, err
+		}
+		r.Decisions = append(r.Decisions, d)
+		switch d.Action {
+		case ActionApprove:
+			approvals = append(approvals, gengo.NewApproveJobRequest(d.JobID, gengo.WithRating(d.Rating), gengo.WithTranslatorComment(d.Comment)))
+		case ActionRevise:
+			revisions = append(revisions, gengo.NewReviseJobRequest(d.JobID, gengo.WithRevisionComment(d.Comment)))
+		}
+	}
+	if e.DryRun {
+		return r, nil
+	}
+	if len(approvals) > 0 {
+		if err := e.Client.ApproveJobs(gengo.NewApproveJobsRequest(approvals...)); err != nil {
+			return r, fmt.Errorf("approving jobs: %v", err)
+		}
+	}
+	if len(revisions) > 0 {
+		if err := e.Client.ReviseJobs(gengo.NewReviseJobsRequest(revisions...)); err != nil {
+			return r, fmt.Errorf("revising jobs: %v", err)
+		}
+	}
+	return r, nil
+}
+
+func (e *Engine) reviewableJobs() ([]gengo.GetJobResponse, error) {
+	list, listErr := e.Client.ListJobs(gengo.NewGetJobsRequest(gengo.WithStatus(gengo.JobStatusReviewable)))
+	if list == nil {
+		return nil, listErr
+	}
+	ids := make([]int, 0, len(list.Jobs))
+	for _, j := range list.Jobs {
+		ids = append(ids, int(j.ID))
+	}
+	r, err := e.Client.GetJobsByID(gengo.NewGetJobsByIDRequest(ids...))
+	if err != nil {
+		return nil, err
+	}
+	return r.Jobs, listErr
+}
@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/glossary"
+	"github.com/trinchan/gengo/internal/gengotest"
+	"github.com/trinchan/gengo/lang"
+)
+
+func job(src, tgt string) *gengo.GetJobResponse {
+	return &gengo.GetJobResponse{ID: 1, BodySrc: src, BodyTgt: tgt, Pair: lang.NewPair(lang.English, lang.French), Tier: gengo.TierStandard}
+}
+
+func TestRules(t *testing.T) {
+	g := glossary.New(lang.English, lang.French)
+	g.Add(glossary.Term{Source: "dashboard", Translations: map[lang.Code]string{lang.French: "tableau de bord"}})
+	checker := glossary.NewChecker(g)
+	tests := []struct {
+		name string
+		rule Rule
+		job  *gengo.GetJobResponse
+		fail bool
+		skip bool
+	}{
+		{"ratio ok", LengthRatio{Min: 0.5, Max: 2}, job("Hello there", "Bonjour toi"), false, false},
+		{"ratio short", LengthRatio{Min: 0.5, Max: 2}, job("Hello there friend", "Oui"), true, false},
+		{"ratio long", LengthRatio{Min: 0.5, Max: 2}, job("Hi", "Bonjour tout le monde"), true, false},
+		{"ratio empty source", LengthRatio{Min: 0.5}, job("", "x"), true, true},
+		{"placeholders ok", Placeholders{}, job("Hi {name}, you have %d {{count}} <b>new</b>", "Salut {name}, vous avez %d {{count}} <b>nouveaux</b>"), false, false},
+		{"placeholders missing", Placeholders{}, job("Hi {name}", "Salut"), true, false},
+		{"placeholders extra", Placeholders{}, job("Hi", "Salut %s"), true, false},
+		{"glossary ok", GlossaryTerms{Checker: checker}, job("Open the Dashboard", "Ouvrez le Tableau de bord"), false, false},
+		{"glossary missing", GlossaryTerms{Checker: checker}, job("Open the dashboard", "Ouvrez le panneau"), true, false},
+		{"glossary unset", GlossaryTerms{}, job("Open the dashboard", "Ouvrez le panneau"), true, true},
+		{"max chars ok", MaxChars{Limit: 5}, job("Hello", "こんにちは"), false, false},
+		{"max chars", MaxChars{Limit: 4}, job("Hello", "こんにちは"), true, false},
+		{"max chars unset", MaxChars{}, job("Hello", "こんにちは"), false, false},
+		{"tier ok", Tiers{gengo.TierStandard}, job("a", "b"), false, false},
+		{"tier skip", Tiers{gengo.TierPro}, job("a", "b"), true, true},
+	}
+	for _, tt := range tests {
+		err := tt.rule.Check(tt.job)
+		if (err != nil) != tt.fail {
+			t.Errorf("%s: Check() = %v, want failure %v", tt.name, err, tt.fail)
+		}
+		if errors.Is(err, ErrSkip) != tt.skip {
+			t.Errorf("%s: Check() = %v, want skip %v", tt.name, err, tt.skip)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	e := New(nil, []Rule{Placeholders{}, MaxChars{Limit: 20}})
+	d, err := e.Evaluate(job("Hi {name}", "Salut"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Action != ActionRevise || !strings.Contains(d.Comment, "missing placeholders {name}") {
+		t.Errorf("unexpected decision %+v", d)
+	}
+	d, err = e.Evaluate(job("Hi {name}", "Salut {name}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Action != ActionApprove || d.Rating != 5 || !strings.Contains(d.Comment, "2 checks") {
+		t.Errorf("unexpected decision %+v", d)
+	}
+}
+
+func TestRun(t *testing.T) {
+	api := gengotest.New(map[string]string{
+		"GET /translate/jobs": `[{"job_id":"1"},{"job_id":"2"}]`,
+		"GET /translate/jobs/1,2": `{"jobs":[
+			{"job_id":"1","status":"reviewable","tier":"standard","body_src":"Hi {name}","body_tgt":"Salut {name}"},
+			{"job_id":"2","status":"reviewable","tier":"standard","body_src":"Hi {name}","body_tgt":"Salut"}]}`,
+		"PUT /translate/jobs": `{}`,
+	})
+	c := gengo.New("public", "private", gengo.SandboxBaseURL)
+	c.SetRoundTripper(api)
+	puts := func() []string {
+		var puts []string
+		for i, req := range api.Requests() {
+			if req.Method == http.MethodPut {
+				puts = append(puts, api.Form(i).Get("data"))
+			}
+		}
+		return puts
+	}
+
+	r, err := New(c, []Rule{Placeholders{}}, WithDryRun()).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Count(ActionApprove) != 1 || r.Count(ActionRevise) != 1 || len(puts()) != 0 {
+		t.Fatalf("dry run decided %+v and made %d changes", r.Decisions, len(puts()))
+	}
+	buf := new(bytes.Buffer)
+	r.WriteTo(buf)
+	if !strings.Contains(buf.String(), "DRY RUN") || !strings.Contains(buf.String(), "1 approved, 1 revised, 0 skipped") {
+		t.Errorf("unexpected report:\n%s", buf)
+	}
+
+	if _, err := New(c, []Rule{Placeholders{}}).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if puts := puts(); len(puts) != 2 || !strings.Contains(puts[0], `"action":"approve"`) || !strings.Contains(puts[1], `"action":"revise"`) {
+		t.Errorf("unexpected changes %v", puts)
+	}
+}
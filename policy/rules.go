@@ -0,0 +1,160 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/glossary"
+)
+
+// Rule checks a reviewable job before it is approved.
+// Check returns nil if the job passes, an error describing the problem if it should be revised,
+// or an error wrapping ErrSkip if the job should be left for a person to review.
+type Rule interface {
+	Name() string
+	Check(job *gengo.GetJobResponse) error
+}
+
+// ErrSkip marks a rule failure which leaves a job alone rather than requesting a revision.
+var ErrSkip = errors.New("skipped")
+
+// Skip formats an error which leaves the job for a person to review.
+func Skip(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrSkip, fmt.Sprintf(format, args...))
+}
+
+// LengthRatio requires the length of the translation relative to the source to fall within [Min, Max].
+// Lengths are measured in characters; a zero Max disables the upper bound.
+type LengthRatio struct {
+	Min float64
+	Max float64
+}
+
+// Name implements Rule.
+func (r LengthRatio) Name() string { return "length-ratio" }
+
+// Check implements Rule.
+func (r LengthRatio) Check(job *gengo.GetJobResponse) error {
+	src := utf8.RuneCountInString(strings.TrimSpace(job.BodySrc))
+	if src == 0 {
+		return Skip("job has no source text")
+	}
+	ratio := float64(utf8.RuneCountInString(strings.TrimSpace(job.BodyTgt))) / float64(src)
+	if ratio < r.Min {
+		return fmt.Errorf("translation is %.0f%% of the source length, expected at least %.0f%%", ratio*100, r.Min*100)
+	}
+	if r.Max > 0 && ratio > r.Max {
+		return fmt.Errorf("translation is %.0f%% of the source length, expected at most %.0f%%", ratio*100, r.Max*100)
+	}
+	return nil
+}
+
+// DefaultPlaceholderPattern matches {{mustache}}, {named}, printf style and HTML tag placeholders.
+var DefaultPlaceholderPattern = regexp.MustCompile(`\{\{\s*[\w.]+\s*\}\}|\{[\w.]+\}|%(?:\d+\$)?[-+ #0]*\d*(?:\.\d+)?[sdfiuxXc@%]|</?[A-Za-z][^<>]*>`)
+
+// Placeholders requires every placeholder in the source to appear in the translation as often as in the source.
+type Placeholders struct {
+	// Pattern matches placeholders. DefaultPlaceholderPattern is used if nil.
+	Pattern *regexp.Regexp
+}
+
+// Name implements Rule.
+func (r Placeholders) Name() string { return "placeholders" }
+
+// Check implements Rule.
+func (r Placeholders) Check(job *gengo.GetJobResponse) error {
+	pattern := r.Pattern
+	if pattern == nil {
+		pattern = DefaultPlaceholderPattern
+	}
+	counts := map[string]int{}
+	for _, p := range pattern.FindAllString(job.BodySrc, -1) {
+		counts[p]++
+	}
+	for _, p := range pattern.FindAllString(job.BodyTgt, -1) {
+		counts[p]--
+	}
+	var missing, extra []string
+	for p, n := range counts {
+		if n > 0 {
+			missing = append(missing, p)
+		} else if n < 0 {
+			extra = append(extra, p)
+		}
+	}
+	if len(missing) == 0 && len(extra) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing placeholders "+strings.Join(missing, ", "))
+	}
+	if len(extra) > 0 {
+		problems = append(problems, "unexpected placeholders "+strings.Join(extra, ", "))
+	}
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// GlossaryTerms requires the mandated translation of every glossary term found in the source text to appear in the translation.
+// Jobs are skipped without a Checker.
+type GlossaryTerms struct {
+	Checker *glossary.Checker
+}
+
+// Name implements Rule.
+func (r GlossaryTerms) Name() string { return "glossary" }
+
+// Check implements Rule.
+func (r GlossaryTerms) Check(job *gengo.GetJobResponse) error {
+	if r.Checker == nil {
+		return Skip("no glossary is set to check the terms of the translation")
+	}
+	violations := r.Checker.CheckJob(job)
+	if len(violations) == 0 {
+		return nil
+	}
+	missing := make([]string, len(violations))
+	for i, v := range violations {
+		missing[i] = v.String()
+	}
+	return errors.New(strings.Join(missing, "; "))
+}
+
+// MaxChars requires the translation to be at most Limit characters long. A Limit of zero or less disables it.
+type MaxChars struct {
+	Limit int
+}
+
+// Name implements Rule.
+func (r MaxChars) Name() string { return "max-chars" }
+
+// Check implements Rule.
+func (r MaxChars) Check(job *gengo.GetJobResponse) error {
+	if n := utf8.RuneCountInString(job.BodyTgt); r.Limit > 0 && n > r.Limit {
+		return fmt.Errorf("translation is %d characters long, the limit is %d", n, r.Limit)
+	}
+	return nil
+}
+
+// Tiers only allows jobs ordered at one of the given tiers to be approved automatically.
+type Tiers []gengo.Tier
+
+// Name implements Rule.
+func (r Tiers) Name() string { return "tier" }
+
+// Check implements Rule.
+func (r Tiers) Check(job *gengo.GetJobResponse) error {
+	for _, t := range r {
+		if job.Tier == t {
+			return nil
+		}
+	}
+	return Skip("%s tier jobs are not approved automatically", job.Tier)
+}
@@ -0,0 +1,152 @@
+package gengo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"strings"
+)
+
+const (
+	// FollowUpRequeue sends a rejected job back to the translator pool.
+	FollowUpRequeue = "requeue"
+	// FollowUpCancel cancels a rejected job and refunds its credits.
+	FollowUpCancel = "cancel"
+)
+
+var (
+	// ErrInvalidRejectionReason is returned when a rejection reason is not one of the RejectionReason constants.
+	ErrInvalidRejectionReason = errors.New("gengo: rejection reason must be one of quality, incomplete or other")
+	// ErrInvalidFollowUp is returned when a rejection follow up is not one of the FollowUp constants.
+	ErrInvalidFollowUp = errors.New("gengo: rejection follow up must be one of requeue or cancel")
+	// ErrMissingRejectionComment is returned when a rejection has no comment for the translator.
+	ErrMissingRejectionComment = errors.New("gengo: rejection comment must not be empty")
+	// ErrMissingCaptchaURL is returned when Gengo does not provide a captcha for a job being rejected.
+	ErrMissingCaptchaURL = errors.New("gengo: job has no captcha url")
+)
+
+// Validate checks the rejection reason, comment and follow up before the request is sent.
+func (r *RejectJobRequest) Validate() error {
+	switch r.Reason {
+	case RejectionReasonQuality, RejectionReasonIncomplete, RejectionReasonOther:
+	default:
+		return ErrInvalidRejectionReason
+	}
+	if strings.TrimSpace(r.Comment) == "" {
+		return ErrMissingRejectionComment
+	}
+	if r.FollowUp != nil {
+		switch *r.FollowUp {
+		case FollowUpRequeue, FollowUpCancel:
+		default:
+			return ErrInvalidFollowUp
+		}
+	}
+	return nil
+}
+
+// CaptchaSolver reads the text from a captcha image so that a job can be rejected.
+type CaptchaSolver interface {
+	SolveCaptcha(image []byte, contentType string) (string, error)
+}
+
+// CaptchaSolverFunc adapts a function into a CaptchaSolver.
+type CaptchaSolverFunc func(image []byte, contentType string) (string, error)
+
+// SolveCaptcha calls f.
+func (f CaptchaSolverFunc) SolveCaptcha(image []byte, contentType string) (string, error) {
+	return f(image, contentType)
+}
+
+// StaticCaptchaSolver answers every captcha with the same text, which is useful for tests.
+type StaticCaptchaSolver string
+
+// SolveCaptcha returns s.
+func (s StaticCaptchaSolver) SolveCaptcha(image []byte, contentType string) (string, error) {
+	return string(s), nil
+}
+
+// PromptCaptchaSolver saves the captcha image to a temporary file and asks a person to type its text.
+type PromptCaptchaSolver struct {
+	In  io.Reader
+	Out io.Writer
+	// Dir is the directory the captcha image is written to. The default temporary directory is used if empty.
+	Dir string
+	// in buffers In across calls, so that answers typed ahead for later captchas are kept.
+	in *bufio.Reader
+}
+
+// NewPromptCaptchaSolver creates a new PromptCaptchaSolver prompting on standard input and output.
+func NewPromptCaptchaSolver() *PromptCaptchaSolver {
+	return &PromptCaptchaSolver{In: os.Stdin, Out: os.Stdout}
+}
+
+// SolveCaptcha writes the image to disk, prints its location and reads the answer from a line of input.
+func (p *PromptCaptchaSolver) SolveCaptcha(image []byte, contentType string) (string, error) {
+	ext := ".img"
+	if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+		ext = exts[0]
+	}
+	f, err := os.CreateTemp(p.Dir, "gengo-captcha-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(image)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(p.Out, "Captcha saved to %s\nEnter the text shown: ", f.Name())
+	if p.in == nil {
+		p.in = bufio.NewReader(p.In)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// RejectJobsWithCaptcha validates and rejects jobs, solving each job's captcha with solver.
+// Jobs without a CaptchaURL are looked up to find the captcha Gengo issued for them.
+// The Captcha field of each request is filled in with the solver's answer.
+func (c *Client) RejectJobsWithCaptcha(solver CaptchaSolver, reqs ...*RejectJobRequest) (*RejectJobsResponse, error) {
+	for _, req := range reqs {
+		if err := req.Validate(); err != nil {
+			return nil, fmt.Errorf("rejecting job %d: %w", req.ID, err)
+		}
+	}
+	for _, req := range reqs {
+		if req.CaptchaURL == nil || *req.CaptchaURL == "" {
+			job, err := c.GetJob(NewGetJobRequest(req.ID))
+			if err != nil {
+				return nil, err
+			}
+			if job.Job.CaptchaURL == "" {
+				return nil, fmt.Errorf("rejecting job %d: %w", req.ID, ErrMissingCaptchaURL)
+			}
+			WithCaptchaURL(job.Job.CaptchaURL)(req)
+		}
+		body, contentType, err := c.open(*req.CaptchaURL, false)
+		if err != nil {
+			return nil, err
+		}
+		image, err := io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return nil, err
+		}
+		answer, err := solver.SolveCaptcha(image, contentType)
+		if err != nil {
+			return nil, fmt.Errorf("solving captcha for job %d: %w", req.ID, err)
+		}
+		req.Captcha = answer
+	}
+	return c.RejectJobs(NewRejectJobsRequest(reqs...))
+}
@@ -0,0 +1,113 @@
+package gengo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/trinchan/gengo/internal/gengotest"
+)
+
+func TestRejectJobRequestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *RejectJobRequest
+		err  error
+	}{
+		{"valid", NewRejectJobRequest(1, RejectionReasonQuality, "poor", ""), nil},
+		{"follow up", NewRejectJobRequest(1, RejectionReasonOther, "wrong file", "", WithFollowUp(FollowUpCancel)), nil},
+		{"reason", NewRejectJobRequest(1, "bad", "poor", ""), ErrInvalidRejectionReason},
+		{"comment", NewRejectJobRequest(1, RejectionReasonIncomplete, " ", ""), ErrMissingRejectionComment},
+		{"bad follow up", NewRejectJobRequest(1, RejectionReasonQuality, "poor", "", WithFollowUp("retry")), ErrInvalidFollowUp},
+	}
+	for _, tt := range tests {
+		if err := tt.req.Validate(); err != tt.err {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+// captchaAPI serves the captcha image outside of the Gengo API.
+type captchaAPI struct {
+	*gengotest.API
+}
+
+func (c captchaAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "captcha.example" {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"image/png"}},
+			Body:       io.NopCloser(strings.NewReader("PNG")),
+		}, nil
+	}
+	return c.API.RoundTrip(req)
+}
+
+func TestRejectJobsWithCaptcha(t *testing.T) {
+	c, api := newFakeClient()
+	c.SetRoundTripper(captchaAPI{api})
+	api.Handle("GET /translate/job/3", `{"job":{"job_id":"3","captcha_url":"http://captcha.example/3.png"}}`)
+	api.Handle("PUT /translate/jobs", `{"jobs":[{"job_id":3,"rejection_reason":"quality","rejection_comments":"poor"}]}`)
+
+	var seen []byte
+	solver := CaptchaSolverFunc(func(image []byte, contentType string) (string, error) {
+		seen = image
+		return "abc123", nil
+	})
+	r, err := c.RejectJobsWithCaptcha(solver, NewRejectJobRequest(3, RejectionReasonQuality, "poor", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(seen) != "PNG" {
+		t.Errorf("solver saw %q", seen)
+	}
+	if len(r.Jobs) != 1 || r.Jobs[0].Reason != RejectionReasonQuality {
+		t.Errorf("unexpected response %+v", r)
+	}
+	var sent RejectJobsRequest
+	api.Data(t, 1, &sent)
+	if sent.Jobs[0].Captcha != "abc123" || *sent.Jobs[0].CaptchaURL != "http://captcha.example/3.png" {
+		t.Errorf("unexpected reject request %+v", sent.Jobs[0])
+	}
+}
+
+func TestRejectJobsWithCaptchaCanceled(t *testing.T) {
+	c, api := newFakeClient()
+	c.SetRoundTripper(captchaAPI{api})
+	api.Handle("GET /translate/job/3", `{"job":{"job_id":"3","captcha_url":"http://captcha.example/3.png"}}`)
+	solver := CaptchaSolverFunc(func([]byte, string) (string, error) { return "", context.Canceled })
+	if _, err := c.RejectJobsWithCaptcha(solver, NewRejectJobRequest(3, RejectionReasonQuality, "poor", "")); !errors.Is(err, context.Canceled) {
+		t.Errorf("RejectJobsWithCaptcha() with a canceled solver = %v", err)
+	}
+}
+
+func TestRejectJobsWithCaptchaInvalid(t *testing.T) {
+	c, api := newFakeClient()
+	_, err := c.RejectJobsWithCaptcha(StaticCaptchaSolver("x"), NewRejectJobRequest(3, "meh", "poor", ""))
+	if !errors.Is(err, ErrInvalidRejectionReason) {
+		t.Errorf("expected ErrInvalidRejectionReason, got %v", err)
+	}
+	if api.Len() != 0 {
+		t.Errorf("invalid rejection made %d requests", api.Len())
+	}
+}
+
+func TestPromptCaptchaSolver(t *testing.T) {
+	out := new(bytes.Buffer)
+	p := &PromptCaptchaSolver{In: strings.NewReader("xyz\nabc\n"), Out: out, Dir: t.TempDir()}
+	answer, err := p.SolveCaptcha([]byte("PNG"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "xyz" || !strings.Contains(out.String(), ".png") {
+		t.Errorf("answer %q, prompt %q", answer, out.String())
+	}
+	// The answer typed ahead for the next captcha is not lost to the buffer of the first.
+	if answer, err := p.SolveCaptcha([]byte("PNG"), "image/png"); answer != "abc" || err != nil {
+		t.Errorf("second answer %q, %v, want abc", answer, err)
+	}
+}
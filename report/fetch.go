@@ -0,0 +1,163 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/trinchan/gengo"
+)
+
+const (
+	// DefaultPageSize is the number of jobs listed per GetJobs() call, the most Gengo allows.
+	DefaultPageSize = gengo.MaxJobsPerPage
+	// revisionFetchConcurrency limits the number of jobs whose revisions are fetched at once.
+	revisionFetchConcurrency = 4
+)
+
+// Job is a job of the account's history, with its revisions when they were fetched.
+type Job struct {
+	gengo.GetJobResponse
+	// Revisions lists the translations delivered for the job, oldest first. It is nil unless revisions were fetched.
+	Revisions []gengo.RevisionWithID `json:"revisions,omitempty"`
+}
+
+// Month is the month the job was created in, such as "2024-01".
+func (j *Job) Month() string {
+	return time.Time(j.Ctime).UTC().Format("2006-01")
+}
+
+// TimeToLastDelivery is the time from the creation of an approved job to the delivery of its last revision. The
+// API does not tell when jobs are approved, which may be much later. ok is false for other jobs and when revisions
+// were not fetched.
+func (j *Job) TimeToLastDelivery() (d time.Duration, ok bool) {
+	if j.Status != gengo.JobStatusApproved || len(j.Revisions) == 0 || time.Time(j.Ctime).IsZero() {
+		return 0, false
+	}
+	last := time.Time(j.Revisions[len(j.Revisions)-1].Ctime)
+	return last.Sub(time.Time(j.Ctime)), true
+}
+
+// Redelivered reports whether the translation was delivered more than once. The API does not tell revision requests
+// from translators saving their work again, which both make a revision.
+func (j *Job) Redelivered() bool {
+	return len(j.Revisions) > 1
+}
+
+// Rejected reports whether the job's translation was rejected.
+func (j *Job) Rejected() bool {
+	return j.Status == gengo.JobStatusRejected
+}
+
+// Fetcher retrieves the whole job history of an account.
+type Fetcher struct {
+	Client   *gengo.Client
+	PageSize int
+	// Since excludes the jobs created at or before it, unless it is zero.
+	Since time.Time
+	// Revisions fetches the revisions of every job, needed for delivery times and redelivery rates.
+	Revisions bool
+}
+
+// FetcherOption configures a Fetcher.
+type FetcherOption func(*Fetcher)
+
+// WithSince only fetches the jobs created after t.
+func WithSince(t time.Time) FetcherOption {
+	return func(f *Fetcher) {
+		f.Since = t
+	}
+}
+
+// WithPageSize sets the number of jobs listed per request.
+func WithPageSize(n int) FetcherOption {
+	return func(f *Fetcher) {
+		f.PageSize = n
+	}
+}
+
+// WithRevisions fetches the revisions of every job, one request per job.
+func WithRevisions() FetcherOption {
+	return func(f *Fetcher) {
+		f.Revisions = true
+	}
+}
+
+// NewFetcher creates a new Fetcher using the client.
+func NewFetcher(c *gengo.Client, options ...FetcherOption) *Fetcher {
+	f := &Fetcher{Client: c, PageSize: DefaultPageSize}
+	for _, option := range options {
+		option(f)
+	}
+	return f
+}
+
+// Jobs lists, hydrates and, if configured, fetches the revisions of every job, oldest first. If not every job
+// could be listed, it returns those which were with a *gengo.IncompleteListError.
+func (f *Fetcher) Jobs() ([]Job, error) {
+	pageSize := f.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	options := []gengo.GetJobsRequestOption{gengo.WithCount(pageSize)}
+	if !f.Since.IsZero() {
+		options = append(options, gengo.WithTimestampAfter(gengo.Time(f.Since)))
+	}
+	list, listErr := f.Client.ListJobs(gengo.NewGetJobsRequest(options...))
+	if _, incomplete := listErr.(*gengo.IncompleteListError); listErr != nil && !incomplete {
+		return nil, listErr
+	}
+	ids := make([]int, len(list.Jobs))
+	for i, j := range list.Jobs {
+		ids[i] = int(j.ID)
+	}
+	r, err := f.Client.GetJobsByID(gengo.NewGetJobsByIDRequest(ids...))
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]Job, 0, len(r.Jobs))
+	for _, j := range r.Jobs {
+		jobs = append(jobs, Job{GetJobResponse: j})
+	}
+	sort.SliceStable(jobs, func(a, b int) bool {
+		return time.Time(jobs[a].Ctime).Before(time.Time(jobs[b].Ctime))
+	})
+	if f.Revisions {
+		if err := f.revisions(jobs); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, listErr
+}
+
+func (f *Fetcher) revisions(jobs []Job) error {
+	errs := make([]error, len(jobs))
+	sem := make(chan struct{}, revisionFetchConcurrency)
+	var wg sync.WaitGroup
+	for i := range jobs {
+		wg.Add(1)
+		go func(j *Job, err *error) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			r, rerr := f.Client.JobRevisions(gengo.NewJobRevisionsRequest(int(j.ID)))
+			if rerr != nil {
+				*err = fmt.Errorf("retrieving revisions of job %d: %v", j.ID, rerr)
+				return
+			}
+			revs := r.Revisions
+			sort.SliceStable(revs, func(a, b int) bool {
+				return time.Time(revs[a].Ctime).Before(time.Time(revs[b].Ctime))
+			})
+			j.Revisions = append([]gengo.RevisionWithID{}, revs...)
+		}(&jobs[i], &errs[i])
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package report aggregates the job history of a Gengo account into spend and usage reports.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/currency"
+)
+
+// Dimension is what jobs are grouped by.
+type Dimension string
+
+const (
+	// ByMonth groups jobs by the month they were created in.
+	ByMonth = Dimension("month")
+	// ByPair groups jobs by language pair.
+	ByPair = Dimension("pair")
+	// ByTier groups jobs by tier.
+	ByTier = Dimension("tier")
+	// ByTag groups jobs by their custom data.
+	ByTag = Dimension("tag")
+)
+
+// NoTag is the tag of jobs without custom data.
+const NoTag = "(none)"
+
+// ParseDimension parses the name of a dimension.
+func ParseDimension(s string) (Dimension, error) {
+	switch d := Dimension(strings.ToLower(strings.TrimSpace(s))); d {
+	case ByMonth, ByPair, ByTier, ByTag:
+		return d, nil
+	}
+	return "", fmt.Errorf("unknown dimension %q, expected month, pair, tier or tag", s)
+}
+
+// key returns the job's group in the dimension.
+func (d Dimension) key(j *Job) string {
+	switch d {
+	case ByMonth:
+		return j.Month()
+	case ByPair:
+		return fmt.Sprintf("%s→%s", j.Source, j.Target)
+	case ByTier:
+		return string(j.Tier)
+	case ByTag:
+		if j.CustomData == "" {
+			return NoTag
+		}
+		return j.CustomData
+	}
+	return ""
+}
+
+// Row is the usage of a group of jobs.
+type Row struct {
+	// Keys are the group's values in each of the report's dimensions.
+	Keys     []string       `json:"keys"`
+	Jobs     int            `json:"jobs"`
+	Approved int            `json:"approved"`
+	Units    int            `json:"units"`
+	Credits  currency.Money `json:"credits"`
+	Rejected int            `json:"rejected"`
+	// RejectionRate is the share of the jobs which were rejected, from 0 to 1.
+	RejectionRate float64 `json:"rejection_rate"`
+	// Reviewed counts the jobs whose revisions were fetched, and Redelivered those of them delivered more than once.
+	Reviewed    int `json:"reviewed"`
+	Redelivered int `json:"redelivered"`
+	// RedeliveryRate is the share of the Reviewed jobs which were redelivered, from 0 to 1.
+	RedeliveryRate float64 `json:"redelivery_rate"`
+	// AverageTimeToLastDelivery is the mean TimeToLastDelivery of the approved jobs with fetched revisions, or zero.
+	AverageTimeToLastDelivery time.Duration `json:"-"`
+	// AverageHoursToLastDelivery is AverageTimeToLastDelivery in hours, for JSON.
+	AverageHoursToLastDelivery float64 `json:"average_hours_to_last_delivery"`
+
+	deliveries   int
+	deliveryTime time.Duration
+}
+
+func (r *Row) add(j *Job, cur currency.Code) {
+	r.Jobs++
+	if j.Status == gengo.JobStatusApproved {
+		r.Approved++
+	}
+	r.Units += int(j.UnitCount)
+	// Credits are rounded to the currency's digits, so that totals match invoices.
+	r.Credits, _ = r.Credits.Add(currency.FromFloat(float64(j.Credits), cur).Round())
+	if j.Rejected() {
+		r.Rejected++
+	}
+	if j.Revisions != nil {
+		r.Reviewed++
+		if j.Redelivered() {
+			r.Redelivered++
+		}
+	}
+	if d, ok := j.TimeToLastDelivery(); ok {
+		r.deliveries++
+		r.deliveryTime += d
+	}
+}
+
+// finish computes the rates and averages of the row's jobs.
+func (r *Row) finish() {
+	if r.Jobs > 0 {
+		r.RejectionRate = float64(r.Rejected) / float64(r.Jobs)
+	}
+	if r.Reviewed > 0 {
+		r.RedeliveryRate = float64(r.Redelivered) / float64(r.Reviewed)
+	}
+	if r.deliveries > 0 {
+		r.AverageTimeToLastDelivery = r.deliveryTime / time.Duration(r.deliveries)
+		r.AverageHoursToLastDelivery = r.AverageTimeToLastDelivery.Hours()
+	}
+}
+
+// Report is the usage of jobs grouped by one or more dimensions.
+type Report struct {
+	By       []Dimension   `json:"by"`
+	Currency currency.Code `json:"currency,omitempty"`
+	Rows     []*Row        `json:"rows"`
+	Total    *Row          `json:"total"`
+}
+
+// New aggregates jobs by the dimensions, such as New(jobs, "USD", ByMonth, ByPair) for a row per month and
+// language pair. Rows are sorted by their keys. The currency is the account's, which jobs do not state.
+func New(jobs []Job, cur currency.Code, by ...Dimension) *Report {
+	r := &Report{
+		By:       by,
+		Currency: cur,
+		Total:    &Row{Keys: []string{}, Credits: currency.New(0, cur)},
+	}
+	rows := map[string]*Row{}
+	for i := range jobs {
+		j := &jobs[i]
+		keys := make([]string, len(by))
+		for k, d := range by {
+			keys[k] = d.key(j)
+		}
+		id := strings.Join(keys, "\x00")
+		row, ok := rows[id]
+		if !ok {
+			row = &Row{Keys: keys, Credits: currency.New(0, cur)}
+			rows[id] = row
+			r.Rows = append(r.Rows, row)
+		}
+		row.add(j, cur)
+		r.Total.add(j, cur)
+	}
+	for _, row := range r.Rows {
+		row.finish()
+	}
+	r.Total.finish()
+	sort.Slice(r.Rows, func(a, b int) bool {
+		ka, kb := r.Rows[a].Keys, r.Rows[b].Keys
+		for i := range ka {
+			if ka[i] != kb[i] {
+				return ka[i] < kb[i]
+			}
+		}
+		return false
+	})
+	return r
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/internal/gengotest"
+)
+
+// history serves three jobs, each created a day after 2024-01-31 00:00 UTC, listed newest first as Gengo does, or
+// oldest first after timestamp_after with oldestFirst.
+func history(oldestFirst bool) (*gengo.Client, *gengotest.API) {
+	api := gengotest.New(map[string]string{
+		"GET /translate/jobs/3": `{"jobs":[{"job_id":"3","lc_src":"en","lc_tgt":"fr","status":"approved","ctime":1706832000}]}`,
+		"GET /translate/jobs/2,3": `{"jobs":[
+			{"job_id":"2","lc_src":"en","lc_tgt":"ja","tier":"pro","status":"rejected","unit_count":"5","credits":"0.75","ctime":1706745600},
+			{"job_id":"3","lc_src":"en","lc_tgt":"fr","tier":"standard","status":"approved","unit_count":"20","credits":"1.2","ctime":1706832000,"custom_data":"web"}]}`,
+		"GET /translate/jobs/1,2,3": `{"jobs":[
+			{"job_id":"1","lc_src":"en","lc_tgt":"ja","tier":"standard","status":"approved","unit_count":"10","credits":"0.505","ctime":1706659200,"custom_data":"web"},
+			{"job_id":"2","lc_src":"en","lc_tgt":"ja","tier":"pro","status":"rejected","unit_count":"5","credits":"0.75","ctime":1706745600},
+			{"job_id":"3","lc_src":"en","lc_tgt":"fr","tier":"standard","status":"approved","unit_count":"20","credits":"1.2","ctime":1706832000,"custom_data":"web"}]}`,
+		"GET /translate/job/1/revisions": `{"job_id":1,"revisions":[{"rev_id":2,"ctime":1706666400},{"rev_id":1,"ctime":1706662800}]}`,
+		"GET /translate/job/2/revisions": `{"job_id":2,"revisions":[{"rev_id":3,"ctime":1706749200}]}`,
+		"GET /translate/job/3/revisions": `{"job_id":3,"revisions":[{"rev_id":4,"ctime":1706846400}]}`,
+	})
+	api.HandleFunc("GET /translate/jobs", gengotest.Jobs([]gengotest.Job{
+		{ID: 1, Ctime: 1706659200, Status: gengo.JobStatusApproved},
+		{ID: 2, Ctime: 1706745600, Status: gengo.JobStatusRejected},
+		{ID: 3, Ctime: 1706832000, Status: gengo.JobStatusApproved},
+	}, oldestFirst))
+	c := gengo.New("public", "private", gengo.SandboxBaseURL)
+	c.SetRoundTripper(api)
+	return c, api
+}
+
+func TestFetcher(t *testing.T) {
+	c, _ := history(false)
+	jobs, err := NewFetcher(c, WithRevisions()).Jobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 3 || jobs[0].ID != 1 || jobs[2].ID != 3 {
+		t.Fatalf("unexpected jobs %+v", jobs)
+	}
+	if !jobs[0].Redelivered() || jobs[1].Redelivered() {
+		t.Error("only job 1 was redelivered")
+	}
+	if d, ok := jobs[0].TimeToLastDelivery(); !ok || d != 2*time.Hour {
+		t.Errorf("time to last delivery of job 1 = %v, %v", d, ok)
+	}
+	if _, ok := jobs[1].TimeToLastDelivery(); ok {
+		t.Error("a rejected job has no time to last delivery")
+	}
+
+	c, _ = history(false)
+	jobs, err = NewFetcher(c, WithPageSize(2), WithSince(time.Unix(1706745600, 0))).Jobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != 3 || jobs[0].Revisions != nil {
+		t.Errorf("jobs since job 2 = %+v", jobs)
+	}
+
+	// Listed newest first, the jobs older than the first page are out of reach.
+	c, _ = history(false)
+	jobs, err = NewFetcher(c, WithPageSize(2)).Jobs()
+	if _, ok := err.(*gengo.IncompleteListError); !ok || len(jobs) != 2 || jobs[0].ID != 2 {
+		t.Errorf("Jobs() of a history longer than a page = %+v, %v", jobs, err)
+	}
+
+	// Listed oldest first, every page is reached.
+	c, api := history(true)
+	jobs, err = NewFetcher(c, WithPageSize(2)).Jobs()
+	if err != nil || len(jobs) != 3 || jobs[0].ID != 1 || jobs[2].ID != 3 {
+		t.Errorf("Jobs() of a history longer than a page = %+v, %v", jobs, err)
+	}
+	var listed []string
+	for _, req := range api.Requests() {
+		if req.URL.Path == "/v2/translate/jobs" {
+			listed = append(listed, req.URL.Query().Get("timestamp_after"))
+		}
+	}
+	if strings.Join(listed, ",") != ",1706745599,1706831999" {
+		t.Errorf("listed pages after %q", listed)
+	}
+}
+
+func TestReport(t *testing.T) {
+	c, _ := history(false)
+	jobs, err := NewFetcher(c, WithRevisions()).Jobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := New(jobs, "USD", ByMonth, ByTier)
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "month,tier,jobs,approved,units,credits,rejected,rejection_rate,redelivered,redelivery_rate,average_hours_to_last_delivery\n" +
+		"2024-01,standard,1,1,10,0.51,0,0.0000,1,1.0000,2.0\n" +
+		"2024-02,pro,1,0,5,0.75,1,1.0000,0,0.0000,0.0\n" +
+		"2024-02,standard,1,1,20,1.20,0,0.0000,0,0.0000,4.0\n" +
+		"total,,3,2,35,2.46,1,0.3333,1,0.3333,3.0\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := New(jobs, "USD", ByTag).Write(&buf, FormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	wantMarkdown := "| tag | jobs | approved | units | credits | rejected | rejection_rate | redelivered | redelivery_rate | average_hours_to_last_delivery |\n" +
+		"| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n" +
+		"| (none) | 1 | 0 | 5 | 0.75 | 1 | 100.0% | 0 | 0.0% | 0.0 |\n" +
+		"| web | 2 | 2 | 30 | 1.71 | 0 | 0.0% | 1 | 50.0% | 3.0 |\n" +
+		"| **total** | **3** | **2** | **35** | **2.46** | **1** | **33.3%** | **1** | **33.3%** | **3.0** |\n"
+	if buf.String() != wantMarkdown {
+		t.Errorf("Markdown =\n%s\nwant\n%s", buf.String(), wantMarkdown)
+	}
+
+	buf.Reset()
+	if err := New(jobs, "USD", ByPair).Write(&buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"keys": [`, `"en→fr"`, `"credits": "2.46"`, `"average_hours_to_last_delivery": 3`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("JSON is missing %s:\n%s", s, buf.String())
+		}
+	}
+	if err := r.Write(&buf, "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestParseDimension(t *testing.T) {
+	if d, err := ParseDimension(" Pair "); err != nil || d != ByPair {
+		t.Errorf("ParseDimension() = %v, %v", d, err)
+	}
+	if _, err := ParseDimension("week"); err == nil {
+		t.Error("expected an error for an unknown dimension")
+	}
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Output formats of a report.
+const (
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// Write writes the report in the format.
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return r.WriteCSV(w)
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatMarkdown, "md":
+		return r.WriteMarkdown(w)
+	}
+	return fmt.Errorf("unknown report format %q", format)
+}
+
+// header returns the column names: one per dimension, then the measures.
+func (r *Report) header() []string {
+	h := make([]string, 0, len(r.By)+10)
+	for _, d := range r.By {
+		h = append(h, string(d))
+	}
+	return append(h, "jobs", "approved", "units", "credits", "rejected", "rejection_rate",
+		"redelivered", "redelivery_rate", "average_hours_to_last_delivery")
+}
+
+// cells formats a row, with rates and delivery times formatted by the functions.
+func (r *Report) cells(row *Row, keys []string, rate, hours func(float64) string) []string {
+	c := append([]string{}, keys...)
+	return append(c,
+		strconv.Itoa(row.Jobs),
+		strconv.Itoa(row.Approved),
+		strconv.Itoa(row.Units),
+		row.Credits.Amount(),
+		strconv.Itoa(row.Rejected),
+		rate(row.RejectionRate),
+		strconv.Itoa(row.Redelivered),
+		rate(row.RedeliveryRate),
+		hours(row.AverageHoursToLastDelivery),
+	)
+}
+
+// totalKeys labels the total row in the first dimension's column.
+func (r *Report) totalKeys() []string {
+	keys := make([]string, len(r.By))
+	if len(keys) > 0 {
+		keys[0] = "total"
+	}
+	return keys
+}
+
+func fraction(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
+
+func percent(f float64) string {
+	return strconv.FormatFloat(f*100, 'f', 1, 64) + "%"
+}
+
+func decimalHours(h float64) string {
+	return strconv.FormatFloat(h, 'f', 1, 64)
+}
+
+// WriteCSV writes a row per group and a total row. Rates are fractions from 0 to 1.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(r.header())
+	for _, row := range r.Rows {
+		cw.Write(r.cells(row, row.Keys, fraction, decimalHours))
+	}
+	cw.Write(r.cells(r.Total, r.totalKeys(), fraction, decimalHours))
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes the report as a Markdown table with a bold total row. Rates are percentages.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	header := r.header()
+	writeMarkdownRow(&b, header)
+	align := make([]string, len(header))
+	for i := range align {
+		align[i] = "---:"
+		if i < len(r.By) {
+			align[i] = "---"
+		}
+	}
+	writeMarkdownRow(&b, align)
+	for _, row := range r.Rows {
+		writeMarkdownRow(&b, r.cells(row, row.Keys, percent, decimalHours))
+	}
+	total := r.cells(r.Total, r.totalKeys(), percent, decimalHours)
+	for i, c := range total {
+		if c != "" {
+			total[i] = "**" + c + "**"
+		}
+	}
+	writeMarkdownRow(&b, total)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+	for _, c := range cells {
+		b.WriteString(" " + strings.NewReplacer("|", `\|`, "\n", " ").Replace(c) + " |")
+	}
+	b.WriteString("\n")
+}
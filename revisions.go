@@ -0,0 +1,143 @@
+package gengo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trinchan/gengo/diff"
+	"github.com/trinchan/gengo/lang"
+)
+
+// revisionFetchConcurrency limits the number of revisions fetched at once by JobRevisionHistory().
+const revisionFetchConcurrency = 4
+
+// JobRevisionHistoryRequest defines the request parameters for the JobRevisionHistory() helper.
+type JobRevisionHistoryRequest struct {
+	ID int
+}
+
+// NewJobRevisionHistoryRequest creates a new JobRevisionHistoryRequest with the given job id.
+func NewJobRevisionHistoryRequest(id int) *JobRevisionHistoryRequest {
+	return &JobRevisionHistoryRequest{ID: id}
+}
+
+// RevisionHistory defines every revision of a job and the changes between them.
+type RevisionHistory struct {
+	JobID int `json:"job_id"`
+	lang.Pair
+	Revisions []Revision       `json:"revisions"`
+	BodyTgt   string           `json:"body_tgt"`
+	Changes   []RevisionChange `json:"changes"`
+}
+
+// Revision defines a single revision of a job's translation.
+type Revision struct {
+	ID    int    `json:"rev_id"`
+	Body  string `json:"body_tgt"`
+	Ctime Time   `json:"ctime"`
+}
+
+// RevisionChange defines the diff between two revisions.
+// A To of 0 compares the last revision against the job's current BodyTgt.
+type RevisionChange struct {
+	From int       `json:"from_rev_id"`
+	To   int       `json:"to_rev_id"`
+	Diff diff.Diff `json:"diff"`
+}
+
+// JobRevisionHistory retrieves every revision of a job concurrently and diffs each revision against the
+// previous one, and the last revision against the job's final translation.
+// Languages counted in characters, such as Japanese and Chinese, are diffed by character, all others by word.
+func (c *Client) JobRevisionHistory(req *JobRevisionHistoryRequest) (*RevisionHistory, error) {
+	job, err := c.GetJob(NewGetJobRequest(req.ID))
+	if err != nil {
+		return nil, err
+	}
+	revs, err := c.JobRevisions(NewJobRevisionsRequest(req.ID))
+	if err != nil {
+		return nil, err
+	}
+	h := &RevisionHistory{
+		JobID:     req.ID,
+		Pair:      job.Job.Pair,
+		BodyTgt:   job.Job.BodyTgt,
+		Revisions: make([]Revision, len(revs.Revisions)),
+	}
+	errs := make([]error, len(revs.Revisions))
+	sem := make(chan struct{}, revisionFetchConcurrency)
+	var wg sync.WaitGroup
+	for i, rev := range revs.Revisions {
+		wg.Add(1)
+		go func(i int, rev RevisionWithID) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			r, err := c.JobRevision(NewJobRevisionRequest(req.ID, rev.ID))
+			if err != nil {
+				errs[i] = fmt.Errorf("retrieving revision %d: %v", rev.ID, err)
+				return
+			}
+			h.Revisions[i] = Revision{ID: rev.ID, Body: r.Revision.Body, Ctime: rev.Ctime}
+		}(i, rev)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(h.Revisions, func(i, j int) bool {
+		return time.Time(h.Revisions[i].Ctime).Before(time.Time(h.Revisions[j].Ctime))
+	})
+
+	differ := diff.Words
+	if h.Target.UnitType() == lang.UnitCharacter {
+		differ = diff.Chars
+	}
+	for i := 1; i < len(h.Revisions); i++ {
+		prev, cur := h.Revisions[i-1], h.Revisions[i]
+		h.Changes = append(h.Changes, RevisionChange{From: prev.ID, To: cur.ID, Diff: differ(prev.Body, cur.Body)})
+	}
+	if n := len(h.Revisions); n > 0 {
+		last := h.Revisions[n-1]
+		h.Changes = append(h.Changes, RevisionChange{From: last.ID, Diff: differ(last.Body, h.BodyTgt)})
+	}
+	return h, nil
+}
+
+func (rc RevisionChange) title() string {
+	if rc.To == 0 {
+		return fmt.Sprintf("revision %d -> final", rc.From)
+	}
+	return fmt.Sprintf("revision %d -> revision %d", rc.From, rc.To)
+}
+
+// Unified renders every change in the history as inline word diffs.
+func (h *RevisionHistory) Unified() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "job %d (%s -> %s)\n", h.JobID, h.Source, h.Target)
+	for _, rc := range h.Changes {
+		fmt.Fprintf(&b, "\n@@ %s @@\n", rc.title())
+		if !rc.Diff.Changed() {
+			b.WriteString("(no changes)\n")
+			continue
+		}
+		b.WriteString(rc.Diff.Unified())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// HTML renders every change in the history as an HTML fragment.
+func (h *RevisionHistory) HTML() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<div class=\"gengo-revisions\" data-job-id=\"%d\" lang=\"%s\">\n", h.JobID, h.Target)
+	for _, rc := range h.Changes {
+		fmt.Fprintf(&b, "<section>\n<h3>%s</h3>\n<p>%s</p>\n</section>\n", rc.title(), rc.Diff.HTML())
+	}
+	b.WriteString("</div>\n")
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package gengo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJobRevisionHistory(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("GET /translate/job/5", `{"job":{"job_id":"5","lc_src":"ja","lc_tgt":"en","body_tgt":"The quick red fox"}}`)
+	api.Handle("GET /translate/job/5/revisions", `{"job_id":5,"revisions":[{"rev_id":2,"ctime":200},{"rev_id":1,"ctime":100}]}`)
+	api.Handle("GET /translate/job/5/revisions/1", `{"revision":{"body_tgt":"A quick fox","ctime":100}}`)
+	api.Handle("GET /translate/job/5/revisions/2", `{"revision":{"body_tgt":"The quick fox","ctime":200}}`)
+
+	h, err := c.JobRevisionHistory(NewJobRevisionHistoryRequest(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Revisions) != 2 || h.Revisions[0].ID != 1 {
+		t.Fatalf("revisions not sorted by ctime: %+v", h.Revisions)
+	}
+	if len(h.Changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(h.Changes))
+	}
+	if u := h.Changes[0].Diff.Unified(); u != "[-A-]{+The+} quick fox" {
+		t.Errorf("first change = %q", u)
+	}
+	if u := h.Changes[1].Diff.Unified(); u != "The quick {+red +}fox" {
+		t.Errorf("final change = %q", u)
+	}
+	if !strings.Contains(h.Unified(), "@@ revision 2 -> final @@") {
+		t.Errorf("unexpected unified output:\n%s", h.Unified())
+	}
+	if !strings.Contains(h.HTML(), "<ins>red </ins>") {
+		t.Errorf("unexpected HTML output:\n%s", h.HTML())
+	}
+	if _, err := json.Marshal(h); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+}
+
+func TestJobRevisionHistoryCharacters(t *testing.T) {
+	c, api := newFakeClient()
+	api.Handle("GET /translate/job/6", `{"job":{"job_id":"6","lc_src":"en","lc_tgt":"ja","body_tgt":"翻訳したテキスト"}}`)
+	api.Handle("GET /translate/job/6/revisions", `{"job_id":6,"revisions":[{"rev_id":1,"ctime":100}]}`)
+	api.Handle("GET /translate/job/6/revisions/1", `{"revision":{"body_tgt":"翻訳するテキスト","ctime":100}}`)
+
+	h, err := c.JobRevisionHistory(NewJobRevisionHistoryRequest(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u := h.Changes[0].Diff.Unified(); u != "翻訳[-する-]{+した+}テキスト" {
+		t.Errorf("final change = %q", u)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/trinchan/gengo/currency"
 	"github.com/trinchan/gengo/lang"
 )
 
@@ -34,8 +35,8 @@ func (lp *LanguagePairsResponse) UnmarshalJSON(data []byte) error {
 type LanguagePairWithPrice struct {
 	lang.Pair
 	Tier
-	Currency  string  `json:"currency"`
-	UnitPrice Float64 `json:"unit_price"`
+	Currency  currency.Code `json:"currency"`
+	UnitPrice Float64       `json:"unit_price"`
 }
 
 type LanguagePairsRequest struct {
@@ -79,10 +80,10 @@ func (l *LanguagesResponse) UnmarshalJSON(b []byte) error {
 }
 
 type Language struct {
-	UnitType      string `json:"unit_type"`
-	Code          string `json:"lc"`
-	LocalizedName string `json:"localized_name"`
-	Name          string `json:"language"`
+	UnitType      lang.UnitType `json:"unit_type"`
+	Code          lang.Code     `json:"lc"`
+	LocalizedName string        `json:"localized_name"`
+	Name          string        `json:"language"`
 }
 
 func (c *Client) Languages() (*LanguagesResponse, error) {
@@ -104,12 +105,23 @@ type QuoteTextResponse struct {
 }
 
 type TextQuote struct {
-	Type           string  `json:"type"`
-	Credits        Float64 `json:"credits"`
-	ETA            int     `json:"eta"`
-	UnitCount      Int     `json:"unit_count"`
-	DetectedSource string  `json:"lc_src_detected,omitempty"`
-	Currency       string  `json:"currency"`
+	Type           string         `json:"type"`
+	Credits        currency.Money `json:"credits"`
+	ETA            int            `json:"eta"`
+	UnitCount      Int            `json:"unit_count"`
+	DetectedSource string         `json:"lc_src_detected,omitempty"`
+	Currency       currency.Code  `json:"currency"`
+}
+
+func (q *TextQuote) UnmarshalJSON(d []byte) error {
+	type tq TextQuote
+	x := new(tq)
+	if err := json.Unmarshal(d, x); err != nil {
+		return err
+	}
+	*q = TextQuote(*x)
+	q.Credits = q.Credits.In(q.Currency)
+	return nil
 }
 
 func (c *Client) QuoteText(req *QuoteTextRequest) (*QuoteTextResponse, error) {
@@ -140,6 +152,22 @@ type FileQuote struct {
 	Error      *FileQuoteError `json:"err,omitempty"`
 }
 
+func (q *FileQuote) UnmarshalJSON(d []byte) error {
+	// decode the embedded TextQuote and the file fields separately, since TextQuote's UnmarshalJSON would hide the rest
+	if err := json.Unmarshal(d, &q.TextQuote); err != nil {
+		return err
+	}
+	var f struct {
+		Identifier string          `json:"identifier"`
+		Error      *FileQuoteError `json:"err,omitempty"`
+	}
+	if err := json.Unmarshal(d, &f); err != nil {
+		return err
+	}
+	q.Identifier, q.Error = f.Identifier, f.Error
+	return nil
+}
+
 type FileQuoteError struct {
 	Name string `json:"filename"`
 	Code int    `json:"code"`
@@ -22,7 +22,7 @@ func ExampleClient_LanguagePairs_all() {
 // Get all language pairs with a specific source language
 func ExampleClient_LanguagePairs_source() {
 	g := NewFromEnv()
-	req := NewLanguagePairsRequest(WithSource(language.English))
+	req := NewLanguagePairsRequest(WithSource(lang.English))
 	r, err := g.LanguagePairs(req)
 	if err != nil {
 		fmt.Printf("Error retrieving language pairs: %v\n", err)
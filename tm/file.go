@@ -0,0 +1,71 @@
+package tm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileBackend is a Backend storing entries as one JSON object per line in a file.
+type FileBackend struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileBackend creates a new FileBackend stored at path. The file is created on the first Append.
+func NewFileBackend(path string) *FileBackend {
+	return &FileBackend{Path: path}
+}
+
+// Load implements Backend.
+func (b *FileBackend) Load() ([]Entry, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	f, err := os.Open(b.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<24)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("reading translation memory %s line %d: %v", b.Path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Append implements Backend.
+func (b *FileBackend) Append(entries []Entry) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var buf []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	f, err := os.OpenFile(b.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(buf)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
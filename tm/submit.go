@@ -0,0 +1,79 @@
+package tm
+
+import (
+	"github.com/trinchan/gengo"
+)
+
+// Job is the result of a job given to a Submitter.
+type Job struct {
+	gengo.PostJobResponse
+	// Hit is set when the translation came from the memory and the job was not submitted. The job is then
+	// approved, with its translation in BodyTgt and no ID.
+	Hit bool `json:"hit"`
+}
+
+// Result is the result of Submitter.Submit.
+type Result struct {
+	// Order is the response to the submission of the missed jobs, or nil if there were none.
+	Order *gengo.PostJobsResponse `json:"order,omitempty"`
+	// Jobs has the result of each job of the request, in order. Submitted jobs are queued in Order.
+	Jobs []Job `json:"jobs"`
+	// Hits is the number of jobs translated from the memory.
+	Hits int `json:"hits"`
+}
+
+// Submitter submits jobs which a Memory has no translation for.
+type Submitter struct {
+	Client *gengo.Client
+	Memory *Memory
+}
+
+// NewSubmitter creates a Submitter posting jobs with c.
+func NewSubmitter(c *gengo.Client, m *Memory) *Submitter {
+	return &Submitter{Client: c, Memory: m}
+}
+
+// Submit looks up the text jobs of req in the memory and posts the others in one PostJobs() call, with the
+// request's comment. Jobs forcing a new translation are always posted.
+func (s *Submitter) Submit(req *gengo.PostJobsRequest) (*Result, error) {
+	result := &Result{Jobs: make([]Job, len(req.Jobs))}
+	misses := &gengo.PostJobsRequest{GroupComment: req.GroupComment}
+	var missed []int
+	for i, jr := range req.Jobs {
+		job := Job{PostJobResponse: gengo.PostJobResponse{
+			Pair:        jr.Pair,
+			Tier:        jr.Tier,
+			Slug:        jr.Slug,
+			CustomData:  jr.CustomData,
+			CallbackURL: jr.CallbackURL,
+			AutoApprove: jr.AutoApprove,
+		}}
+		if jr.BodySrc != nil {
+			job.BodySrc = *jr.BodySrc
+			if e, ok := s.Memory.Lookup(job.BodySrc, jr.Pair, jr.Tier); ok && !bool(jr.Force) {
+				job.BodyTgt = e.Target
+				job.Status = gengo.JobStatusApproved
+				job.Hit = true
+				result.Jobs[i] = job
+				result.Hits++
+				continue
+			}
+		}
+		result.Jobs[i] = job
+		misses.Jobs = append(misses.Jobs, jr)
+		missed = append(missed, i)
+	}
+	if len(misses.Jobs) == 0 {
+		return result, nil
+	}
+	order, err := s.Client.PostJobs(misses)
+	if err != nil {
+		return nil, err
+	}
+	result.Order = order
+	for _, i := range missed {
+		result.Jobs[i].OrderID = gengo.Int(order.OrderID)
+		result.Jobs[i].Status = gengo.JobStatusQueued
+	}
+	return result, nil
+}
@@ -0,0 +1,240 @@
+// Package tm is a translation memory of approved Gengo translations, so that text which was translated before is
+// not paid for again. Entries are keyed by their normalized source text, language pair and tier.
+package tm
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/diff"
+	"github.com/trinchan/gengo/lang"
+)
+
+// Entry is a source text and its translation.
+type Entry struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	lang.Pair
+	Tier gengo.Tier `json:"tier"`
+	// JobID is the job the translation came from, if any.
+	JobID int       `json:"job_id,omitempty"`
+	Time  time.Time `json:"time"`
+}
+
+// Key identifies the entries which translate the same text the same way.
+type Key struct {
+	Source string
+	Pair   lang.Pair
+	Tier   gengo.Tier
+}
+
+// KeyOf returns the key of a source text.
+func KeyOf(source string, pair lang.Pair, tier gengo.Tier) Key {
+	return Key{Source: Normalize(source), Pair: pair, Tier: tier}
+}
+
+// Key returns the key of the entry.
+func (e Entry) Key() Key {
+	return KeyOf(e.Source, e.Pair, e.Tier)
+}
+
+// Normalize returns s in Unicode normal form C with its whitespace collapsed and trimmed, so that texts differing
+// only in those ways share entries. Case is kept, since it changes translations.
+func Normalize(s string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(s)), " ")
+}
+
+// Match is an entry found by a fuzzy lookup.
+type Match struct {
+	Entry
+	// Score is the similarity of the entry's source text to the text looked up, from 0 to 1 for an exact match.
+	Score float64
+}
+
+// Backend persists the entries of a Memory.
+type Backend interface {
+	// Load returns every entry stored. Later entries replace earlier ones with the same key.
+	Load() ([]Entry, error)
+	Append(entries []Entry) error
+}
+
+// Memory is a translation memory. It is safe for concurrent use.
+type Memory struct {
+	backend Backend
+	mu      sync.RWMutex
+	entries map[Key]Entry
+}
+
+// New creates an empty Memory which only lasts as long as the process.
+func New() *Memory {
+	return &Memory{entries: map[Key]Entry{}}
+}
+
+// Open creates a Memory with the entries of a backend, to which it appends the entries added.
+func Open(b Backend) (*Memory, error) {
+	entries, err := b.Load()
+	if err != nil {
+		return nil, err
+	}
+	m := New()
+	m.put(entries)
+	m.backend = b
+	return m, nil
+}
+
+// OpenFile opens a Memory stored in a file by a FileBackend.
+func OpenFile(path string) (*Memory, error) {
+	return Open(NewFileBackend(path))
+}
+
+// Add adds entries, replacing those with the same key unless they are newer.
+func (m *Memory) Add(entries ...Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	added := m.put(entries)
+	if m.backend == nil || len(added) == 0 {
+		return nil
+	}
+	return m.backend.Append(added)
+}
+
+// put adds entries to the index and returns the ones which were kept.
+func (m *Memory) put(entries []Entry) []Entry {
+	var added []Entry
+	for _, e := range entries {
+		if Normalize(e.Source) == "" || e.Target == "" {
+			continue
+		}
+		k := e.Key()
+		if prev, ok := m.entries[k]; ok && prev.Time.After(e.Time) {
+			continue
+		}
+		m.entries[k] = e
+		added = append(added, e)
+	}
+	return added
+}
+
+// AddJobs adds the translations of approved text jobs and returns how many there were. Other jobs are ignored.
+func (m *Memory) AddJobs(jobs ...gengo.GetJobResponse) (int, error) {
+	var entries []Entry
+	for _, j := range jobs {
+		if j.Status != gengo.JobStatusApproved || j.BodySrc == "" || j.BodyTgt == "" {
+			continue
+		}
+		entries = append(entries, Entry{
+			Source: j.BodySrc,
+			Target: j.BodyTgt,
+			Pair:   j.Pair,
+			Tier:   j.Tier,
+			JobID:  int(j.ID),
+			Time:   time.Time(j.Ctime),
+		})
+	}
+	return len(entries), m.Add(entries...)
+}
+
+// AddApproved retrieves jobs by ID and adds the translations of the approved ones, as AddJobs does.
+func (m *Memory) AddApproved(c *gengo.Client, ids ...int) (int, error) {
+	r, err := c.GetJobsByID(gengo.NewGetJobsByIDRequest(ids...))
+	if err != nil {
+		return 0, err
+	}
+	return m.AddJobs(r.Jobs...)
+}
+
+// Lookup returns the entry translating source exactly, after normalization.
+func (m *Memory) Lookup(source string, pair lang.Pair, tier gengo.Tier) (Entry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.entries[KeyOf(source, pair, tier)]
+	return e, ok
+}
+
+// Fuzzy returns the entries of the pair and tier whose source text is at least min similar to source, best first.
+// Similarity is the share of characters the texts have in common, so that 0.8 finds texts about one character in
+// five apart.
+func (m *Memory) Fuzzy(source string, pair lang.Pair, tier gengo.Tier, min float64) []Match {
+	source = Normalize(source)
+	n := utf8.RuneCountInString(source)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var matches []Match
+	for k, e := range m.entries {
+		if k.Pair != pair || k.Tier != tier {
+			continue
+		}
+		// The texts cannot have more characters in common than the shorter one has.
+		kn := utf8.RuneCountInString(k.Source)
+		if n+kn == 0 || 2*float64(minInt(n, kn))/float64(n+kn) < min {
+			continue
+		}
+		if score := similarity(source, k.Source, n+kn); score >= min {
+			matches = append(matches, Match{Entry: e, Score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Source < matches[j].Source
+	})
+	return matches
+}
+
+// similarity is twice the number of characters a and b have in common over their total number of characters.
+func similarity(a, b string, total int) float64 {
+	if a == b {
+		return 1
+	}
+	common := 0
+	for _, e := range diff.Chars(a, b) {
+		if e.Op == diff.Equal {
+			common += utf8.RuneCountInString(e.Text)
+		}
+	}
+	return 2 * float64(common) / float64(total)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Entries returns every entry, sorted by language pair, tier and source text.
+func (m *Memory) Entries() []Entry {
+	m.mu.RLock()
+	entries := make([]Entry, 0, len(m.entries))
+	for _, e := range m.entries {
+		entries = append(entries, e)
+	}
+	m.mu.RUnlock()
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.Pair.Source != b.Pair.Source:
+			return a.Pair.Source < b.Pair.Source
+		case a.Pair.Target != b.Pair.Target:
+			return a.Pair.Target < b.Pair.Target
+		case a.Tier != b.Tier:
+			return a.Tier < b.Tier
+		}
+		return a.Source < b.Source
+	})
+	return entries
+}
+
+// Len returns the number of entries.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.entries)
+}
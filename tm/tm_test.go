@@ -0,0 +1,103 @@
+package tm
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/internal/gengotest"
+	"github.com/trinchan/gengo/lang"
+)
+
+var enJa = lang.NewPair(lang.English, lang.Japanese)
+
+func TestMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tm.jsonl")
+	m, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	n, err := m.AddJobs(
+		gengo.GetJobResponse{ID: 1, BodySrc: "Save  changes", BodyTgt: "変更を保存", Pair: enJa, Tier: gengo.TierStandard, Status: gengo.JobStatusApproved, Ctime: gengo.Time(day)},
+		gengo.GetJobResponse{ID: 2, BodySrc: "Delete", BodyTgt: "削除", Pair: enJa, Tier: gengo.TierStandard, Status: gengo.JobStatusReviewable},
+		gengo.GetJobResponse{ID: 3, BodySrc: "Save all changes", BodyTgt: "すべての変更を保存", Pair: enJa, Tier: gengo.TierStandard, Status: gengo.JobStatusApproved, Ctime: gengo.Time(day)},
+	)
+	if n != 2 || err != nil {
+		t.Fatalf("AddJobs() = %d, %v, want 2", n, err)
+	}
+	if err := m.Add(Entry{Source: "Save changes", Target: "古い", Pair: enJa, Tier: gengo.TierStandard, Time: day.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() after reopening = %d, want 2", m.Len())
+	}
+	if e, ok := m.Lookup(" Save changes\n", enJa, gengo.TierStandard); !ok || e.Target != "変更を保存" || e.JobID != 1 {
+		t.Errorf("Lookup() = %+v, %v", e, ok)
+	}
+	if _, ok := m.Lookup("Save changes", enJa, gengo.TierPro); ok {
+		t.Error("Lookup() of another tier succeeded")
+	}
+
+	matches := m.Fuzzy("Save the changes", enJa, gengo.TierStandard, 0.8)
+	if len(matches) != 2 || matches[0].Source != "Save  changes" || matches[0].Score < matches[1].Score {
+		t.Errorf("Fuzzy() = %+v", matches)
+	}
+	if matches := m.Fuzzy("Open", enJa, gengo.TierStandard, 0.5); len(matches) != 0 {
+		t.Errorf("Fuzzy() of unrelated text = %+v", matches)
+	}
+}
+
+func TestSubmitter(t *testing.T) {
+	m := New()
+	if err := m.Add(Entry{Source: "Hello", Target: "こんにちは", Pair: enJa, Tier: gengo.TierStandard}); err != nil {
+		t.Fatal(err)
+	}
+	api := gengotest.New(map[string]string{
+		"POST /translate/jobs": `{"order_id":7,"job_count":1,"credits_used":"0.50","currency":"USD"}`,
+	})
+	c := gengo.New("public", "private", gengo.SandboxBaseURL)
+	c.SetRoundTripper(api)
+	// posted returns the source texts of the jobs posted in the i-th request.
+	posted := func(i int) []string {
+		var body struct {
+			Jobs []gengo.JobRequest `json:"jobs"`
+		}
+		api.Data(t, i, &body)
+		var texts []string
+		for _, j := range body.Jobs {
+			texts = append(texts, *j.BodySrc)
+		}
+		return texts
+	}
+
+	req := gengo.NewPostJobsRequest([]*gengo.JobRequest{
+		gengo.NewJobRequest("Hello", enJa, gengo.TierStandard, gengo.WithSlug("greeting")),
+		gengo.NewJobRequest("Goodbye", enJa, gengo.TierStandard),
+	})
+	r, err := NewSubmitter(c, m).Submit(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posted := posted(0); len(posted) != 1 || posted[0] != "Goodbye" {
+		t.Errorf("posted %q, want only Goodbye", posted)
+	}
+	hit, miss := r.Jobs[0], r.Jobs[1]
+	if r.Hits != 1 || !hit.Hit || hit.BodyTgt != "こんにちは" || hit.Status != gengo.JobStatusApproved || hit.Slug != "greeting" {
+		t.Errorf("hit = %+v", hit)
+	}
+	if miss.Hit || miss.OrderID != 7 || miss.Status != gengo.JobStatusQueued || r.Order.OrderID != 7 {
+		t.Errorf("miss = %+v, order = %+v", miss, r.Order)
+	}
+
+	r, err = NewSubmitter(c, m).Submit(gengo.NewPostJobsRequest([]*gengo.JobRequest{gengo.NewJobRequest("hello ", enJa, gengo.TierStandard)}))
+	if err != nil || r.Hits != 0 || api.Len() != 2 || len(posted(1)) != 1 {
+		t.Errorf("Submit() of a case-changed text = %+v, %v after %d requests", r, err, api.Len())
+	}
+}
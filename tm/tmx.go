@@ -0,0 +1,188 @@
+package tm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+)
+
+const (
+	// tmxTime is the format of TMX dates.
+	tmxTime = "20060102T150405Z"
+	// tmxTierProp is the property recording the tier of a translation unit.
+	tmxTierProp = "x-gengo-tier"
+)
+
+type tmxDocument struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	TMF                 string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SourceLang          string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+}
+
+type tmxUnit struct {
+	SourceLang   string       `xml:"srclang,attr,omitempty"`
+	CreationDate string       `xml:"creationdate,attr,omitempty"`
+	ID           string       `xml:"tuid,attr,omitempty"`
+	Props        []tmxProp    `xml:"prop"`
+	Variants     []tmxVariant `xml:"tuv"`
+}
+
+type tmxProp struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tmxVariant struct {
+	Lang    string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	OldLang string `xml:"lang,attr,omitempty"`
+	Seg     tmxSeg `xml:"seg"`
+}
+
+func (v tmxVariant) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.OldLang
+}
+
+// tmxSeg is the text of a segment. Inline elements such as <ph> and <bpt> hold the native code of placeholders
+// and markup, so their text is kept.
+type tmxSeg string
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+func (s *tmxSeg) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var b strings.Builder
+	for depth := 1; depth > 0; {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+	*s = tmxSeg(b.String())
+	return nil
+}
+
+// ReadTMX reads the entries of a TMX 1.4 document. The source language of each unit is its srclang, or the header's,
+// or the language of its first variant if both are "*all*". Every other variant is an entry. Language tags are
+// mapped to the closest Gengo language, and the tier is read from an x-gengo-tier property, or is tier.
+func ReadTMX(r io.Reader, tier gengo.Tier) ([]Entry, error) {
+	doc := new(tmxDocument)
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("reading tmx: %v", err)
+	}
+	var entries []Entry
+	for i, u := range doc.Units {
+		if len(u.Variants) == 0 {
+			continue
+		}
+		sourceLang := u.SourceLang
+		if sourceLang == "" {
+			sourceLang = doc.Header.SourceLang
+		}
+		if sourceLang == "" || sourceLang == "*all*" {
+			sourceLang = u.Variants[0].lang()
+		}
+		source, err := lang.Parse(sourceLang)
+		if err != nil {
+			return nil, fmt.Errorf("reading tmx unit %d: %v", i+1, err)
+		}
+		var created time.Time
+		if u.CreationDate != "" {
+			if created, err = time.Parse(tmxTime, u.CreationDate); err != nil {
+				return nil, fmt.Errorf("reading tmx unit %d: %v", i+1, err)
+			}
+		}
+		unitTier := tier
+		for _, p := range u.Props {
+			if p.Type == tmxTierProp {
+				unitTier = gengo.Tier(strings.TrimSpace(p.Value))
+			}
+		}
+		var text string
+		var targets []Entry
+		for _, v := range u.Variants {
+			code, err := lang.Parse(v.lang())
+			if err != nil {
+				return nil, fmt.Errorf("reading tmx unit %d: %v", i+1, err)
+			}
+			switch {
+			case code != source:
+				targets = append(targets, Entry{Target: string(v.Seg), Pair: lang.NewPair(source, code), Tier: unitTier, Time: created})
+			case text == "":
+				text = string(v.Seg)
+			}
+		}
+		for _, e := range targets {
+			e.Source = text
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// WriteTMX writes entries as a TMX 1.4 document, with their tier as an x-gengo-tier property.
+func WriteTMX(w io.Writer, entries []Entry) error {
+	doc := tmxDocument{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "github.com/trinchan/gengo/tm",
+			CreationToolVersion: "1",
+			SegType:             "block",
+			TMF:                 "gengo",
+			AdminLang:           "en",
+			SourceLang:          "*all*",
+			DataType:            "plaintext",
+		},
+	}
+	for _, e := range entries {
+		u := tmxUnit{
+			SourceLang: string(e.Pair.Source),
+			Props:      []tmxProp{{Type: tmxTierProp, Value: string(e.Tier)}},
+			Variants: []tmxVariant{
+				{Lang: string(e.Pair.Source), Seg: tmxSeg(e.Source)},
+				{Lang: string(e.Pair.Target), Seg: tmxSeg(e.Target)},
+			},
+		}
+		if e.JobID != 0 {
+			u.ID = fmt.Sprintf("job-%d", e.JobID)
+		}
+		if !e.Time.IsZero() {
+			u.CreationDate = e.Time.UTC().Format(tmxTime)
+		}
+		doc.Units = append(doc.Units, u)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,85 @@
+package tm
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+)
+
+const tmx = `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="Example" creationtoolversion="1" segtype="sentence" o-tmf="x" adminlang="en-US" srclang="en-US" datatype="plaintext"/>
+  <body>
+    <tu creationdate="20240102T030405Z">
+      <prop type="x-gengo-tier">pro</prop>
+      <tuv xml:lang="en-US"><seg>Click <bpt i="1">&lt;b&gt;</bpt>here<ept i="1">&lt;/b&gt;</ept></seg></tuv>
+      <tuv xml:lang="ja-JP"><seg><bpt i="1">&lt;b&gt;</bpt>ここ<ept i="1">&lt;/b&gt;</ept>をクリック</seg></tuv>
+      <tuv xml:lang="fr-FR"><seg>Cliquez <bpt i="1">&lt;b&gt;</bpt>ici<ept i="1">&lt;/b&gt;</ept></seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en"><seg>Bye</seg></tuv>
+      <tuv xml:lang="ja"><seg>さようなら</seg></tuv>
+    </tu>
+  </body>
+</tmx>`
+
+func TestReadTMX(t *testing.T) {
+	entries, err := ReadTMX(strings.NewReader(tmx), gengo.TierStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := []Entry{
+		{Source: "Click <b>here</b>", Target: "<b>ここ</b>をクリック", Pair: enJa, Tier: gengo.TierPro, Time: created},
+		{Source: "Click <b>here</b>", Target: "Cliquez <b>ici</b>", Pair: lang.NewPair(lang.English, lang.French), Tier: gengo.TierPro, Time: created},
+		{Source: "Bye", Target: "さようなら", Pair: enJa, Tier: gengo.TierStandard},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("ReadTMX() = %+v\nwant %+v", entries, want)
+	}
+	if _, err := ReadTMX(strings.NewReader(`<tmx><body><tu><tuv xml:lang="xx-bogus"><seg>a</seg></tuv></tu></body></tmx>`), gengo.TierStandard); err == nil {
+		t.Error("ReadTMX() of an unknown language succeeded")
+	}
+}
+
+func TestWriteTMX(t *testing.T) {
+	entries := []Entry{
+		{Source: "a < b & c", Target: "a < b & c だ", Pair: enJa, Tier: gengo.TierStandard, JobID: 9, Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	var buf bytes.Buffer
+	if err := WriteTMX(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="github.com/trinchan/gengo/tm" creationtoolversion="1" segtype="block" o-tmf="gengo" adminlang="en" srclang="*all*" datatype="plaintext"></header>
+  <body>
+    <tu srclang="en" creationdate="20240102T030405Z" tuid="job-9">
+      <prop type="x-gengo-tier">standard</prop>
+      <tuv xml:lang="en">
+        <seg>a &lt; b &amp; c</seg>
+      </tuv>
+      <tuv xml:lang="ja">
+        <seg>a &lt; b &amp; c だ</seg>
+      </tuv>
+    </tu>
+  </body>
+</tmx>
+`
+	if buf.String() != want {
+		t.Errorf("WriteTMX() =\n%s\nwant\n%s", buf.String(), want)
+	}
+	read, err := ReadTMX(&buf, gengo.TierPro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries[0].JobID = 0
+	if !reflect.DeepEqual(read, entries) {
+		t.Errorf("ReadTMX() of WriteTMX() = %+v\nwant %+v", read, entries)
+	}
+}
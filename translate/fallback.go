@@ -0,0 +1,117 @@
+package translate
+
+import (
+	"sync"
+)
+
+// Fallback translates text by machine right away and by Gengo in the background, replacing the machine
+// translation once the human one is approved.
+type Fallback struct {
+	Machine Translator
+	Human   *Gengo
+	// OnHuman, if set, is called from the background with the human translation of each request once approved.
+	OnHuman func(req Request, r *Result)
+}
+
+// NewFallback creates a Fallback from a machine translator and Gengo.
+func NewFallback(machine Translator, human *Gengo) *Fallback {
+	return &Fallback{Machine: machine, Human: human}
+}
+
+// Start translates req by machine and returns, then submits it to Gengo and follows the job in the background.
+// It returns an error, and submits nothing, if the machine translation fails. A failed submission is reported by
+// Err once Done is closed.
+func (f *Fallback) Start(req Request) (*Handle, error) {
+	machine, err := f.Machine.Translate(req)
+	if err != nil {
+		return nil, err
+	}
+	h := &Handle{
+		result: machine,
+		done:   make(chan struct{}),
+		stop:   make(chan struct{}),
+	}
+	go func() {
+		defer close(h.done)
+		select {
+		case <-h.stop:
+			h.finish(nil, ErrStopped)
+			return
+		default:
+		}
+		job, err := f.Human.Submit(req)
+		if err != nil {
+			h.finish(nil, err)
+			return
+		}
+		h.mu.Lock()
+		h.job = job
+		h.mu.Unlock()
+		human, err := f.Human.Wait(job, h.stop)
+		h.finish(human, err)
+		if human != nil && f.OnHuman != nil {
+			f.OnHuman(req, human)
+		}
+	}()
+	return h, nil
+}
+
+// Handle follows the translation of a text started by a Fallback.
+type Handle struct {
+	mu       sync.Mutex
+	job      *Job
+	result   *Result
+	err      error
+	done     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Job returns the Gengo job of the translation once submitted, or nil.
+func (h *Handle) Job() *Job {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.job
+}
+
+// finish records the outcome of the human translation.
+func (h *Handle) finish(human *Result, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if human != nil {
+		h.result = human
+	}
+	h.err = err
+}
+
+// Result returns the best translation so far: the machine translation until the human one is approved.
+func (h *Handle) Result() *Result {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.result
+}
+
+// Done is closed once the human translation is approved, or submitting or following the job failed or was stopped.
+func (h *Handle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Err returns why the human translation is missing once Done is closed, or nil.
+func (h *Handle) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// Wait waits until Done is closed and returns the final translation, which is the machine translation if the
+// human one failed, and Err.
+func (h *Handle) Wait() (*Result, error) {
+	<-h.done
+	return h.Result(), h.Err()
+}
+
+// Stop stops following the job, which stays submitted, or submitting it if not submitted yet. Unless the human
+// translation was already approved, Wait then returns ErrStopped with the machine translation.
+func (h *Handle) Stop() {
+	h.stopOnce.Do(func() { close(h.stop) })
+}
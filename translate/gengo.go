@@ -0,0 +1,169 @@
+package translate
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trinchan/gengo"
+)
+
+const (
+	// DefaultInterval is the time between polls of a Gengo job.
+	DefaultInterval = 30 * time.Second
+	// maxBackoff limits how many times the interval Wait waits after failed polls.
+	maxBackoff = 16
+)
+
+var (
+	// ErrCanceled is returned when the Gengo job of a translation was canceled.
+	ErrCanceled = errors.New("translate: job was canceled")
+	// ErrTimeout is returned when a Gengo job was not approved in time.
+	ErrTimeout = errors.New("translate: timed out waiting for approval")
+	// ErrStopped is returned when waiting for a Gengo job was stopped.
+	ErrStopped = errors.New("translate: stopped")
+)
+
+// Gengo is a Translator submitting text jobs to Gengo and waiting for their approval.
+type Gengo struct {
+	Client *gengo.Client
+	// Interval is the time between polls of a job.
+	Interval time.Duration
+	// Timeout limits how long Translate waits for a job to be approved. Zero waits forever.
+	Timeout time.Duration
+	// MaxErrors is the number of polls in a row which may fail before Wait returns the error. Zero retries failed
+	// polls until the Timeout.
+	MaxErrors int
+}
+
+// GengoOption configures a Gengo translator.
+type GengoOption func(*Gengo)
+
+// WithInterval sets the time between polls of a job.
+func WithInterval(d time.Duration) GengoOption {
+	return func(g *Gengo) {
+		g.Interval = d
+	}
+}
+
+// WithTimeout limits how long Translate waits for a job to be approved.
+func WithTimeout(d time.Duration) GengoOption {
+	return func(g *Gengo) {
+		g.Timeout = d
+	}
+}
+
+// WithMaxErrors makes Wait return the error of a poll once n polls in a row failed.
+func WithMaxErrors(n int) GengoOption {
+	return func(g *Gengo) {
+		g.MaxErrors = n
+	}
+}
+
+// NewGengo creates a Gengo translator submitting jobs with c.
+func NewGengo(c *gengo.Client, options ...GengoOption) *Gengo {
+	g := &Gengo{Client: c, Interval: DefaultInterval}
+	for _, option := range options {
+		option(g)
+	}
+	return g
+}
+
+// Job is a text submitted to Gengo.
+type Job struct {
+	Request Request
+	OrderID int
+}
+
+// Submit posts the text of req as a job of its own order.
+func (g *Gengo) Submit(req Request) (*Job, error) {
+	jr := gengo.NewJobRequest(req.Text, req.Pair, req.Tier, req.Options...)
+	r, err := g.Client.PostJobs(gengo.NewPostJobsRequest([]*gengo.JobRequest{jr}))
+	if err != nil {
+		return nil, err
+	}
+	return &Job{Request: req, OrderID: r.OrderID}, nil
+}
+
+// Poll returns the translation of a job once it is approved, or nil until then, including while the job is held.
+// It returns ErrCanceled if the job was canceled.
+func (g *Gengo) Poll(job *Job) (*Result, error) {
+	r, err := g.Client.GetOrder(gengo.NewOrderGetRequest(job.OrderID))
+	if err != nil {
+		return nil, err
+	}
+	order := r.Order
+	switch {
+	case order.Status() == gengo.OrderStatusCanceled:
+		return nil, ErrCanceled
+	case len(order.JobsApproved) == 0:
+		return nil, nil
+	}
+	jobs, err := g.Client.GetJobsByID(gengo.NewGetJobsByIDRequest(int(order.JobsApproved[0])))
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs.Jobs) == 0 {
+		return nil, fmt.Errorf("translate: job %d of order %d not found", order.JobsApproved[0], job.OrderID)
+	}
+	j := jobs.Jobs[0]
+	return &Result{Text: j.BodyTgt, OrderID: job.OrderID, JobID: int(j.ID)}, nil
+}
+
+// Wait polls a job until it is approved or canceled, the Timeout passes or stop is closed. Failed polls are retried
+// at growing intervals, up to MaxErrors in a row if set.
+func (g *Gengo) Wait(job *Job, stop <-chan struct{}) (*Result, error) {
+	var timeout <-chan time.Time
+	if g.Timeout > 0 {
+		t := time.NewTimer(g.Timeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	interval := g.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	errs := 0
+	for {
+		r, err := g.Poll(job)
+		switch {
+		case r != nil || err == ErrCanceled:
+			return r, err
+		case err == nil:
+			errs = 0
+		default:
+			errs++
+			if g.MaxErrors > 0 && errs >= g.MaxErrors {
+				return nil, err
+			}
+		}
+		t := time.NewTimer(interval * time.Duration(backoff(errs)))
+		select {
+		case <-t.C:
+		case <-timeout:
+			t.Stop()
+			return nil, ErrTimeout
+		case <-stop:
+			t.Stop()
+			return nil, ErrStopped
+		}
+	}
+}
+
+// Translate implements Translator. It submits the text and waits for its approval.
+func (g *Gengo) Translate(req Request) (*Result, error) {
+	job, err := g.Submit(req)
+	if err != nil {
+		return nil, err
+	}
+	return g.Wait(job, nil)
+}
+
+// backoff returns how many times the interval to wait after a number of failed polls in a row.
+func backoff(errs int) int {
+	n := 1
+	for ; errs > 0 && n < maxBackoff; errs-- {
+		n *= 2
+	}
+	return n
+}
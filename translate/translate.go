@@ -0,0 +1,80 @@
+// Package translate translates text through interchangeable providers, such as Gengo's human translators or a
+// machine translation service, and can return a machine translation until the human one is approved.
+package translate
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/lang"
+)
+
+// Request is a text to translate.
+type Request struct {
+	Text string
+	lang.Pair
+	Tier gengo.Tier
+	// Options are applied to the Gengo job of the text. Machine translators ignore them.
+	Options []gengo.JobOption
+}
+
+// Result is a translation.
+type Result struct {
+	Text string `json:"text"`
+	// Machine is set for machine translations.
+	Machine bool `json:"machine"`
+	// OrderID and JobID are those of the Gengo job of a human translation.
+	OrderID int `json:"order_id,omitempty"`
+	JobID   int `json:"job_id,omitempty"`
+}
+
+// Translator translates text.
+type Translator interface {
+	Translate(req Request) (*Result, error)
+}
+
+// MachineFunc adapts a machine translation service to the Translator interface.
+type MachineFunc func(text string, pair lang.Pair) (string, error)
+
+// Translate implements Translator.
+func (f MachineFunc) Translate(req Request) (*Result, error) {
+	text, err := f(req.Text, req.Pair)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Text: text, Machine: true}, nil
+}
+
+// Stub is a local machine Translator for tests. It returns the text of Translations keyed by source text, or the
+// source text prefixed with the target language, such as "[ja] Hello". It is safe for concurrent use.
+type Stub struct {
+	Translations map[string]string
+	// Err is returned instead of a translation if set.
+	Err error
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+// Translate implements Translator.
+func (s *Stub) Translate(req Request) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	text, ok := s.Translations[req.Text]
+	if !ok {
+		text = fmt.Sprintf("[%s] %s", req.Target, req.Text)
+	}
+	return &Result{Text: text, Machine: true}, nil
+}
+
+// Requests returns the requests translated so far.
+func (s *Stub) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
@@ -0,0 +1,164 @@
+package translate
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trinchan/gengo"
+	"github.com/trinchan/gengo/internal/gengotest"
+	"github.com/trinchan/gengo/lang"
+)
+
+var enJa = lang.NewPair(lang.English, lang.Japanese)
+
+// flaky fails the first polls of an order as an unreachable server would.
+type flaky struct {
+	http.RoundTripper
+	mu    sync.Mutex
+	fails int
+}
+
+func (f *flaky) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	fail := f.fails > 0 && strings.Contains(req.URL.Path, "/translate/order/")
+	if fail {
+		f.fails--
+	}
+	f.mu.Unlock()
+	if fail {
+		return nil, errors.New("connection reset")
+	}
+	return f.RoundTripper.RoundTrip(req)
+}
+
+// fakeGengo answers for one order whose job is held, then approved after a number of polls, or canceled if
+// approveAfter is negative. The first fails polls fail.
+func fakeGengo(t *testing.T, approveAfter, fails int) (*gengo.Client, func() int) {
+	var mu sync.Mutex
+	polls := 0
+	api := gengotest.New(map[string]string{
+		"POST /translate/jobs":  `{"order_id":5,"job_count":1,"credits_used":"0.50","currency":"USD"}`,
+		"GET /translate/jobs/9": `{"jobs":[{"job_id":"9","order_id":"5","status":"approved","body_src":"Hello","body_tgt":"こんにちは"}]}`,
+	})
+	api.HandleFunc("GET /translate/order/5", func(*http.Request) string {
+		mu.Lock()
+		defer mu.Unlock()
+		polls++
+		switch {
+		case approveAfter < 0:
+			return `{"order":{"order_id":"5","jobs_cancelled":["9"]}}`
+		case polls > approveAfter:
+			return `{"order":{"order_id":"5","jobs_approved":["9"]}}`
+		case polls == 1:
+			return `{"order":{"order_id":"5","jobs_held":["9"]}}`
+		}
+		return `{"order":{"order_id":"5","jobs_pending":["9"]}}`
+	})
+	c := gengo.New("public", "private", gengo.SandboxBaseURL)
+	c.SetRoundTripper(&flaky{RoundTripper: api, fails: fails})
+	return c, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return polls
+	}
+}
+
+func TestGengo(t *testing.T) {
+	c, polls := fakeGengo(t, 2, 0)
+	g := NewGengo(c, WithInterval(time.Millisecond))
+	r, err := g.Translate(Request{Text: "Hello", Pair: enJa, Tier: gengo.TierStandard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *r != (Result{Text: "こんにちは", OrderID: 5, JobID: 9}) || polls() != 3 {
+		t.Errorf("Translate() = %+v after %d polls", r, polls())
+	}
+
+	c, _ = fakeGengo(t, -1, 0)
+	if _, err := NewGengo(c).Translate(Request{Text: "Hello", Pair: enJa, Tier: gengo.TierStandard}); err != ErrCanceled {
+		t.Errorf("Translate() of a canceled job = %v, want ErrCanceled", err)
+	}
+
+	c, _ = fakeGengo(t, 1000, 0)
+	g = NewGengo(c, WithInterval(time.Millisecond), WithTimeout(20*time.Millisecond))
+	if _, err := g.Translate(Request{Text: "Hello", Pair: enJa, Tier: gengo.TierStandard}); err != ErrTimeout {
+		t.Errorf("Translate() = %v, want ErrTimeout", err)
+	}
+
+	// Failed polls are retried, until MaxErrors of them failed in a row.
+	c, polls = fakeGengo(t, 1, 3)
+	if r, err := NewGengo(c, WithInterval(time.Millisecond)).Translate(Request{Text: "Hello", Pair: enJa}); err != nil || r.JobID != 9 || polls() != 2 {
+		t.Errorf("Translate() with failing polls = %+v, %v after %d polls", r, err, polls())
+	}
+	c, polls = fakeGengo(t, 1, 3)
+	g = NewGengo(c, WithInterval(time.Millisecond), WithMaxErrors(3))
+	if _, err := g.Translate(Request{Text: "Hello", Pair: enJa}); err == nil || !strings.Contains(err.Error(), "connection reset") || polls() != 0 {
+		t.Errorf("Translate() with MaxErrors = %v after %d polls", err, polls())
+	}
+}
+
+func TestStub(t *testing.T) {
+	s := &Stub{Translations: map[string]string{"Hello": "こんにちは"}}
+	for text, want := range map[string]string{"Hello": "こんにちは", "Bye": "[ja] Bye"} {
+		if r, err := s.Translate(Request{Text: text, Pair: enJa}); err != nil || r.Text != want || !r.Machine {
+			t.Errorf("Translate(%q) = %+v, %v, want %q", text, r, err, want)
+		}
+	}
+	if len(s.Requests()) != 2 {
+		t.Errorf("Requests() = %+v", s.Requests())
+	}
+}
+
+func TestFallback(t *testing.T) {
+	c, _ := fakeGengo(t, 1, 0)
+	var swapped []*Result
+	f := NewFallback(&Stub{}, NewGengo(c, WithInterval(10*time.Millisecond)))
+	f.OnHuman = func(req Request, r *Result) {
+		swapped = append(swapped, r)
+	}
+	h, err := f.Start(Request{Text: "Hello", Pair: enJa, Tier: gengo.TierStandard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := h.Result(); r.Text != "[ja] Hello" || !r.Machine {
+		t.Errorf("Result() before approval = %+v", r)
+	}
+	r, err := h.Wait()
+	if err != nil || r.Text != "こんにちは" || r.Machine || r.JobID != 9 {
+		t.Errorf("Wait() = %+v, %v", r, err)
+	}
+	if len(swapped) != 1 || swapped[0] != r {
+		t.Errorf("OnHuman got %+v", swapped)
+	}
+
+	c, _ = fakeGengo(t, 1000, 0)
+	h, err = NewFallback(&Stub{}, NewGengo(c, WithInterval(time.Millisecond))).Start(Request{Text: "Hello", Pair: enJa})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Stop()
+	h.Stop()
+	if r, err := h.Wait(); err != ErrStopped || !r.Machine {
+		t.Errorf("Wait() after Stop() = %+v, %v", r, err)
+	}
+
+	// A failed submission leaves the machine translation.
+	c = gengo.New("public", "private", gengo.SandboxBaseURL)
+	c.SetRoundTripper(gengotest.New(nil))
+	h, err = NewFallback(&Stub{}, NewGengo(c)).Start(Request{Text: "Hello", Pair: enJa})
+	if err != nil {
+		t.Fatalf("Start() with a failing submission = %v", err)
+	}
+	if r, err := h.Wait(); err == nil || !r.Machine || r.Text != "[ja] Hello" || h.Job() != nil {
+		t.Errorf("Wait() after a failed submission = %+v, %v, job %+v", r, err, h.Job())
+	}
+
+	mtErr := errors.New("mt down")
+	if _, err := NewFallback(&Stub{Err: mtErr}, NewGengo(c)).Start(Request{Text: "Hello", Pair: enJa}); err != mtErr {
+		t.Errorf("Start() with a failing machine translator = %v", err)
+	}
+}
@@ -1,42 +1,56 @@
 package gengo
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// scalar returns a JSON string or number as a string, so that both forms can be parsed alike.
+// null is reported so that the value is left unchanged, as encoding/json does for other types.
+func scalar(d []byte, kind string) (s string, null bool, err error) {
+	d = bytes.TrimSpace(d)
+	switch {
+	case string(d) == "null":
+		return "", true, nil
+	case len(d) > 0 && d[0] == '"':
+		err := json.Unmarshal(d, &s)
+		return strings.TrimSpace(s), false, err
+	case string(d) == "true" || string(d) == "false":
+		return string(d), false, nil
+	case len(d) > 0 && (d[0] == '-' || d[0] >= '0' && d[0] <= '9') && json.Valid(d):
+		return string(d), false, nil
+	}
+	return "", false, fmt.Errorf("unknown type for gengo %s: %s", kind, d)
+}
+
 // Float64 is a float64 which can support string and float64 forms because of inconsistencies in the Gengo API response.
 type Float64 float64
 
 // MarshalJSON implements the Marshaler interface for Float64.
-func (g *Float64) MarshalJSON() ([]byte, error) {
-	if g == nil {
-		return []byte("null"), nil
-	}
-	return []byte(fmt.Sprintf(`"%.2f"`, float64(*g))), nil
+func (g Float64) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatFloat(float64(g), 'f', -1, 64) + `"`), nil
 }
 
-// UnmarshalJSON implements the Unmarshaler interface for Float64.
+// UnmarshalJSON implements the Unmarshaler interface for Float64. An empty string is zero.
 func (g *Float64) UnmarshalJSON(d []byte) error {
-	var f interface{}
-	err := json.Unmarshal(d, &f)
-	if err != nil {
+	s, null, err := scalar(d, "float")
+	if err != nil || null {
 		return err
 	}
-	switch t := f.(type) {
-	case string:
-		f, err := strconv.ParseFloat(t, 64)
-		if err != nil {
-			return err
-		}
-		*g = Float64(f)
-	case float64:
-		*g = Float64(t)
-	default:
-		return fmt.Errorf("unknown type for gengo float (%T) %v", t, t)
+	if s == "" {
+		*g = 0
+		return nil
 	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Errorf("cannot parse gengo float: %s", d)
+	}
+	*g = Float64(f)
 	return nil
 }
 
@@ -44,40 +58,30 @@ func (g *Float64) UnmarshalJSON(d []byte) error {
 type Int int
 
 // MarshalJSON implements the Marshaler interface for Int.
-func (g *Int) MarshalJSON() ([]byte, error) {
-	if g == nil {
-		return []byte("null"), nil
-	}
-	return []byte(fmt.Sprintf(`"%d"`, int(*g))), nil
+func (g Int) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.Itoa(int(g)) + `"`), nil
 }
 
 // UnmarshalJSON implements the Unmarshaler interface for Int.
+// Decimal forms such as "12.0" are accepted and truncated toward zero. An empty string is zero.
 func (g *Int) UnmarshalJSON(d []byte) error {
-	var f interface{}
-	err := json.Unmarshal(d, &f)
-	if err != nil {
+	s, null, err := scalar(d, "int")
+	if err != nil || null {
 		return err
 	}
-	switch t := f.(type) {
-	case string:
-		i, err := strconv.Atoi(t)
-		if err != nil {
-			return err
-		}
+	if s == "" {
+		*g = 0
+		return nil
+	}
+	if i, err := strconv.ParseInt(s, 10, strconv.IntSize); err == nil {
 		*g = Int(i)
-	case int64:
-		*g = Int(int(t))
-	case int:
-		*g = Int(t)
-	case int32:
-		*g = Int(int(t))
-	case float64:
-		*g = Int(int(t))
-	case float32:
-		*g = Int(int(t))
-	default:
-		return fmt.Errorf("unknown type for gengo int (%T) %v", t, t)
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || f < math.MinInt || f >= -math.MinInt {
+		return fmt.Errorf("cannot parse gengo int: %s", d)
 	}
+	*g = Int(f)
 	return nil
 }
 
@@ -85,56 +89,32 @@ func (g *Int) UnmarshalJSON(d []byte) error {
 type Bool bool
 
 // MarshalJSON implements the Marshaler interface for Bool.
-func (g *Bool) MarshalJSON() ([]byte, error) {
-	if g == nil {
-		return []byte("null"), nil
-	}
-	if *g {
+func (g Bool) MarshalJSON() ([]byte, error) {
+	if g {
 		return []byte(`"1"`), nil
 	}
 	return []byte(`"0"`), nil
 }
 
-// UnmarshalJSON implements the Unmarshaler interface for Bool.
+// UnmarshalJSON implements the Unmarshaler interface for Bool. Non-zero numbers are true and an empty string is false.
 func (g *Bool) UnmarshalJSON(d []byte) error {
-	var f interface{}
-	err := json.Unmarshal(d, &f)
-	if err != nil {
+	s, null, err := scalar(d, "bool")
+	if err != nil || null {
 		return err
 	}
-	switch t := f.(type) {
-	case string:
-		f, err := strconv.ParseBool(t)
-		if err != nil {
-			i, err := strconv.Atoi(t)
-			if err != nil {
-				return fmt.Errorf("cannot parse gengo string to bool: %s", t)
-			}
-			if i != 0 {
-				*g = Bool(true)
-				return nil
-			}
-			*g = Bool(false)
-			return nil
-		}
-		*g = Bool(f)
-	case bool:
-		*g = Bool(t)
-	case int:
-		if t == 1 {
-			*g = Bool(true)
-		} else {
-			*g = Bool(false)
-		}
-	case float64:
-		if int(t) == 1 {
-			*g = Bool(true)
-		} else {
-			*g = Bool(false)
-		}
-	default:
-		return fmt.Errorf("unknown type for gengo bool (%T) %v", t, t)
+	if s == "" {
+		*g = false
+		return nil
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		*g = Bool(b)
+		return nil
 	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		return fmt.Errorf("cannot parse gengo bool: %s", d)
+	}
+	*g = f != 0
 	return nil
 }
 
@@ -145,45 +125,83 @@ func (t Time) String() string {
 	return time.Time(t).String()
 }
 
-// MarshalJSON implements the Marshaler interface for Time.
-func (t *Time) MarshalJSON() ([]byte, error) {
-	if t == nil {
-		return []byte("null"), nil
+// MarshalJSON implements the Marshaler interface for Time. Times are UNIX seconds, with a fraction if they have one.
+func (t Time) MarshalJSON() ([]byte, error) {
+	sec, nsec := time.Time(t).Unix(), int64(time.Time(t).Nanosecond())
+	if nsec == 0 {
+		return []byte(strconv.FormatInt(sec, 10)), nil
+	}
+	sign := ""
+	if sec < 0 {
+		sign, sec, nsec = "-", -sec-1, 1e9-nsec
 	}
-	return []byte(fmt.Sprintf("%d", time.Time(*t).Unix())), nil
+	frac := strings.TrimRight(fmt.Sprintf("%09d", nsec), "0")
+	return []byte(fmt.Sprintf("%s%d.%s", sign, sec, frac)), nil
 }
 
-const (
-	postgresTimeFormat = "2006-01-02 15:04:05.000000"
-)
+// postgresTimeFormat is the layout Gengo usually sends times in, such as "2020-01-31 12:34:56.789012".
+const postgresTimeFormat = "2006-01-02 15:04:05"
+
+// timeFormats are the layouts Time parses strings with. Fractional seconds are optional in each.
+// Times without a time zone are UTC.
+var timeFormats = []string{
+	postgresTimeFormat,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -0700 MST",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z0700",
+}
 
-// UnmarshalJSON implements the Unmarshaler interface for Time.
+// UnmarshalJSON implements the Unmarshaler interface for Time. An empty string is the zero time.
 func (t *Time) UnmarshalJSON(d []byte) error {
-	var f interface{}
-	err := json.Unmarshal(d, &f)
-	if err != nil {
+	s, null, err := scalar(d, "time")
+	if err != nil || null {
 		return err
 	}
-	switch pt := f.(type) {
-	case string:
-		f, err := time.Parse(postgresTimeFormat, pt)
-		if err != nil {
-			i, err := strconv.Atoi(pt)
-			if err != nil {
-				return fmt.Errorf("cannot parse gengo string to time: %s", t)
-			}
-			*t = Time(time.Unix(int64(i), 0))
-			return nil
-		}
-		*t = Time(f)
-	case int:
-		*t = Time(time.Unix(int64(pt), 0))
+	if s == "" {
+		*t = Time{}
 		return nil
-	case float64:
-		*t = Time(time.Unix(int64(pt), 0))
+	}
+	if u, ok := parseUnix(s); ok {
+		*t = Time(u)
 		return nil
-	default:
-		return fmt.Errorf("unknown type for gengo time (%T) %v", pt, pt)
 	}
-	return nil
+	for _, layout := range timeFormats {
+		if pt, err := time.Parse(layout, s); err == nil {
+			*t = Time(pt)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot parse gengo string to time: %s", d)
+}
+
+// maxUnix bounds UNIX seconds to times time.Time can represent.
+const maxUnix = 1 << 62
+
+// parseUnix parses UNIX seconds, keeping up to nanoseconds of any fraction exactly.
+func parseUnix(s string) (time.Time, bool) {
+	digits := strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" || strings.Trim(whole+frac, "0123456789") != "" {
+		if f, err := strconv.ParseFloat(s, 64); err == nil && math.Abs(f) < maxUnix {
+			sec, fsec := math.Modf(f)
+			return time.Unix(int64(sec), int64(math.Round(fsec*1e9))), true
+		}
+		return time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || sec >= maxUnix {
+		return time.Time{}, false
+	}
+	if len(frac) > 9 {
+		frac = frac[:9]
+	}
+	nsec, _ := strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+	if len(digits) < len(s) {
+		sec, nsec = -sec, -nsec
+	}
+	return time.Unix(sec, nsec), true
 }
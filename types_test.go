@@ -0,0 +1,209 @@
+package gengo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFloat64(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Float64
+		wantErr bool
+	}{
+		{`"12.50"`, 12.5, false},
+		{`12.5`, 12.5, false},
+		{`"0.0525"`, 0.0525, false},
+		{`" 3 "`, 3, false},
+		{`""`, 0, false},
+		{`null`, 7, false},
+		{`"NaN"`, 7, true},
+		{`"abc"`, 7, true},
+		{`true`, 7, true},
+		{`{}`, 7, true},
+	}
+	for _, tt := range tests {
+		g := Float64(7)
+		err := json.Unmarshal([]byte(tt.in), &g)
+		if (err != nil) != tt.wantErr || g != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", tt.in, g, err, tt.want)
+		}
+	}
+	if b, _ := json.Marshal(Float64(0.0525)); string(b) != `"0.0525"` {
+		t.Errorf("Marshal(0.0525) = %s", b)
+	}
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Int
+		wantErr bool
+	}{
+		{`"12"`, 12, false},
+		{`12`, 12, false},
+		{`"12.0"`, 12, false},
+		{`12.9`, 12, false},
+		{`"-3"`, -3, false},
+		{`1e3`, 1000, false},
+		{`""`, 0, false},
+		{`null`, 7, false},
+		{`"1e30"`, 7, true},
+		{`"twelve"`, 7, true},
+		{`[1]`, 7, true},
+	}
+	for _, tt := range tests {
+		g := Int(7)
+		err := json.Unmarshal([]byte(tt.in), &g)
+		if (err != nil) != tt.wantErr || g != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", tt.in, g, err, tt.want)
+		}
+	}
+}
+
+func TestBool(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Bool
+		wantErr bool
+	}{
+		{`true`, true, false},
+		{`"false"`, false, false},
+		{`"1"`, true, false},
+		{`"0"`, false, false},
+		{`2`, true, false},
+		{`0`, false, false},
+		{`""`, false, false},
+		{`null`, true, false},
+		{`"yes"`, true, true},
+	}
+	for _, tt := range tests {
+		g := Bool(true)
+		err := json.Unmarshal([]byte(tt.in), &g)
+		if (err != nil) != tt.wantErr || g != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", tt.in, g, err, tt.want)
+		}
+	}
+}
+
+func TestTime(t *testing.T) {
+	utc := func(nsec int) time.Time {
+		return time.Date(2020, 1, 31, 12, 34, 56, nsec, time.UTC)
+	}
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{`"2020-01-31 12:34:56.789012"`, utc(789012000), false},
+		{`"2020-01-31 12:34:56"`, utc(0), false},
+		{`"2020-01-31 21:34:56+09"`, utc(0), false},
+		{`"2020-01-31 21:34:56.5+09:00"`, utc(5e8), false},
+		{`"2020-01-31 12:34:56 +0000"`, utc(0), false},
+		{`"2020-01-31T12:34:56Z"`, utc(0), false},
+		{`"2020-01-31T13:34:56.25+01:00"`, utc(25e7), false},
+		{`"2020-01-31T12:34:56"`, utc(0), false},
+		{`1580474096`, utc(0), false},
+		{`"1580474096"`, utc(0), false},
+		{`1580474096.5`, utc(5e8), false},
+		{`""`, time.Time{}, false},
+		{`null`, time.Unix(1, 0), false},
+		{`"yesterday"`, time.Unix(1, 0), true},
+		{`"99999999999999999999"`, time.Unix(1, 0), true},
+	}
+	for _, tt := range tests {
+		g := Time(time.Unix(1, 0))
+		err := json.Unmarshal([]byte(tt.in), &g)
+		if (err != nil) != tt.wantErr || !time.Time(g).Equal(tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", tt.in, g, err, tt.want)
+		}
+	}
+	if _, err := (new(Time)).MarshalJSON(); err != nil {
+		t.Error(err)
+	}
+	var g Time
+	if err := json.Unmarshal([]byte(`"yesterday"`), &g); err == nil || err.Error() != `cannot parse gengo string to time: "yesterday"` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestTypesMarshalSymmetry(t *testing.T) {
+	v := struct {
+		F  Float64
+		I  Int
+		B  Bool
+		T  Time
+		PT *Time
+	}{0.0525, -42, true, Time(time.Unix(-5, 5e8)), nil}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"F":"0.0525","I":"-42","B":"1","T":-4.5,"PT":null}` {
+		t.Errorf("Marshal() = %s", b)
+	}
+	w := v
+	w.T = Time{}
+	if err := json.Unmarshal(b, &w); err != nil {
+		t.Fatal(err)
+	}
+	if w.F != v.F || w.I != v.I || w.B != v.B || !time.Time(w.T).Equal(time.Time(v.T)) || w.PT != nil {
+		t.Errorf("round trip = %+v, want %+v", w, v)
+	}
+}
+
+// roundTrip checks that whatever v decodes from survives marshalling and decoding again.
+func roundTrip[T any](t *testing.T, in []byte, v, again *T, equal func(a, b T) bool) {
+	if err := json.Unmarshal(in, v); err != nil {
+		return
+	}
+	b, err := json.Marshal(*v)
+	if err != nil {
+		t.Fatalf("Marshal(%v) = %v", *v, err)
+	}
+	if err := json.Unmarshal(b, again); err != nil {
+		t.Fatalf("Unmarshal(%s) of Marshal(%v) = %v", b, *v, err)
+	}
+	if !equal(*v, *again) {
+		t.Fatalf("%s decoded to %v, marshalled to %s and decoded to %v", in, *v, b, *again)
+	}
+}
+
+func addSeeds(f *testing.F, seeds ...string) {
+	for _, s := range seeds {
+		f.Add([]byte(s))
+	}
+}
+
+func FuzzFloat64(f *testing.F) {
+	addSeeds(f, `"12.50"`, `12.5`, `"-0"`, `1e300`, `"0x1p-2"`, `null`)
+	f.Fuzz(func(t *testing.T, in []byte) {
+		var v, again Float64
+		roundTrip(t, in, &v, &again, func(a, b Float64) bool { return a == b })
+	})
+}
+
+func FuzzInt(f *testing.F) {
+	addSeeds(f, `"12"`, `"12.0"`, `-3`, `1e18`, `"9223372036854775807"`, `null`)
+	f.Fuzz(func(t *testing.T, in []byte) {
+		var v, again Int
+		roundTrip(t, in, &v, &again, func(a, b Int) bool { return a == b })
+	})
+}
+
+func FuzzBool(f *testing.F) {
+	addSeeds(f, `true`, `"0"`, `"T"`, `2.5`, `""`, `null`)
+	f.Fuzz(func(t *testing.T, in []byte) {
+		var v, again Bool
+		roundTrip(t, in, &v, &again, func(a, b Bool) bool { return a == b })
+	})
+}
+
+func FuzzTime(f *testing.F) {
+	addSeeds(f, `"2020-01-31 12:34:56.789012"`, `"2020-01-31T12:34:56+09:00"`, `1580474096`, `-4.5`, `"1.5e9"`, `null`)
+	f.Fuzz(func(t *testing.T, in []byte) {
+		var v, again Time
+		roundTrip(t, in, &v, &again, func(a, b Time) bool { return time.Time(a).Equal(time.Time(b)) })
+	})
+}